@@ -20,12 +20,12 @@ import (
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/config"
 	"golang.org/x/pkgsite/internal/dcensus"
+	"golang.org/x/pkgsite/internal/fetch"
 	"golang.org/x/pkgsite/internal/frontend"
 	"golang.org/x/pkgsite/internal/localdatasource"
 	"golang.org/x/pkgsite/internal/log"
 	"golang.org/x/pkgsite/internal/middleware"
 	"golang.org/x/pkgsite/internal/postgres"
-	"golang.org/x/pkgsite/internal/proxy"
 	"golang.org/x/pkgsite/internal/proxydatasource"
 	"golang.org/x/pkgsite/internal/queue"
 	"golang.org/x/pkgsite/internal/source"
@@ -44,7 +44,14 @@ var (
 		"as a direct backend, bypassing the database")
 	localPaths         = flag.String("local", "", "run locally, accepts a GOPATH-like collection of local paths for modules to load to memory")
 	gopathMode         = flag.Bool("gopath_mode", false, "assume that local modules' paths are relative to GOPATH/src, used only with -local")
+	watch              = flag.Bool("watch", false, "reload local modules when their files change and refresh open pages, used only with -local")
 	bypassLicenseCheck = flag.Bool("bypass_license_check", false, "display all information, even for non-redistributable paths")
+	playDir            = flag.String("play_dir", "", "if set, run and share examples locally instead of on play.golang.org, storing shared examples in this directory")
+	playGoCache        = flag.String("play_gocache", "", "build cache shared by the programs run with -play_dir")
+	playModCache       = flag.String("play_modcache", "", "module cache from which the programs run with -play_dir import modules")
+	playGoProxy        = flag.String("play_goproxy", "", "module proxy from which the programs run with -play_dir download modules; by default, only modules in the module cache can be imported")
+	playMaxSnippets    = flag.Int("play_max_snippets", 0, "maximum number of programs kept in -play_dir (default 10000)")
+	playMaxSnippetMB   = flag.Int64("play_max_snippet_mb", 0, "maximum total size in megabytes of the programs kept in -play_dir (default 100)")
 )
 
 func main() {
@@ -75,11 +82,16 @@ func main() {
 	expg := cmdconfig.ExperimentGetter(ctx, cfg)
 	log.Infof(ctx, "cmd/frontend: initialized cmdconfig.ExperimentGetter")
 
+	if err := fetch.SetBuildContexts(cmdconfig.BuildContexts(ctx, cfg)); err != nil {
+		log.Fatal(ctx, err)
+	}
+	fetch.SetDirectFetchPatterns(cfg.DirectFetchPatterns)
+
 	if *localPaths != "" {
 		lds := localdatasource.New()
 		dsg = func(context.Context) internal.DataSource { return lds }
 	} else {
-		proxyClient, err := proxy.New(*proxyURL)
+		proxyClient, err := cmdconfig.ProxyClient(ctx, cfg, *proxyURL)
 		if err != nil {
 			log.Fatal(ctx, err)
 		}
@@ -113,12 +125,32 @@ func main() {
 		}
 	}
 
+	var reloader *frontend.LiveReloader
+	if *localPaths != "" && *watch {
+		reloader = frontend.NewLiveReloader()
+	}
+
 	var haClient *redis.Client
 	if cfg.RedisHAHost != "" {
 		haClient = redis.NewClient(&redis.Options{
 			Addr: cfg.RedisHAHost + ":" + cfg.RedisHAPort,
 		})
 	}
+	var playground frontend.PlaygroundBackend
+	if *playDir != "" {
+		playground, err = frontend.NewLocalPlayground(frontend.LocalPlaygroundConfig{
+			SnippetDir: *playDir,
+			BuildCache: *playGoCache,
+			ModCache:   *playModCache,
+			GOPROXY:    *playGoProxy,
+
+			MaxSnippets:     *playMaxSnippets,
+			MaxSnippetBytes: *playMaxSnippetMB << 20,
+		})
+		if err != nil {
+			log.Fatal(ctx, err)
+		}
+	}
 	server, err := frontend.NewServer(frontend.ServerConfig{
 		DataSourceGetter:     dsg,
 		Queue:                fetchQueue,
@@ -130,6 +162,8 @@ func main() {
 		AppVersionLabel:      cfg.AppVersionLabel(),
 		GoogleTagManagerID:   cfg.GoogleTagManagerID,
 		ServeStats:           cfg.ServeStats,
+		LiveReload:           reloader != nil,
+		Playground:           playground,
 	})
 	if err != nil {
 		log.Fatalf(ctx, "frontend.NewServer: %v", err)
@@ -139,6 +173,9 @@ func main() {
 		lds, ok := dsg(ctx).(*localdatasource.DataSource)
 		if ok {
 			load(ctx, lds, *localPaths)
+			if reloader != nil {
+				go lds.Watch(ctx, time.Second, reloader.Reload)
+			}
 		}
 	}
 
@@ -199,9 +236,18 @@ func main() {
 		ermw,
 		middleware.Timeout(54*time.Second),
 	)
+	handler := mw(router)
+	if reloader != nil {
+		// Serve live reload events outside the middleware, which buffers
+		// responses.
+		mux := http.NewServeMux()
+		mux.Handle(frontend.LiveReloadPath, reloader)
+		mux.Handle("/", handler)
+		handler = mux
+	}
 	addr := cfg.HostAddr("localhost:8080")
 	log.Infof(ctx, "Listening on addr %s", addr)
-	log.Fatal(ctx, http.ListenAndServe(addr, mw(router)))
+	log.Fatal(ctx, http.ListenAndServe(addr, handler))
 }
 
 // load loads local modules from pathList.
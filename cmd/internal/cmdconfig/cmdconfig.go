@@ -22,6 +22,7 @@ import (
 	"golang.org/x/pkgsite/internal/log"
 	"golang.org/x/pkgsite/internal/middleware"
 	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/proxy"
 )
 
 // Logger configures a middleware.Logger.
@@ -92,6 +93,67 @@ func ExperimentGetter(ctx context.Context, cfg *config.Config) middleware.Experi
 	}
 }
 
+// BuildContexts returns the build contexts used to process packages. Those in
+// the dynamic config take precedence over those in cfg; if neither has any,
+// internal.DefaultBuildContexts is returned.
+func BuildContexts(ctx context.Context, cfg *config.Config) []internal.BuildContext {
+	if cfg.DynamicConfigLocation != "" {
+		dc, err := dynconfig.Read(ctx, cfg.DynamicConfigLocation)
+		if err != nil {
+			log.Fatal(ctx, err)
+		}
+		if len(dc.BuildContexts) > 0 {
+			return dc.BuildContexts
+		}
+	}
+	if len(cfg.BuildContexts) == 0 {
+		return internal.DefaultBuildContexts
+	}
+	var bcs []internal.BuildContext
+	for _, s := range cfg.BuildContexts {
+		bc, err := internal.ParseBuildContext(s)
+		if err != nil {
+			log.Fatal(ctx, err)
+		}
+		bcs = append(bcs, bc)
+	}
+	return bcs
+}
+
+// ProxyClient returns a client for the proxy at proxyURL that follows the
+// proxy routes in cfg. Proxies without a token use the credentials for their
+// host in cfg.NetrcFile, if there are any.
+func ProxyClient(ctx context.Context, cfg *config.Config, proxyURL string) (_ *proxy.Client, err error) {
+	defer derrors.Wrap(&err, "cmdconfig.ProxyClient(ctx, cfg)")
+
+	var netrc map[string]*proxy.Credentials
+	if cfg.NetrcFile != "" {
+		netrc, err = proxy.ReadNetrc(cfg.NetrcFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	defaultCreds, err := proxy.NetrcCredentials(netrc, proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	var routes []*proxy.Route
+	for _, r := range cfg.ProxyRoutes {
+		route := &proxy.Route{Patterns: r.Patterns, URL: r.URL}
+		if r.Token != "" {
+			route.Credentials = &proxy.Credentials{Token: r.Token}
+		} else {
+			route.Credentials, err = proxy.NetrcCredentials(netrc, r.URL)
+			if err != nil {
+				return nil, err
+			}
+		}
+		log.Infof(ctx, "proxy route: %s => %s (credentials: %s)", r.Patterns, r.URL, route.Credentials)
+		routes = append(routes, route)
+	}
+	return proxy.NewWithRoutes(proxyURL, defaultCreds, routes)
+}
+
 // OpenDB opens the postgres database specified by the config.
 // It first tries the main connection info (DBConnInfo), and if that fails, it uses backup
 // connection info it if exists (DBSecondaryConnInfo).
@@ -0,0 +1,194 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// The pkgdoc command prints the documentation of a package or symbol to the
+// terminal, as pkg.go.dev would show it: it loads modules the same way as the
+// frontend, and follows the same build context and license rules.
+//
+// Usage:
+//
+//   pkgdoc [flags] <package>[.<symbol>[.<method>]]
+//   pkgdoc [flags] <package>[@<version>] [<symbol>[.<method>]]
+//
+// Modules are downloaded from the proxy, or, with -local, loaded from local
+// directories. Like the frontend, pkgdoc reads its build contexts and proxy
+// routes from the environment (GO_DISCOVERY_BUILD_CONTEXTS and
+// GO_DISCOVERY_PROXY_ROUTES).
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/safehtml/template"
+	"golang.org/x/pkgsite/cmd/internal/cmdconfig"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/config"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/fetch"
+	"golang.org/x/pkgsite/internal/godoc"
+	"golang.org/x/pkgsite/internal/godoc/dochtml"
+	"golang.org/x/pkgsite/internal/godoc/doctext"
+	"golang.org/x/pkgsite/internal/localdatasource"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/proxydatasource"
+	"golang.org/x/pkgsite/internal/stdlib"
+)
+
+var (
+	// flag used in call to safehtml/template.TrustedSourceFromFlag
+	_                  = flag.String("static", "content/static", "path to folder containing static files, for the documentation templates")
+	proxyURL           = flag.String("proxy_url", "https://proxy.golang.org", "module proxy to download modules from")
+	localPaths         = flag.String("local", "", "load modules from these local directories, a GOPATH-like list, instead of the proxy")
+	goos               = flag.String("goos", "", "show the documentation for this GOOS")
+	goarch             = flag.String("goarch", "", "show the documentation for this GOARCH")
+	color              = flag.Bool("color", false, "highlight headings and declarations with ANSI escape sequences")
+	bypassLicenseCheck = flag.Bool("bypass_license_check", false, "display all information, even for non-redistributable paths")
+)
+
+func main() {
+	flag.Usage = func() {
+		out := flag.CommandLine.Output()
+		fmt.Fprintf(out, "usage: %s [flags] <package>[.<symbol>[.<method>]]\n", os.Args[0])
+		fmt.Fprintf(out, "       %s [flags] <package>[@<version>] [<symbol>[.<method>]]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 1 || flag.NArg() > 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	ctx := context.Background()
+	if err := run(ctx, flag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "pkgdoc: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	// Loading modules renders their documentation, so the templates must be
+	// loaded first.
+	staticPath := template.TrustedSourceFromFlag(flag.Lookup("static").Value)
+	dochtml.LoadTemplates(template.TrustedSourceJoin(staticPath, template.TrustedSourceFromConstant("html/doc")))
+	cfg, err := config.Init(ctx)
+	if err != nil {
+		return err
+	}
+	log.SetLevel(cfg.LogLevel)
+	if err := fetch.SetBuildContexts(cmdconfig.BuildContexts(ctx, cfg)); err != nil {
+		return err
+	}
+	ds, err := dataSource(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	um, symbol, err := resolve(ctx, ds, args)
+	if err != nil {
+		return err
+	}
+	if !um.IsPackage() {
+		return fmt.Errorf("%s is not a package", um.Path)
+	}
+	bc := internal.BuildContext{GOOS: *goos, GOARCH: *goarch}
+	u, err := ds.GetUnit(ctx, um, internal.WithMain, bc)
+	if err != nil {
+		return err
+	}
+	if !u.IsRedistributable {
+		return fmt.Errorf("documentation for %s not displayed due to license restrictions; see https://pkg.go.dev/license-policy", u.Path)
+	}
+	if len(u.Documentation) == 0 {
+		if len(u.BuildContexts) > 0 {
+			var names []string
+			for _, bc := range u.BuildContexts {
+				names = append(names, bc.String())
+			}
+			return fmt.Errorf("no documentation for GOOS/GOARCH %s/%s; available: %s", orAny(bc.GOOS), orAny(bc.GOARCH), strings.Join(names, ", "))
+		}
+		return fmt.Errorf("no documentation for %s", u.Path)
+	}
+	docPkg, err := godoc.DecodePackage(u.Documentation[0].Source)
+	if err != nil {
+		return err
+	}
+	modInfo := &godoc.ModuleInfo{ModulePath: u.ModulePath, ResolvedVersion: u.Version}
+	var innerPath string
+	if u.ModulePath == stdlib.ModulePath {
+		innerPath = u.Path
+	} else if u.Path != u.ModulePath {
+		innerPath = u.Path[len(u.ModulePath)+1:]
+	}
+	return docPkg.RenderText(ctx, os.Stdout, innerPath, modInfo, doctext.Options{Symbol: symbol, Styled: *color})
+}
+
+// dataSource returns the data source to load packages from, constructed as
+// by the frontend for the same flags and configuration.
+func dataSource(ctx context.Context, cfg *config.Config) (internal.DataSource, error) {
+	if *localPaths != "" {
+		lds := localdatasource.New()
+		for _, path := range filepath.SplitList(*localPaths) {
+			if err := lds.Load(ctx, path); err != nil {
+				return nil, err
+			}
+		}
+		return lds, nil
+	}
+	proxyClient, err := cmdconfig.ProxyClient(ctx, cfg, *proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	if *bypassLicenseCheck {
+		return proxydatasource.NewBypassingLicenseCheck(proxyClient), nil
+	}
+	return proxydatasource.New(proxyClient), nil
+}
+
+// resolve returns the package and symbol named by the command-line
+// arguments. If there is a single argument without a version, the symbol may
+// follow the package path after a dot, so each way of splitting the last
+// path element at a dot is tried, starting with the whole argument.
+func resolve(ctx context.Context, ds internal.DataSource, args []string) (_ *internal.UnitMeta, symbol string, err error) {
+	defer derrors.Wrap(&err, "resolve(%q)", args)
+
+	arg, version := args[0], internal.LatestVersion
+	if len(args) == 2 {
+		symbol = args[1]
+	}
+	if i := strings.IndexByte(arg, '@'); i >= 0 {
+		arg, version = arg[:i], arg[i+1:]
+	}
+	candidates := [][2]string{{arg, symbol}}
+	if len(args) == 1 && version == internal.LatestVersion {
+		// A symbol has at most two parts, as in "Type.Method".
+		last := strings.LastIndexByte(arg, '/') + 1
+		for i, dots := len(arg)-1, 0; i > last && dots < 2; i-- {
+			if arg[i] == '.' {
+				candidates = append(candidates, [2]string{arg[:i], arg[i+1:]})
+				dots++
+			}
+		}
+	}
+	for _, c := range candidates {
+		um, err := ds.GetUnitMeta(ctx, c[0], internal.UnknownModulePath, version)
+		if err == nil && um.IsPackage() {
+			return um, c[1], nil
+		}
+		if err != nil && !errors.Is(err, derrors.NotFound) {
+			return nil, "", err
+		}
+	}
+	return nil, "", fmt.Errorf("no package %s: %w", arg, derrors.NotFound)
+}
+
+func orAny(s string) string {
+	if s == "" {
+		return "any"
+	}
+	return s
+}
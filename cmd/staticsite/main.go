@@ -0,0 +1,140 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// The staticsite command writes the documentation of modules as a static
+// HTML site, which can be served by a plain file server or shipped with a
+// release, without running the frontend or a database.
+//
+// Usage:
+//
+//   staticsite -out DIR [flags] MODULE...
+//
+// MODULE is a local module directory, a module zip file (ending in .zip), or
+// a module path with an optional @version, which is downloaded from the proxy.
+// With several modules, each is written to the directory named by its module
+// path. With -docset NAME, the site is written as a docset for the Dash and
+// Zeal documentation browsers, NAME.docset, with an index of packages and
+// symbols.
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/safehtml/template"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/docset"
+	"golang.org/x/pkgsite/internal/fetch"
+	"golang.org/x/pkgsite/internal/godoc/dochtml"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/proxy"
+	"golang.org/x/pkgsite/internal/source"
+	"golang.org/x/pkgsite/internal/staticsite"
+)
+
+var (
+	outDir = flag.String("out", "", "directory to write the site to")
+	// flag used in call to safehtml/template.TrustedSourceFromFlag
+	_                  = flag.String("static", "content/static", "path to folder containing templates and static files")
+	proxyURL           = flag.String("proxy_url", "https://proxy.golang.org", "module proxy to download modules from")
+	externalURL        = flag.String("external_url", staticsite.DefaultExternalURL, "site to link packages outside the module to")
+	bypassLicenseCheck = flag.Bool("bypass_license_check", false, "display all information, even for non-redistributable paths")
+	docsetName         = flag.String("docset", "", "write a Dash docset with this name in the output directory")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s -out DIR [flags] MODULE...\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "MODULE is a directory, a module zip file, or a module path with an optional @version.\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	ctx := context.Background()
+	if flag.NArg() == 0 || *outDir == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	staticPath := template.TrustedSourceFromFlag(flag.Lookup("static").Value)
+	// Fetching renders documentation, so the templates must be loaded first.
+	dochtml.LoadTemplates(template.TrustedSourceJoin(staticPath, template.TrustedSourceFromConstant("html/doc")))
+	var modules []*internal.Module
+	for _, arg := range flag.Args() {
+		res := fetchModule(ctx, arg)
+		defer res.Defer()
+		if res.Module == nil {
+			log.Fatal(ctx, res.Error)
+		}
+		if res.Error != nil {
+			// The module can still be documented, without the packages that
+			// could not be loaded.
+			log.Warning(ctx, res.Error)
+		}
+		modules = append(modules, res.Module)
+	}
+	if *docsetName != "" {
+		dir, err := docset.Write(ctx, modules, docset.Config{
+			Name:               *docsetName,
+			OutDir:             *outDir,
+			StaticPath:         staticPath,
+			ExternalURL:        *externalURL,
+			BypassLicenseCheck: *bypassLicenseCheck,
+		})
+		if err != nil {
+			log.Fatal(ctx, err)
+		}
+		log.Infof(ctx, "wrote docset for %d modules to %s", len(modules), dir)
+		return
+	}
+	cfg := staticsite.Config{
+		OutDir:             *outDir,
+		StaticPath:         staticPath,
+		ExternalURL:        *externalURL,
+		BypassLicenseCheck: *bypassLicenseCheck,
+	}
+	var err error
+	if len(modules) == 1 {
+		err = staticsite.Generate(ctx, modules[0], cfg)
+	} else {
+		err = staticsite.GenerateModules(ctx, modules, cfg)
+	}
+	if err != nil {
+		log.Fatal(ctx, err)
+	}
+	log.Infof(ctx, "wrote documentation for %d modules to %s", len(modules), *outDir)
+}
+
+// fetchModule fetches the module described by arg: a local directory, a
+// module zip file, or a module path with an optional version.
+func fetchModule(ctx context.Context, arg string) *fetch.FetchResult {
+	sourceClient := source.NewClient(10 * time.Second)
+	if info, err := os.Stat(arg); err == nil {
+		if info.IsDir() {
+			return fetch.FetchLocalModule(ctx, "", arg, sourceClient)
+		}
+		if strings.HasSuffix(arg, ".zip") {
+			r, err := zip.OpenReader(arg)
+			if err != nil {
+				log.Fatal(ctx, err)
+			}
+			res := fetch.FetchZipModule(ctx, &r.Reader, sourceClient)
+			res.Defer = func() { r.Close() }
+			return res
+		}
+	}
+	modulePath, version := arg, internal.LatestVersion
+	if i := strings.Index(arg, "@"); i >= 0 {
+		modulePath, version = arg[:i], arg[i+1:]
+	}
+	proxyClient, err := proxy.New(*proxyURL)
+	if err != nil {
+		log.Fatal(ctx, err)
+	}
+	return fetch.FetchModule(ctx, modulePath, version, proxyClient, sourceClient, false)
+}
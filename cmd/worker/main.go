@@ -27,7 +27,6 @@ import (
 	"golang.org/x/pkgsite/internal/log"
 	"golang.org/x/pkgsite/internal/middleware"
 	"golang.org/x/pkgsite/internal/postgres"
-	"golang.org/x/pkgsite/internal/proxy"
 	"golang.org/x/pkgsite/internal/queue"
 	"golang.org/x/pkgsite/internal/source"
 	"golang.org/x/pkgsite/internal/worker"
@@ -63,6 +62,11 @@ func main() {
 
 	readProxyRemoved(ctx)
 
+	if err := fetch.SetBuildContexts(cmdconfig.BuildContexts(ctx, cfg)); err != nil {
+		log.Fatal(ctx, err)
+	}
+	fetch.SetDirectFetchPatterns(cfg.DirectFetchPatterns)
+
 	db, err := cmdconfig.OpenDB(ctx, cfg, *bypassLicenseCheck)
 	if err != nil {
 		log.Fatalf(ctx, "%v", err)
@@ -75,7 +79,7 @@ func main() {
 	if err != nil {
 		log.Fatal(ctx, err)
 	}
-	proxyClient, err := proxy.New(cfg.ProxyURL)
+	proxyClient, err := cmdconfig.ProxyClient(ctx, cfg, cfg.ProxyURL)
 	if err != nil {
 		log.Fatal(ctx, err)
 	}
@@ -0,0 +1,95 @@
+/**
+ * @license
+ * Copyright 2021 The Go Authors. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+// This file suggests packages as the user types in the search box, using
+// third_party/autoComplete.js and the /autocomplete endpoint of the server,
+// which matches the query against package paths and names. Choosing a
+// suggestion goes to the package page.
+
+// The CSS is in content/static/css/stylesheet.css.
+
+/* global autoComplete */
+
+const completionInput = document.querySelector('.js-autocomplete');
+if (completionInput && completionInput.form) {
+  completionInput.form.classList.add('AutoComplete-form');
+  const completion = new autoComplete({
+    selector: () => completionInput,
+    data: {
+      src: fetchSuggestions,
+      key: ['packagePath'],
+      cache: false,
+    },
+    // The server has already matched the suggestions against the query.
+    searchEngine: (query, record) => record,
+    // Shorter queries match too many packages; the server returns no
+    // suggestions for them unless they contain a slash.
+    threshold: 3,
+    debounce: 100,
+    maxResults: 10,
+    resultsList: {
+      render: true,
+      container: list => list.classList.add('AutoComplete-list'),
+      destination: completionInput.form,
+      position: 'beforeend',
+    },
+    resultItem: {
+      content: renderSuggestion,
+    },
+    onSelection: feedback => {
+      if (!feedback.selection) {
+        return;
+      }
+      // Go to the package instead of submitting the search.
+      feedback.event.preventDefault();
+      window.location.href = `/${feedback.selection.value.packagePath}`;
+    },
+  });
+  completionInput.addEventListener('blur', () => {
+    completion.resultsList.view.innerHTML = '';
+  });
+}
+
+/**
+ * fetchSuggestions returns the suggestions for the current query, or none if
+ * they are not available.
+ * @return {Promise<Array<Object>>}
+ */
+async function fetchSuggestions() {
+  const q = completionInput.value.trim();
+  if (!q) {
+    return [];
+  }
+  try {
+    const response = await fetch(`/autocomplete?q=${encodeURIComponent(q)}`);
+    if (!response.ok) {
+      return [];
+    }
+    return await response.json();
+  } catch (e) {
+    return [];
+  }
+}
+
+/**
+ * renderSuggestion fills the list item li with the package path and synopsis
+ * of a suggestion. Text is inserted as text, never as HTML.
+ * @param {Object} data the suggestion, as passed by autoComplete
+ * @param {HTMLElement} li
+ */
+function renderSuggestion(data, li) {
+  const path = document.createElement('span');
+  path.className = 'AutoComplete-path';
+  path.textContent = data.value.packagePath;
+  li.appendChild(path);
+  if (data.value.synopsis) {
+    const synopsis = document.createElement('span');
+    synopsis.className = 'AutoComplete-synopsis';
+    synopsis.textContent = data.value.synopsis;
+    li.appendChild(synopsis);
+  }
+}
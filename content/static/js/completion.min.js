@@ -0,0 +1,44 @@
+/*
+
+ Copyright 2021 The Go Authors. All rights reserved.
+ Use of this source code is governed by a BSD-style
+ license that can be found in the LICENSE file.
+*/
+var $jscomp=$jscomp||{};$jscomp.scope={};$jscomp.findInternal=function(a,b,c){a instanceof String&&(a=String(a));for(var d=a.length,e=0;e<d;e++){var f=a[e];if(b.call(c,f,e,a))return{i:e,v:f}}return{i:-1,v:void 0}};$jscomp.checkStringArgs=function(b,c,d){if(null==b)throw new TypeError("The 'this' value for String.prototype."+d+" must not be null or undefined");if(c instanceof RegExp)throw new TypeError("First argument to String.prototype."+d+" must not be a regular expression");return b+""};$jscomp.createTemplateTagFirstArg=function(a){return a.raw=a};$jscomp.createTemplateTagFirstArgWithRaw=function(a,b){a.raw=b;return a};$jscomp.ASSUME_ES5=!1;$jscomp.ASSUME_NO_NATIVE_MAP=!1;$jscomp.ASSUME_NO_NATIVE_SET=!1;$jscomp.SIMPLE_FROUND_POLYFILL=!1;$jscomp.ISOLATE_POLYFILLS=!1;
+$jscomp.defineProperty=$jscomp.ASSUME_ES5||"function"==typeof Object.defineProperties?Object.defineProperty:function(a,b,e){if(a==Array.prototype||a==Object.prototype)return a;a[b]=e.value;return a};$jscomp.getGlobal=function(a){a=["object"==typeof globalThis&&globalThis,a,"object"==typeof window&&window,"object"==typeof self&&self,"object"==typeof global&&global];for(var b=0;b<a.length;++b){var e=a[b];if(e&&e.Math==Math)return e}throw Error("Cannot find global object");};$jscomp.global=$jscomp.getGlobal(this);
+$jscomp.IS_SYMBOL_NATIVE="function"===typeof Symbol&&"symbol"===typeof Symbol("x");$jscomp.TRUST_ES6_POLYFILLS=!$jscomp.ISOLATE_POLYFILLS||$jscomp.IS_SYMBOL_NATIVE;$jscomp.polyfills={};$jscomp.propertyToPolyfillSymbol={};$jscomp.POLYFILL_PREFIX="$jscp$";var $jscomp$lookupPolyfilledValue=function(a,b){var e=$jscomp.propertyToPolyfillSymbol[b];if(null==e)return a[b];e=a[e];return void 0!==e?e:a[b]};
+$jscomp.polyfill=function(a,b,e,f){b&&($jscomp.ISOLATE_POLYFILLS?$jscomp.polyfillIsolated(a,b,e,f):$jscomp.polyfillUnisolated(a,b,e,f))};$jscomp.polyfillUnisolated=function(a,b,e,f){e=$jscomp.global;a=a.split(".");for(f=0;f<a.length-1;f++){var d=a[f];if(!(d in e))return;e=e[d]}a=a[a.length-1];f=e[a];b=b(f);b!=f&&null!=b&&$jscomp.defineProperty(e,a,{configurable:!0,writable:!0,value:b})};
+$jscomp.polyfillIsolated=function(a,b,e,f){var d=a.split(".");a=1===d.length;f=d[0];f=!a&&f in $jscomp.polyfills?$jscomp.polyfills:$jscomp.global;for(var l=0;l<d.length-1;l++){var c=d[l];if(!(c in f))return;f=f[c]}d=d[d.length-1];e=$jscomp.IS_SYMBOL_NATIVE&&"es6"===e?f[d]:null;b=b(e);null!=b&&(a?$jscomp.defineProperty($jscomp.polyfills,d,{configurable:!0,writable:!0,value:b}):b!==e&&($jscomp.propertyToPolyfillSymbol[d]=$jscomp.IS_SYMBOL_NATIVE?$jscomp.global.Symbol(d):$jscomp.POLYFILL_PREFIX+d,d=
+$jscomp.propertyToPolyfillSymbol[d],$jscomp.defineProperty(f,d,{configurable:!0,writable:!0,value:b})))};$jscomp.underscoreProtoCanBeSet=function(){var a={a:!0},b={};try{return b.__proto__=a,b.a}catch(e){}return!1};$jscomp.setPrototypeOf=$jscomp.TRUST_ES6_POLYFILLS&&"function"==typeof Object.setPrototypeOf?Object.setPrototypeOf:$jscomp.underscoreProtoCanBeSet()?function(a,b){a.__proto__=b;if(a.__proto__!==b)throw new TypeError(a+" is not extensible");return a}:null;
+$jscomp.arrayIteratorImpl=function(a){var b=0;return function(){return b<a.length?{done:!1,value:a[b++]}:{done:!0}}};$jscomp.arrayIterator=function(a){return{next:$jscomp.arrayIteratorImpl(a)}};$jscomp.makeIterator=function(a){var b="undefined"!=typeof Symbol&&Symbol.iterator&&a[Symbol.iterator];return b?b.call(a):$jscomp.arrayIterator(a)};$jscomp.generator={};
+$jscomp.generator.ensureIteratorResultIsObject_=function(a){if(!(a instanceof Object))throw new TypeError("Iterator result "+a+" is not an object");};$jscomp.generator.Context=function(){this.isRunning_=!1;this.yieldAllIterator_=null;this.yieldResult=void 0;this.nextAddress=1;this.finallyAddress_=this.catchAddress_=0;this.finallyContexts_=this.abruptCompletion_=null};
+$jscomp.generator.Context.prototype.start_=function(){if(this.isRunning_)throw new TypeError("Generator is already running");this.isRunning_=!0};$jscomp.generator.Context.prototype.stop_=function(){this.isRunning_=!1};$jscomp.generator.Context.prototype.jumpToErrorHandler_=function(){this.nextAddress=this.catchAddress_||this.finallyAddress_};$jscomp.generator.Context.prototype.next_=function(a){this.yieldResult=a};
+$jscomp.generator.Context.prototype.throw_=function(a){this.abruptCompletion_={exception:a,isException:!0};this.jumpToErrorHandler_()};$jscomp.generator.Context.prototype.return=function(a){this.abruptCompletion_={return:a};this.nextAddress=this.finallyAddress_};$jscomp.generator.Context.prototype.jumpThroughFinallyBlocks=function(a){this.abruptCompletion_={jumpTo:a};this.nextAddress=this.finallyAddress_};$jscomp.generator.Context.prototype.yield=function(a,b){this.nextAddress=b;return{value:a}};
+$jscomp.generator.Context.prototype.yieldAll=function(a,b){a=$jscomp.makeIterator(a);var e=a.next();$jscomp.generator.ensureIteratorResultIsObject_(e);if(e.done)this.yieldResult=e.value,this.nextAddress=b;else return this.yieldAllIterator_=a,this.yield(e.value,b)};$jscomp.generator.Context.prototype.jumpTo=function(a){this.nextAddress=a};$jscomp.generator.Context.prototype.jumpToEnd=function(){this.nextAddress=0};
+$jscomp.generator.Context.prototype.setCatchFinallyBlocks=function(a,b){this.catchAddress_=a;void 0!=b&&(this.finallyAddress_=b)};$jscomp.generator.Context.prototype.setFinallyBlock=function(a){this.catchAddress_=0;this.finallyAddress_=a||0};$jscomp.generator.Context.prototype.leaveTryBlock=function(a,b){this.nextAddress=a;this.catchAddress_=b||0};
+$jscomp.generator.Context.prototype.enterCatchBlock=function(a){this.catchAddress_=a||0;a=this.abruptCompletion_.exception;this.abruptCompletion_=null;return a};$jscomp.generator.Context.prototype.enterFinallyBlock=function(a,b,e){e?this.finallyContexts_[e]=this.abruptCompletion_:this.finallyContexts_=[this.abruptCompletion_];this.catchAddress_=a||0;this.finallyAddress_=b||0};
+$jscomp.generator.Context.prototype.leaveFinallyBlock=function(a,b){b=this.finallyContexts_.splice(b||0)[0];if(b=this.abruptCompletion_=this.abruptCompletion_||b){if(b.isException)return this.jumpToErrorHandler_();void 0!=b.jumpTo&&this.finallyAddress_<b.jumpTo?(this.nextAddress=b.jumpTo,this.abruptCompletion_=null):this.nextAddress=this.finallyAddress_}else this.nextAddress=a};$jscomp.generator.Context.prototype.forIn=function(a){return new $jscomp.generator.Context.PropertyIterator(a)};
+$jscomp.generator.Context.PropertyIterator=function(a){this.object_=a;this.properties_=[];for(var b in a)this.properties_.push(b);this.properties_.reverse()};$jscomp.generator.Context.PropertyIterator.prototype.getNext=function(){for(;0<this.properties_.length;){var a=this.properties_.pop();if(a in this.object_)return a}return null};$jscomp.generator.Engine_=function(a){this.context_=new $jscomp.generator.Context;this.program_=a};
+$jscomp.generator.Engine_.prototype.next_=function(a){this.context_.start_();if(this.context_.yieldAllIterator_)return this.yieldAllStep_(this.context_.yieldAllIterator_.next,a,this.context_.next_);this.context_.next_(a);return this.nextStep_()};
+$jscomp.generator.Engine_.prototype.return_=function(a){this.context_.start_();var b=this.context_.yieldAllIterator_;if(b)return this.yieldAllStep_("return"in b?b["return"]:function(e){return{value:e,done:!0}},a,this.context_.return);this.context_.return(a);return this.nextStep_()};
+$jscomp.generator.Engine_.prototype.throw_=function(a){this.context_.start_();if(this.context_.yieldAllIterator_)return this.yieldAllStep_(this.context_.yieldAllIterator_["throw"],a,this.context_.next_);this.context_.throw_(a);return this.nextStep_()};
+$jscomp.generator.Engine_.prototype.yieldAllStep_=function(a,b,e){try{var f=a.call(this.context_.yieldAllIterator_,b);$jscomp.generator.ensureIteratorResultIsObject_(f);if(!f.done)return this.context_.stop_(),f;var d=f.value}catch(l){return this.context_.yieldAllIterator_=null,this.context_.throw_(l),this.nextStep_()}this.context_.yieldAllIterator_=null;e.call(this.context_,d);return this.nextStep_()};
+$jscomp.generator.Engine_.prototype.nextStep_=function(){for(;this.context_.nextAddress;)try{var a=this.program_(this.context_);if(a)return this.context_.stop_(),{value:a.value,done:!1}}catch(b){this.context_.yieldResult=void 0,this.context_.throw_(b)}this.context_.stop_();if(this.context_.abruptCompletion_){a=this.context_.abruptCompletion_;this.context_.abruptCompletion_=null;if(a.isException)throw a.exception;return{value:a.return,done:!0}}return{value:void 0,done:!0}};
+$jscomp.generator.Generator_=function(a){this.next=function(b){return a.next_(b)};this.throw=function(b){return a.throw_(b)};this.return=function(b){return a.return_(b)};this[Symbol.iterator]=function(){return this}};$jscomp.generator.createGenerator=function(a,b){b=new $jscomp.generator.Generator_(new $jscomp.generator.Engine_(b));$jscomp.setPrototypeOf&&a.prototype&&$jscomp.setPrototypeOf(b,a.prototype);return b};
+$jscomp.asyncExecutePromiseGenerator=function(a){function b(f){return a.next(f)}function e(f){return a.throw(f)}return new Promise(function(f,d){function l(c){c.done?f(c.value):Promise.resolve(c.value).then(b,e).then(l,d)}l(a.next())})};$jscomp.asyncExecutePromiseGeneratorFunction=function(a){return $jscomp.asyncExecutePromiseGenerator(a())};$jscomp.asyncExecutePromiseGeneratorProgram=function(a){return $jscomp.asyncExecutePromiseGenerator(new $jscomp.generator.Generator_(new $jscomp.generator.Engine_(a)))};
+$jscomp.initSymbol=function(){};$jscomp.polyfill("Symbol",function(a){if(a)return a;var b=function(d,l){this.$jscomp$symbol$id_=d;$jscomp.defineProperty(this,"description",{configurable:!0,writable:!0,value:l})};b.prototype.toString=function(){return this.$jscomp$symbol$id_};var e=0,f=function(d){if(this instanceof f)throw new TypeError("Symbol is not a constructor");return new b("jscomp_symbol_"+(d||"")+"_"+e++,d)};return f},"es6","es3");$jscomp.initSymbolIterator=function(){};
+$jscomp.polyfill("Symbol.iterator",function(a){if(a)return a;a=Symbol("Symbol.iterator");for(var b="Array Int8Array Uint8Array Uint8ClampedArray Int16Array Uint16Array Int32Array Uint32Array Float32Array Float64Array".split(" "),e=0;e<b.length;e++){var f=$jscomp.global[b[e]];"function"===typeof f&&"function"!=typeof f.prototype[a]&&$jscomp.defineProperty(f.prototype,a,{configurable:!0,writable:!0,value:function(){return $jscomp.iteratorPrototype($jscomp.arrayIteratorImpl(this))}})}return a},"es6",
+"es3");$jscomp.initSymbolAsyncIterator=function(){};$jscomp.iteratorPrototype=function(a){a={next:a};a[Symbol.iterator]=function(){return this};return a};$jscomp.FORCE_POLYFILL_PROMISE=!1;
+$jscomp.polyfill("Promise",function(a){function b(){this.batch_=null}function e(c){return c instanceof d?c:new d(function(g,h){g(c)})}if(a&&!$jscomp.FORCE_POLYFILL_PROMISE)return a;b.prototype.asyncExecute=function(c){if(null==this.batch_){this.batch_=[];var g=this;this.asyncExecuteFunction(function(){g.executeBatch_()})}this.batch_.push(c)};var f=$jscomp.global.setTimeout;b.prototype.asyncExecuteFunction=function(c){f(c,0)};b.prototype.executeBatch_=function(){for(;this.batch_&&this.batch_.length;){var c=
+this.batch_;this.batch_=[];for(var g=0;g<c.length;++g){var h=c[g];c[g]=null;try{h()}catch(k){this.asyncThrow_(k)}}}this.batch_=null};b.prototype.asyncThrow_=function(c){this.asyncExecuteFunction(function(){throw c;})};var d=function(c){this.state_=0;this.result_=void 0;this.onSettledCallbacks_=[];var g=this.createResolveAndReject_();try{c(g.resolve,g.reject)}catch(h){g.reject(h)}};d.prototype.createResolveAndReject_=function(){function c(k){return function(m){h||(h=!0,k.call(g,m))}}var g=this,h=!1;
+return{resolve:c(this.resolveTo_),reject:c(this.reject_)}};d.prototype.resolveTo_=function(c){if(c===this)this.reject_(new TypeError("A Promise cannot resolve to itself"));else if(c instanceof d)this.settleSameAsPromise_(c);else{a:switch(typeof c){case "object":var g=null!=c;break a;case "function":g=!0;break a;default:g=!1}g?this.resolveToNonPromiseObj_(c):this.fulfill_(c)}};d.prototype.resolveToNonPromiseObj_=function(c){var g=void 0;try{g=c.then}catch(h){this.reject_(h);return}"function"==typeof g?
+this.settleSameAsThenable_(g,c):this.fulfill_(c)};d.prototype.reject_=function(c){this.settle_(2,c)};d.prototype.fulfill_=function(c){this.settle_(1,c)};d.prototype.settle_=function(c,g){if(0!=this.state_)throw Error("Cannot settle("+c+", "+g+"): Promise already settled in state"+this.state_);this.state_=c;this.result_=g;this.executeOnSettledCallbacks_()};d.prototype.executeOnSettledCallbacks_=function(){if(null!=this.onSettledCallbacks_){for(var c=0;c<this.onSettledCallbacks_.length;++c)l.asyncExecute(this.onSettledCallbacks_[c]);
+this.onSettledCallbacks_=null}};var l=new b;d.prototype.settleSameAsPromise_=function(c){var g=this.createResolveAndReject_();c.callWhenSettled_(g.resolve,g.reject)};d.prototype.settleSameAsThenable_=function(c,g){var h=this.createResolveAndReject_();try{c.call(g,h.resolve,h.reject)}catch(k){h.reject(k)}};d.prototype.then=function(c,g){function h(n,p){return"function"==typeof n?function(q){try{k(n(q))}catch(r){m(r)}}:p}var k,m,t=new d(function(n,p){k=n;m=p});this.callWhenSettled_(h(c,k),h(g,m));return t};
+d.prototype.catch=function(c){return this.then(void 0,c)};d.prototype.callWhenSettled_=function(c,g){function h(){switch(k.state_){case 1:c(k.result_);break;case 2:g(k.result_);break;default:throw Error("Unexpected state: "+k.state_);}}var k=this;null==this.onSettledCallbacks_?l.asyncExecute(h):this.onSettledCallbacks_.push(h)};d.resolve=e;d.reject=function(c){return new d(function(g,h){h(c)})};d.race=function(c){return new d(function(g,h){for(var k=$jscomp.makeIterator(c),m=k.next();!m.done;m=k.next())e(m.value).callWhenSettled_(g,
+h)})};d.all=function(c){var g=$jscomp.makeIterator(c),h=g.next();return h.done?e([]):new d(function(k,m){function t(q){return function(r){n[q]=r;p--;0==p&&k(n)}}var n=[],p=0;do n.push(void 0),p++,e(h.value).callWhenSettled_(t(n.length-1),m),h=g.next();while(!h.done)})};return d},"es6","es3");$jscomp.polyfill("Array.prototype.find",function(a){return a?a:function(b,c){return $jscomp.findInternal(this,b,c).v}},"es6","es3");$jscomp.polyfill("String.prototype.includes",function(b){return b?b:function(c,d){return-1!==$jscomp.checkStringArgs(this,c,"includes").indexOf(c,d||0)}},"es6","es3");
+(function(w,L){typeof exports=="object"&&typeof module!="undefined"?module.exports=L():typeof define=="function"&&define.amd?define(L):w.autoComplete=L()})(this,function(){"use strict";function w(a,t){if(!(a instanceof t))throw new TypeError("Cannot call a class as a function")}function L(a,t){for(var e=0;e<t.length;e++){var n=t[e];n.enumerable=n.enumerable||!1,n.configurable=!0,"value"in n&&(n.writable=!0),Object.defineProperty(a,n.key,n)}}function U(a,t,e){return t&&L(a.prototype,t),e&&L(a,e),a}var S="data-id",b={resultsList:"autoComplete_list",result:"autoComplete_result",highlight:"autoComplete_highlighted",selectedResult:"autoComplete_selected"},_={ENTER:13,ARROW_UP:38,ARROW_DOWN:40},F=function(t){return typeof t=="string"?document.querySelector(t):t()},z=function(t){var e=document.createElement(t.element);return e.setAttribute("id",b.resultsList),t.container&&t.container(e),t.destination.insertAdjacentElement(t.position,e),e},B=function(t){return"<span class=".concat(b.highlight,">").concat(t,"</span>")},G=function(t,e,n){var r=document.createDocumentFragment();e.forEach(function(l,u){var i=document.createElement(n.element),s=e[u].index;i.setAttribute(S,s),i.setAttribute("class",b.result),n.content?n.content(l,i):i.innerHTML=l.match||l,r.appendChild(i)}),t.appendChild(r)},x=function(t){return t.innerHTML=""},T=function(t,e,n,r,l,u){r({event:t,query:e instanceof HTMLInputElement?e.value:e.innerHTML,matches:l.matches,results:l.list.map(function(i){return i.value}),selection:l.list.find(function(i){if(t.keyCode===_.ENTER)return i.index===Number(u.getAttribute(S));if(t.type==="mousedown")return i.index===Number(t.currentTarget.getAttribute(S))})}),x(n)},J=function(t,e,n,r){var l=e.childNodes,u=l.length-1,i=void 0,s,c=function(f){i.classList.remove(b.selectedResult),f===1?s=i.nextSibling:s=i.previousSibling},o=function(f){i=f,i.classList.add(b.selectedResult)};t.onkeydown=function(h){if(l.length>0)switch(h.keyCode){case _.ARROW_UP:i?(c(0),o(s||l[u])):o(l[u]);break;case _.ARROW_DOWN:i?(c(1),o(s||l[0])):o(l[0]);break;case _.ENTER:i&&T(h,t,e,n,r,i)}},l.forEach(function(h){h.onmousedown=function(f){return T(f,t,e,n,r)}})},y={getInput:F,createResultsList:z,highlight:B,addResultsToList:G,navigation:J,clearResults:x},k=function(t,e){e=e||{bubbles:!1,cancelable:!1,detail:void 0};var n=document.createEvent("CustomEvent");return n.initCustomEvent(t,e.bubbles,e.cancelable,e.detail),n};k.prototype=window.Event.prototype;var K=typeof window.CustomEvent=="function"&&window.CustomEvent||k,Q=function(){Element.prototype.matches||(Element.prototype.matches=Element.prototype.msMatchesSelector||Element.prototype.webkitMatchesSelector),Element.prototype.closest||(Element.prototype.closest=function(t){var e=this;do{if(e.matches(t))return e;e=e.parentElement||e.parentNode}while(e!==null&&e.nodeType===1);return null})},A={CustomEventWrapper:K,initElementClosestPolyfill:Q},X=function(){function a(t){w(this,a);var e=t.selector,n=e===void 0?"#autoComplete":e,r=t.data,l=r.key,u=r.src,i=r.cache,s=i===void 0?!0:i,c=t.query,o=t.trigger;o=o===void 0?{}:o;var h=o.event,f=h===void 0?["input"]:h,d=o.condition,p=d===void 0?!1:d,$=t.searchEngine,v=$===void 0?"strict":$,g=t.threshold,m=g===void 0?0:g,R=t.debounce,Y=R===void 0?0:R,E=t.resultsList;E=E===void 0?{}:E;var M=E.render,H=M===void 0?!1:M,I=E.container,Z=I===void 0?!1:I,ee=E.destination,P=E.position,te=P===void 0?"afterend":P,W=E.element,ne=W===void 0?"ul":W,N=E.navigation,ie=N===void 0?!1:N,O=t.sort,se=O===void 0?!1:O,le=t.placeHolder,V=t.maxResults,oe=V===void 0?5:V,C=t.resultItem;C=C===void 0?{}:C;var j=C.content,ae=j===void 0?!1:j,q=C.element,re=q===void 0?"li":q,ue=t.noResults,D=t.highlight,ce=D===void 0?!1:D,he=t.onSelection,fe=H?y.createResultsList({container:Z,destination:ee||y.getInput(n),position:te,element:ne}):null;this.selector=n,this.data={src:function(){return typeof u=="function"?u():u},key:l,cache:s},this.query=c,this.trigger={event:f,condition:p},this.searchEngine=v==="loose"?"loose":typeof v=="function"?v:"strict",this.threshold=m,this.debounce=Y,this.resultsList={render:H,view:fe,navigation:ie},this.sort=se,this.placeHolder=le,this.maxResults=oe,this.resultItem={content:ae,element:re},this.noResults=ue,this.highlight=ce,this.onSelection=he,this.init()}return U(a,[{key:"search",value:function(e,n){var r=n.toLowerCase();if(this.searchEngine==="loose"){e=e.replace(/ /g,"");for(var l=[],u=0,i=0;i<r.length;i++){var s=n[i];u<e.length&&r[i]===e[u]&&(s=this.highlight?y.highlight(s):s,u++),l.push(s)}return u!==e.length?!1:l.join("")}else if(r.includes(e)){var c=new RegExp("".concat(e),"i");return e=c.exec(n),this.highlight?n.replace(e,y.highlight(e)):n}}},{key:"listMatchedResults",value:function(e){var n=this;return new Promise(function(r){var l=[];e.filter(function(i,s){var c=function(g){var m=g?i[g]:i;if(m){var R=typeof n.searchEngine=="function"?n.searchEngine(n.queryValue,m):n.search(n.queryValue,m);R&&g?l.push({key:g,index:s,match:R,value:i}):R&&!g&&l.push({index:s,match:R,value:i})}};if(n.data.key){var o=!0,h=!1,f=void 0;try{for(var d=n.data.key[Symbol.iterator](),p;!(o=(p=d.next()).done);o=!0){var $=p.value;c($)}}catch(v){h=!0,f=v}finally{try{!o&&d.return!=null&&d.return()}finally{if(h)throw f}}}else c()});var u=n.sort?l.sort(n.sort).slice(0,n.maxResults):l.slice(0,n.maxResults);return r({matches:l.length,list:u})})}},{key:"ignite",value:function(){var e=this,n=y.getInput(this.selector);this.placeHolder&&n.setAttribute("placeholder",this.placeHolder);var r=function(s,c){var o;return function(){var h=this,f=arguments;clearTimeout(o),o=setTimeout(function(){return s.apply(h,f)},c)}},l=function(s){var c=n instanceof HTMLInputElement||n instanceof HTMLTextAreaElement?n.value.toLowerCase():n.innerHTML.toLowerCase(),o=e.queryValue=e.query&&e.query.manipulate?e.query.manipulate(c):c,h=e.resultsList.render,f=e.trigger.condition?e.trigger.condition(o):o.length>e.threshold&&o.replace(/ /g,"").length,d=function(g,m){n.dispatchEvent(new A.CustomEventWrapper("autoComplete",{bubbles:!0,detail:{event:g,input:c,query:o,matches:m?m.matches:null,results:m?m.list:null},cancelable:!0}))};if(h){var p=e.resultsList.view,$=y.clearResults(p);f?e.listMatchedResults(e.dataStream,s).then(function(v){d(s,v),e.resultsList.render&&(v.list.length===0&&e.noResults?e.noResults():(y.addResultsToList(p,v.list,e.resultItem),e.onSelection&&(e.resultsList.navigation?e.resultsList.navigation(s,n,p,e.onSelection,v):y.navigation(n,p,e.onSelection,v))))}):d(s)}else!h&&f&&e.listMatchedResults(e.dataStream,s).then(function(v){d(s,v)})},u=function(s){Promise.resolve(e.data.cache?e.dataStream:e.data.src()).then(function(c){e.dataStream=c,l(s)})};this.trigger.event.forEach(function(i){n.addEventListener(i,r(function(s){return u(s)},e.debounce))})}},{key:"init",value:function(){var e=this;this.data.cache?Promise.resolve(this.data.src()).then(function(n){e.dataStream=n,e.ignite()}):this.ignite(),A.initElementClosestPolyfill()}}]),a}();return X});
+var completionInput=document.querySelector(".js-autocomplete");
+if(completionInput&&completionInput.form){completionInput.form.classList.add("AutoComplete-form");var completion=new autoComplete({selector:function(){return completionInput},data:{src:fetchSuggestions,key:["packagePath"],cache:!1},searchEngine:function(a,b){return b},threshold:3,debounce:100,maxResults:10,resultsList:{render:!0,container:function(a){return a.classList.add("AutoComplete-list")},destination:completionInput.form,position:"beforeend"},resultItem:{content:renderSuggestion},
+onSelection:function(a){a.selection&&(a.event.preventDefault(),window.location.href="/"+a.selection.value.packagePath)}});completionInput.addEventListener("blur",function(){completion.resultsList.view.innerHTML=""})}
+function fetchSuggestions(){var a,b;return $jscomp.asyncExecutePromiseGeneratorProgram(function(c){switch(c.nextAddress){case 1:a=completionInput.value.trim();if(!a)return c.return([]);c.setCatchFinallyBlocks(2);return c.yield(fetch("/autocomplete?q="+encodeURIComponent(a)),4);case 4:b=c.yieldResult;if(!b.ok)return c.return([]);return c.yield(b.json(),5);case 5:return c.return(c.yieldResult);case 2:return c.enterCatchBlock(),c.return([])}})}
+function renderSuggestion(a,b){var c=document.createElement("span");c.className="AutoComplete-path";c.textContent=a.value.packagePath;b.appendChild(c);a.value.synopsis&&(c=document.createElement("span"),c.className="AutoComplete-synopsis",c.textContent=a.value.synopsis,b.appendChild(c))};
@@ -0,0 +1,20 @@
+/**
+ * @license
+ * Copyright 2020 The Go Authors. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+// This file reloads the page when the frontend reports that the module it
+// shows has changed on disk. It is only loaded when serving local modules
+// with live reload enabled.
+
+const events = new EventSource('/_live-reload');
+events.addEventListener('reload', e => {
+  const modulePath = e.data;
+  // Drop the leading slash and any version from the page path.
+  const path = window.location.pathname.slice(1).replace(/@[^/]*/, '');
+  if (path === modulePath || path.startsWith(modulePath + '/')) {
+    window.location.reload();
+  }
+});
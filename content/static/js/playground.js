@@ -6,8 +6,10 @@
  */
 
 // This file implements the playground implementation of the documentation
-// page. The playground involves a "play" button that allows you to open up
-// a new link to play.golang.org using the example code.
+// page. The playground involves a "run" button that runs the example code
+// and shows its output, and a "play" button that allows you to open up a new
+// link to the shared example code. Both are served under /play/ by the
+// server's playground backend.
 
 // The CSS is in content/static/css/stylesheet.css.
 
@@ -22,6 +24,7 @@ const PlayExampleClassName = {
   EXAMPLE_OUTPUT: '.Documentation-exampleOutput',
   EXAMPLE_ERROR: '.Documentation-exampleError',
   PLAY_BUTTON: '.Documentation-examplePlayButton',
+  RUN_BUTTON: '.Documentation-exampleRunButton',
 };
 
 /**
@@ -79,6 +82,13 @@ class PlaygroundExampleController {
     }
     this._playButtonEl = /** @type {!Element} */ (playButtonEl);
 
+    /**
+     * Button that runs an example and shows its output, this element
+     * only exists in executable examples.
+     * @private {Element}
+     */
+    this._runButtonEl = exampleEl.querySelector(PlayExampleClassName.RUN_BUTTON);
+
     /**
      * The executable code of an example.
      * @private {Element}
@@ -104,6 +114,11 @@ class PlaygroundExampleController {
     this._playButtonEl.addEventListener('click', e =>
       this.handlePlayButtonClick(/** @type {!MouseEvent} */ (e))
     );
+    if (this._runButtonEl) {
+      this._runButtonEl.addEventListener('click', e =>
+        this.handleRunButtonClick(/** @type {!MouseEvent} */ (e))
+      );
+    }
   }
 
   /**
@@ -122,13 +137,17 @@ class PlaygroundExampleController {
   }
 
   /**
-   * Changes the text of the example's output box.
+   * Changes the text of the example's output box, creating the box
+   * after the example code if the example has none.
    * @param {string} output
    */
   setOutputText(output) {
-    if (this._outputEl) {
-      this._outputEl.textContent = output;
+    if (!this._outputEl) {
+      this._outputEl = document.createElement('pre');
+      this._outputEl.classList.add(PlayExampleClassName.EXAMPLE_OUTPUT.slice(1));
+      this._inputEl.insertAdjacentElement('afterend', this._outputEl);
     }
+    this._outputEl.textContent = output;
   }
 
   /**
@@ -142,13 +161,13 @@ class PlaygroundExampleController {
   }
 
   /**
-   * Opens a new window to play.golang.org using the
-   * example snippet's code in the playground.
+   * Opens a new window showing the example snippet's code,
+   * shared with the playground backend.
    * @param {!MouseEvent} e
    * @private
    */
   handlePlayButtonClick(e) {
-    const PLAYGROUND_BASE_URL = '//play.golang.org/p/';
+    const PLAYGROUND_BASE_URL = '/play/p/';
 
     this.setOutputText('Waiting for remote server…');
 
@@ -164,6 +183,44 @@ class PlaygroundExampleController {
         this.setErrorText(/** @type {!string} */ (err));
       });
   }
+
+  /**
+   * Runs the example snippet's code with the playground backend
+   * and shows its output in the output box.
+   * @param {!MouseEvent} e
+   * @private
+   */
+  handleRunButtonClick(e) {
+    this.setOutputText('Waiting for remote server…');
+    this._errorEl.textContent = '';
+
+    const body = new URLSearchParams();
+    body.set('version', '2');
+    body.set('body', this._inputEl.textContent);
+    fetch('/play/compile', {
+      method: 'POST',
+      body: body,
+    })
+      .then(res => {
+        if (!res.ok) {
+          throw new Error(res.statusText);
+        }
+        return res.json();
+      })
+      .then(({ Errors, Events, Status }) => {
+        let output = (Events || []).map(e => e.Message).join('');
+        if (Status) {
+          output += `\nProgram exited: status ${Status}.`;
+        }
+        this.setOutputText(output);
+        if (Errors) {
+          this._errorEl.textContent = Errors;
+        }
+      })
+      .catch(err => {
+        this.setErrorText(/** @type {!string} */ (String(err)));
+      });
+  }
 }
 
 const exampleHashRegex = location.hash.match(/^#(example-.*)$/);
@@ -11,9 +11,12 @@ $jscomp.IS_SYMBOL_NATIVE="function"===typeof Symbol&&"symbol"===typeof Symbol("x
 $jscomp.polyfill=function(a,b,c,d){b&&($jscomp.ISOLATE_POLYFILLS?$jscomp.polyfillIsolated(a,b,c,d):$jscomp.polyfillUnisolated(a,b,c,d))};$jscomp.polyfillUnisolated=function(a,b,c,d){c=$jscomp.global;a=a.split(".");for(d=0;d<a.length-1;d++){var e=a[d];if(!(e in c))return;c=c[e]}a=a[a.length-1];d=c[a];b=b(d);b!=d&&null!=b&&$jscomp.defineProperty(c,a,{configurable:!0,writable:!0,value:b})};
 $jscomp.polyfillIsolated=function(a,b,c,d){var e=a.split(".");a=1===e.length;d=e[0];d=!a&&d in $jscomp.polyfills?$jscomp.polyfills:$jscomp.global;for(var f=0;f<e.length-1;f++){var g=e[f];if(!(g in d))return;d=d[g]}e=e[e.length-1];c=$jscomp.IS_SYMBOL_NATIVE&&"es6"===c?d[e]:null;b=b(c);null!=b&&(a?$jscomp.defineProperty($jscomp.polyfills,e,{configurable:!0,writable:!0,value:b}):b!==c&&($jscomp.propertyToPolyfillSymbol[e]=$jscomp.IS_SYMBOL_NATIVE?$jscomp.global.Symbol(e):$jscomp.POLYFILL_PREFIX+e,e=
 $jscomp.propertyToPolyfillSymbol[e],$jscomp.defineProperty(d,e,{configurable:!0,writable:!0,value:b})))};$jscomp.polyfill("Array.prototype.find",function(a){return a?a:function(b,c){return $jscomp.findInternal(this,b,c).v}},"es6","es3");
-var PlayExampleClassName={PLAY_HREF:".js-exampleHref",PLAY_CONTAINER:".js-exampleContainer",EXAMPLE_INPUT:".Documentation-exampleCode",EXAMPLE_OUTPUT:".Documentation-exampleOutput",EXAMPLE_ERROR:".Documentation-exampleError",PLAY_BUTTON:".Documentation-examplePlayButton"},PlaygroundExampleController=function(a){var b=this,c=!1;a||(console.warn("Must provide playground example element"),c=!0);this._exampleEl=a;var d=a.querySelector("a");d||(console.warn("anchor tag is not detected"),c=!0);this._anchorEl=
-d;(d=a.querySelector(PlayExampleClassName.EXAMPLE_ERROR))||(c=!0);this._errorEl=d;(d=a.querySelector(PlayExampleClassName.PLAY_BUTTON))||(c=!0);this._playButtonEl=d;d=a.querySelector(PlayExampleClassName.EXAMPLE_INPUT);d||(console.warn("Input element is not detected"),c=!0);this._inputEl=d;this._outputEl=a.querySelector(PlayExampleClassName.EXAMPLE_OUTPUT);c||this._playButtonEl.addEventListener("click",function(e){return b.handlePlayButtonClick(e)})};
-PlaygroundExampleController.prototype.getAnchorHash=function(){return this._anchorEl.hash};PlaygroundExampleController.prototype.expand=function(){this._exampleEl.open=!0};PlaygroundExampleController.prototype.setOutputText=function(a){this._outputEl&&(this._outputEl.textContent=a)};PlaygroundExampleController.prototype.setErrorText=function(a){this._errorEl.textContent=a;this.setOutputText("An error has occurred\u2026")};
-PlaygroundExampleController.prototype.handlePlayButtonClick=function(a){var b=this;this.setOutputText("Waiting for remote server\u2026");fetch("/play/",{method:"POST",body:this._inputEl.textContent}).then(function(c){return c.text()}).then(function(c){window.open("//play.golang.org/p/"+c)}).catch(function(c){b.setErrorText(c)})};var exampleHashRegex=location.hash.match(/^#(example-.*)$/);
+var PlayExampleClassName={PLAY_HREF:".js-exampleHref",PLAY_CONTAINER:".js-exampleContainer",EXAMPLE_INPUT:".Documentation-exampleCode",EXAMPLE_OUTPUT:".Documentation-exampleOutput",EXAMPLE_ERROR:".Documentation-exampleError",PLAY_BUTTON:".Documentation-examplePlayButton",RUN_BUTTON:".Documentation-exampleRunButton"},PlaygroundExampleController=function(a){var b=this,c=!1;a||(console.warn("Must provide playground example element"),c=!0);this._exampleEl=a;var d=a.querySelector("a");d||(console.warn("anchor tag is not detected"),c=!0);
+this._anchorEl=d;(d=a.querySelector(PlayExampleClassName.EXAMPLE_ERROR))||(c=!0);this._errorEl=d;(d=a.querySelector(PlayExampleClassName.PLAY_BUTTON))||(c=!0);this._playButtonEl=d;this._runButtonEl=a.querySelector(PlayExampleClassName.RUN_BUTTON);d=a.querySelector(PlayExampleClassName.EXAMPLE_INPUT);d||(console.warn("Input element is not detected"),c=!0);this._inputEl=d;this._outputEl=a.querySelector(PlayExampleClassName.EXAMPLE_OUTPUT);
+c||(this._playButtonEl.addEventListener("click",function(e){return b.handlePlayButtonClick(e)}),this._runButtonEl&&this._runButtonEl.addEventListener("click",function(e){return b.handleRunButtonClick(e)}))};PlaygroundExampleController.prototype.getAnchorHash=function(){return this._anchorEl.hash};PlaygroundExampleController.prototype.expand=function(){this._exampleEl.open=!0};
+PlaygroundExampleController.prototype.setOutputText=function(a){this._outputEl||(this._outputEl=document.createElement("pre"),this._outputEl.classList.add(PlayExampleClassName.EXAMPLE_OUTPUT.slice(1)),this._inputEl.insertAdjacentElement("afterend",this._outputEl));this._outputEl.textContent=a};PlaygroundExampleController.prototype.setErrorText=function(a){this._errorEl.textContent=a;this.setOutputText("An error has occurred\u2026")};
+PlaygroundExampleController.prototype.handlePlayButtonClick=function(a){var b=this;this.setOutputText("Waiting for remote server\u2026");fetch("/play/",{method:"POST",body:this._inputEl.textContent}).then(function(c){return c.text()}).then(function(c){window.open("/play/p/"+c)}).catch(function(c){b.setErrorText(c)})};
+PlaygroundExampleController.prototype.handleRunButtonClick=function(a){var b=this;this.setOutputText("Waiting for remote server\u2026");this._errorEl.textContent="";var c=new URLSearchParams;c.set("version","2");c.set("body",this._inputEl.textContent);fetch("/play/compile",{method:"POST",body:c}).then(function(d){if(!d.ok)throw Error(d.statusText);return d.json()}).then(function(d){var e=d.Errors,f=d.Events;d=d.Status;f=(f||[]).map(function(g){return g.Message}).join("");
+d&&(f+="\nProgram exited: status "+d+".");b.setOutputText(f);e&&(b._errorEl.textContent=e)}).catch(function(d){b.setErrorText(String(d))})};var exampleHashRegex=location.hash.match(/^#(example-.*)$/);
 if(exampleHashRegex){var exampleHashEl=document.getElementById(exampleHashRegex[1]);exampleHashEl&&(exampleHashEl.open=!0)}var exampleHrefs=[].concat($jscomp.arrayFromIterable(document.querySelectorAll(PlayExampleClassName.PLAY_HREF))),findExampleHash=function(a){return exampleHrefs.find(function(b){return b.hash===a.getAnchorHash()})};
 document.querySelectorAll(PlayExampleClassName.PLAY_CONTAINER).forEach(function(a){var b=new PlaygroundExampleController(a);(a=findExampleHash(b))?a.addEventListener("click",function(){b.expand()}):console.warn("example href not found")});
@@ -75,3 +75,19 @@ const directoriesOption = document.querySelector('.js-directoriesOption');
 if (!unitDirectories) {
   directoriesOption.setAttribute('disabled', true);
 }
+
+/**
+ * Expands the collapsed documentation of a deprecated symbol when the URL
+ * fragment links to it or to one of its fields or methods.
+ */
+function openDeprecatedDetails() {
+  const id = decodeURIComponent(window.location.hash.slice(1));
+  const el = id && document.getElementById(id);
+  const details = el && el.closest('.js-deprecatedDetails');
+  if (details) {
+    details.open = true;
+    el.scrollIntoView();
+  }
+}
+openDeprecatedDetails();
+window.addEventListener('hashchange', openDeprecatedDetails);
@@ -49,9 +49,7 @@ main() {
   $cmd $JSDIR/playground.min.js         $JSDIR/playground.js
   $cmd $JSDIR/badge.min.js              $JSDIR/badge.js
   $cmd $JSDIR/jump.min.js               third_party/dialog-polyfill/dialog-polyfill.js $JSDIR/jump.js
-
-  # TODO: once this is not an experiment, add it to the relevant line above.
-  $cmd $JSDIR/completion.min.js         $JSDIR/completion.js
+  $cmd $JSDIR/completion.min.js         third_party/autoComplete.js/autoComplete.js $JSDIR/completion.js
 }
 
 main $@
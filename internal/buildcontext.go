@@ -0,0 +1,158 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A BuildContext describes a build environment in which a package is
+// processed: the files of the package that are used are those whose build
+// constraints are satisfied by the GOOS, GOARCH and Tags of the context.
+type BuildContext struct {
+	GOOS, GOARCH string
+	// Tags are additional build tags that are considered satisfied.
+	Tags []string `json:",omitempty"`
+}
+
+// BuildContextAll matches every build context. When used to request a unit,
+// it selects the documentation for the first build context the unit has, in
+// the order given by CompareBuildContexts.
+var BuildContextAll = BuildContext{}
+
+// DefaultBuildContexts are the build contexts used to process packages when
+// no others are configured.
+var DefaultBuildContexts = []BuildContext{
+	{GOOS: "linux", GOARCH: "amd64"},
+	{GOOS: "windows", GOARCH: "amd64"},
+	{GOOS: "darwin", GOARCH: "amd64"},
+	{GOOS: "js", GOARCH: "wasm"},
+}
+
+// String returns the build context in the form "GOOS/GOARCH", followed by its
+// tags, if any, separated by commas.
+func (b BuildContext) String() string {
+	s := b.GOOS + "/" + b.GOARCH
+	if len(b.Tags) > 0 {
+		s += "," + strings.Join(b.Tags, ",")
+	}
+	return s
+}
+
+// IsAll reports whether b is BuildContextAll.
+func (b BuildContext) IsAll() bool {
+	return b.GOOS == "" && b.GOARCH == ""
+}
+
+// Match reports whether b matches the given GOOS and GOARCH. An empty field
+// of b matches any value.
+func (b BuildContext) Match(goos, goarch string) bool {
+	return (b.GOOS == "" || b.GOOS == goos) && (b.GOARCH == "" || b.GOARCH == goarch)
+}
+
+// ParseBuildContext parses a build context of the form
+// "GOOS/GOARCH[,tag...]", as produced by BuildContext.String.
+func ParseBuildContext(s string) (BuildContext, error) {
+	parts := strings.Split(strings.TrimSpace(s), ",")
+	env := strings.Split(parts[0], "/")
+	if len(env) != 2 || env[0] == "" || env[1] == "" {
+		return BuildContext{}, fmt.Errorf("invalid build context %q: want GOOS/GOARCH[,tag...]", s)
+	}
+	bc := BuildContext{GOOS: env[0], GOARCH: env[1]}
+	for _, t := range parts[1:] {
+		if t = strings.TrimSpace(t); t != "" {
+			bc.Tags = append(bc.Tags, t)
+		}
+	}
+	return bc, nil
+}
+
+// ValidateBuildContexts checks that bcs is a usable list of build contexts:
+// it must be non-empty, and no GOOS/GOARCH pair may appear twice, since
+// documentation is stored per GOOS and GOARCH.
+func ValidateBuildContexts(bcs []BuildContext) error {
+	if len(bcs) == 0 {
+		return fmt.Errorf("no build contexts")
+	}
+	seen := map[string]bool{}
+	for _, bc := range bcs {
+		if bc.IsAll() || bc.GOOS == "" || bc.GOARCH == "" {
+			return fmt.Errorf("invalid build context %q", bc)
+		}
+		key := bc.GOOS + "/" + bc.GOARCH
+		if seen[key] {
+			return fmt.Errorf("duplicate build context %q", key)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// CompareBuildContexts returns a negative number, 0, or a positive number
+// depending on whether c1 is less than, equal to, or greater than c2. The
+// build contexts in DefaultBuildContexts come first, in order, followed by
+// all others sorted by GOOS and then GOARCH. Tags are ignored.
+func CompareBuildContexts(c1, c2 BuildContext) int {
+	if c1.GOOS == c2.GOOS && c1.GOARCH == c2.GOARCH {
+		return 0
+	}
+	i1, i2 := defaultBuildContextIndex(c1), defaultBuildContextIndex(c2)
+	if i1 != i2 {
+		return i1 - i2
+	}
+	if c1.GOOS != c2.GOOS {
+		return strings.Compare(c1.GOOS, c2.GOOS)
+	}
+	return strings.Compare(c1.GOARCH, c2.GOARCH)
+}
+
+// defaultBuildContextIndex returns the position of bc in
+// DefaultBuildContexts, or len(DefaultBuildContexts) if it is not present.
+func defaultBuildContextIndex(bc BuildContext) int {
+	for i, d := range DefaultBuildContexts {
+		if d.GOOS == bc.GOOS && d.GOARCH == bc.GOARCH {
+			return i
+		}
+	}
+	return len(DefaultBuildContexts)
+}
+
+// SortBuildContexts sorts bcs in the order given by CompareBuildContexts.
+func SortBuildContexts(bcs []BuildContext) {
+	sort.SliceStable(bcs, func(i, j int) bool { return CompareBuildContexts(bcs[i], bcs[j]) < 0 })
+}
+
+// DocumentationForBuildContext returns the first Documentation in docs that
+// matches bc, or nil if there is none. If bc is BuildContextAll, it returns
+// the documentation whose build context sorts first.
+func DocumentationForBuildContext(docs []*Documentation, bc BuildContext) *Documentation {
+	var best *Documentation
+	for _, d := range docs {
+		if !bc.Match(d.GOOS, d.GOARCH) {
+			continue
+		}
+		if best == nil || CompareBuildContexts(d.BuildContext(), best.BuildContext()) < 0 {
+			best = d
+		}
+	}
+	return best
+}
+
+// DocumentationMatching returns the documentation in docs whose build context
+// matches bc, sorted by CompareBuildContexts.
+func DocumentationMatching(docs []*Documentation, bc BuildContext) []*Documentation {
+	var matched []*Documentation
+	for _, d := range docs {
+		if bc.Match(d.GOOS, d.GOARCH) {
+			matched = append(matched, d)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return CompareBuildContexts(matched[i].BuildContext(), matched[j].BuildContext()) < 0
+	})
+	return matched
+}
@@ -0,0 +1,92 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseBuildContext(t *testing.T) {
+	for _, test := range []struct {
+		in      string
+		want    BuildContext
+		wantErr bool
+	}{
+		{in: "linux/amd64", want: BuildContext{GOOS: "linux", GOARCH: "amd64"}},
+		{in: " linux/arm64,cgo, netgo", want: BuildContext{GOOS: "linux", GOARCH: "arm64", Tags: []string{"cgo", "netgo"}}},
+		{in: "linux", wantErr: true},
+		{in: "/amd64", wantErr: true},
+		{in: "linux/amd64/x", wantErr: true},
+	} {
+		got, err := ParseBuildContext(test.in)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseBuildContext(%q): got error %v, want error: %t", test.in, err, test.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ParseBuildContext(%q): mismatch (-want, +got):\n%s", test.in, diff)
+		}
+	}
+}
+
+func TestValidateBuildContexts(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		bcs     []BuildContext
+		wantErr bool
+	}{
+		{"default", DefaultBuildContexts, false},
+		{"empty", nil, true},
+		{"all", []BuildContext{BuildContextAll}, true},
+		{"duplicate", []BuildContext{{GOOS: "linux", GOARCH: "amd64"}, {GOOS: "linux", GOARCH: "amd64", Tags: []string{"cgo"}}}, true},
+	} {
+		if err := ValidateBuildContexts(test.bcs); (err != nil) != test.wantErr {
+			t.Errorf("%s: got error %v, want error: %t", test.name, err, test.wantErr)
+		}
+	}
+}
+
+func TestSortBuildContexts(t *testing.T) {
+	bcs := []BuildContext{
+		{GOOS: "linux", GOARCH: "arm64"},
+		{GOOS: "js", GOARCH: "wasm"},
+		{GOOS: "freebsd", GOARCH: "amd64"},
+		{GOOS: "linux", GOARCH: "amd64"},
+	}
+	SortBuildContexts(bcs)
+	want := []BuildContext{
+		{GOOS: "linux", GOARCH: "amd64"},
+		{GOOS: "js", GOARCH: "wasm"},
+		{GOOS: "freebsd", GOARCH: "amd64"},
+		{GOOS: "linux", GOARCH: "arm64"},
+	}
+	if diff := cmp.Diff(want, bcs); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestDocumentationForBuildContext(t *testing.T) {
+	docs := []*Documentation{
+		{GOOS: "windows", GOARCH: "amd64"},
+		{GOOS: "linux", GOARCH: "amd64"},
+		{GOOS: "linux", GOARCH: "arm64"},
+	}
+	for _, test := range []struct {
+		bc   BuildContext
+		want *Documentation
+	}{
+		{BuildContextAll, docs[1]},
+		{BuildContext{GOOS: "windows", GOARCH: "amd64"}, docs[0]},
+		{BuildContext{GOOS: "linux"}, docs[1]},
+		{BuildContext{GOARCH: "arm64"}, docs[2]},
+		{BuildContext{GOOS: "darwin", GOARCH: "amd64"}, nil},
+	} {
+		if got := DocumentationForBuildContext(docs, test.bc); got != test.want {
+			t.Errorf("DocumentationForBuildContext(%v) = %v, want %v", test.bc, got, test.want)
+		}
+	}
+}
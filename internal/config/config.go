@@ -114,6 +114,19 @@ type Config struct {
 	// Discovery environment variables
 	ProxyURL, IndexURL string
 
+	// ProxyRoutes send requests for the modules they match to proxies other
+	// than the one at ProxyURL, such as ones serving private modules.
+	ProxyRoutes []*ProxyRoute
+
+	// NetrcFile is the path of a .netrc file holding credentials for the
+	// proxies. It is consulted for proxies without a token.
+	NetrcFile string
+
+	// DirectFetchPatterns is a comma-separated list of glob patterns, in the
+	// syntax of GOPRIVATE, for the modules that are fetched directly from
+	// their git repositories instead of from a proxy.
+	DirectFetchPatterns string
+
 	// Ports used for hosting. 'DebugPort' is used for serving HTTP debug pages.
 	Port, DebugPort string
 
@@ -185,6 +198,45 @@ type Config struct {
 
 	// DisableErrorReporting disables sending errors to the GCP ErrorReporting system.
 	DisableErrorReporting bool
+
+	// BuildContexts are the build contexts used to process packages, each of
+	// the form "GOOS/GOARCH[,tag...]". If empty, a default set is used.
+	// Build contexts in the dynamic config take precedence.
+	BuildContexts []string
+}
+
+// A ProxyRoute sends requests for modules whose paths match Patterns to the
+// proxy at URL.
+type ProxyRoute struct {
+	// Patterns is a comma-separated list of glob patterns, in the syntax of
+	// GOPRIVATE, matched against prefixes of module paths.
+	Patterns string
+
+	URL string
+
+	// TokenSecret is the name of the secret holding a bearer token for the
+	// proxy. If it is empty, the proxy's credentials are read from the
+	// .netrc file, if any.
+	TokenSecret string
+	Token       string `json:"-"`
+}
+
+// parseProxyRoutes parses the value of GO_DISCOVERY_PROXY_ROUTES, a
+// space-separated list of routes of the form "patterns=url[;secret]".
+func parseProxyRoutes(s string) ([]*ProxyRoute, error) {
+	var routes []*ProxyRoute
+	for _, f := range strings.Fields(s) {
+		i := strings.IndexByte(f, '=')
+		if i <= 0 || i == len(f)-1 {
+			return nil, fmt.Errorf("invalid proxy route %q: want patterns=url[;secret]", f)
+		}
+		r := &ProxyRoute{Patterns: f[:i], URL: f[i+1:]}
+		if j := strings.IndexByte(r.URL, ';'); j >= 0 {
+			r.URL, r.TokenSecret = r.URL[:j], r.URL[j+1:]
+		}
+		routes = append(routes, r)
+	}
+	return routes, nil
 }
 
 // AppVersionLabel returns the version label for the current instance.  This is
@@ -357,6 +409,7 @@ func Init(ctx context.Context) (_ *Config, err error) {
 		AuthValues: parseCommaList(os.Getenv("GO_DISCOVERY_AUTH_VALUES")),
 		IndexURL:   GetEnv("GO_MODULE_INDEX_URL", "https://index.golang.org/index"),
 		ProxyURL:   GetEnv("GO_MODULE_PROXY_URL", "https://proxy.golang.org"),
+		NetrcFile:  os.Getenv("GO_DISCOVERY_NETRC"),
 		Port:       os.Getenv("PORT"),
 		DebugPort:  os.Getenv("DEBUG_PORT"),
 		// Resolve AppEngine identifiers
@@ -414,6 +467,14 @@ func Init(ctx context.Context) (_ *Config, err error) {
 		LogLevel:              os.Getenv("GO_DISCOVERY_LOG_LEVEL"),
 		ServeStats:            os.Getenv("GO_DISCOVERY_SERVE_STATS") == "true",
 		DisableErrorReporting: os.Getenv("GO_DISCOVERY_DISABLE_ERROR_REPORTING") == "true",
+		DirectFetchPatterns:   os.Getenv("GO_DISCOVERY_DIRECT_FETCH"),
+		// Build contexts are separated by spaces, since their tags are
+		// separated by commas.
+		BuildContexts: strings.Fields(os.Getenv("GO_DISCOVERY_BUILD_CONTEXTS")),
+	}
+	cfg.ProxyRoutes, err = parseProxyRoutes(os.Getenv("GO_DISCOVERY_PROXY_ROUTES"))
+	if err != nil {
+		return nil, err
 	}
 	bucket := os.Getenv("GO_DISCOVERY_CONFIG_BUCKET")
 	object := os.Getenv("GO_DISCOVERY_CONFIG_DYNAMIC")
@@ -482,6 +543,15 @@ func Init(ctx context.Context) (_ *Config, err error) {
 			return nil, fmt.Errorf("could not get database password secret: %v", err)
 		}
 	}
+	for _, r := range cfg.ProxyRoutes {
+		if r.TokenSecret == "" {
+			continue
+		}
+		r.Token, err = secrets.Get(ctx, r.TokenSecret)
+		if err != nil {
+			return nil, fmt.Errorf("could not get token for proxy route %q: %v", r.Patterns, err)
+		}
+	}
 	if cfg.Quota.Enable {
 		s, err := secrets.Get(ctx, "quota-hmac-key")
 		if err != nil {
@@ -118,3 +118,23 @@ func TestEnvAndApp(t *testing.T) {
 		}
 	}
 }
+
+func TestParseProxyRoutes(t *testing.T) {
+	got, err := parseProxyRoutes("corp.example.com=https://proxy.corp.example.com;proxy-token  *.internal,git.example.org/team=https://athens.example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*ProxyRoute{
+		{Patterns: "corp.example.com", URL: "https://proxy.corp.example.com", TokenSecret: "proxy-token"},
+		{Patterns: "*.internal,git.example.org/team", URL: "https://athens.example.org"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, s := range []string{"corp.example.com", "=https://proxy.example.com", "corp.example.com="} {
+		if _, err := parseProxyRoutes(s); err == nil {
+			t.Errorf("parseProxyRoutes(%q) succeeded, want error", s)
+		}
+	}
+}
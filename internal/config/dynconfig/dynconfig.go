@@ -29,6 +29,10 @@ type DynamicConfig struct {
 	// requires careful coordination with the config file contents.
 
 	Experiments []*internal.Experiment
+
+	// BuildContexts are the build contexts used to process packages. If
+	// empty, those in config.Config are used.
+	BuildContexts []internal.BuildContext
 }
 
 // Read reads dynamic configuration from the given location.
@@ -0,0 +1,48 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+// DocCoverage summarizes how much of the exported API of a package is
+// documented.
+type DocCoverage struct {
+	// HasPackageDoc reports whether the package has a package comment.
+	HasPackageDoc bool
+	// NumExported is the number of exported constants, variables, functions,
+	// types and methods of the package. Struct fields and interface methods
+	// are not counted.
+	NumExported int
+	// NumDocumented is the number of exported identifiers that have a doc
+	// comment.
+	NumDocumented int
+	// Undocumented holds the names of the exported identifiers that have no
+	// doc comment, qualified like Symbol names.
+	Undocumented []string
+	// NumFuncs is the number of exported functions and methods, and
+	// NumFuncsWithExamples is how many of them have at least one example.
+	NumFuncs             int
+	NumFuncsWithExamples int
+	// NumExamples is the number of examples in the package, including those
+	// for the package itself and for its types.
+	NumExamples int
+}
+
+// Percent returns the percentage of exported identifiers that are documented,
+// rounded down. A package with no exported identifiers is fully documented.
+func (c *DocCoverage) Percent() int {
+	return percent(c.NumDocumented, c.NumExported)
+}
+
+// ExamplePercent returns the percentage of exported functions and methods
+// that have an example, rounded down.
+func (c *DocCoverage) ExamplePercent() int {
+	return percent(c.NumFuncsWithExamples, c.NumFuncs)
+}
+
+func percent(n, total int) int {
+	if total == 0 {
+		return 100
+	}
+	return 100 * n / total
+}
@@ -16,11 +16,17 @@ type DataSource interface {
 	GetNestedModules(ctx context.Context, modulePath string) ([]*ModuleInfo, error)
 	// GetUnit returns information about a directory, which may also be a
 	// module and/or package. The module and version must both be known.
-	GetUnit(ctx context.Context, pathInfo *UnitMeta, fields FieldSet) (_ *Unit, err error)
+	// The documentation returned, if any, is for the build context bc.
+	GetUnit(ctx context.Context, pathInfo *UnitMeta, fields FieldSet, bc BuildContext) (_ *Unit, err error)
 	// GetUnitMeta returns information about a path.
 	GetUnitMeta(ctx context.Context, path, requestedModulePath, requestedVersion string) (_ *UnitMeta, err error)
 	// GetModuleReadme gets the readme for the module.
 	GetModuleReadme(ctx context.Context, modulePath, resolvedVersion string) (*Readme, error)
+	// GetSourceFile returns the contents of a .go file of the module,
+	// given by its path relative to the module root.
+	GetSourceFile(ctx context.Context, modulePath, resolvedVersion, filePath string) ([]byte, error)
+	// GetGoMod returns the directives of the go.mod file of the module.
+	GetGoMod(ctx context.Context, modulePath, resolvedVersion string) (*GoMod, error)
 
 	// GetLatestInfo gets information about the latest versions of a unit and module.
 	// See LatestInfo for documentation.
@@ -54,4 +60,10 @@ type LatestInfo struct {
 	// and the latest major version is 3, then is field is "M/v3/U". If the module version
 	// at MajorModulePath does not contain this unit, then it is the module path."
 	MajorUnitPath string
+
+	// Deprecated reports whether the module is deprecated by a "Deprecated:"
+	// comment in the go.mod file of its highest version, and
+	// DeprecationComment is the text of that comment.
+	Deprecated         bool
+	DeprecationComment string
 }
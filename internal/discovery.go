@@ -41,6 +41,12 @@ type ModuleInfo struct {
 	IsRedistributable bool
 	HasGoMod          bool // whether the module zip has a go.mod file
 	SourceInfo        *source.Info
+	// Retracted reports whether the version is retracted by the go.mod file
+	// of the highest version of the module. It is only populated by
+	// GetVersionsForPath.
+	Retracted bool
+	// RetractionRationale is the comment of the retract directive, if any.
+	RetractionRationale string
 }
 
 // VersionMap holds metadata associated with module queries for a version.
@@ -102,6 +108,18 @@ type Module struct {
 	// that may be contained in nested subdirectories.
 	Licenses []*licenses.License
 	Units    []*Unit
+	// SourceFiles holds the .go files of the module, for the source viewer.
+	SourceFiles []*SourceFile
+	// GoMod holds the directives of the go.mod file of the module. It is nil
+	// if the module has no go.mod file, or if it could not be parsed.
+	GoMod *GoMod
+}
+
+// SourceFile is a source file of a module.
+type SourceFile struct {
+	// Path is the path of the file relative to the module root.
+	Path     string
+	Contents []byte
 }
 
 // Packages returns all of the units for a module that are packages.
@@ -199,4 +217,7 @@ type SearchResult struct {
 	// can be approximate if search scanned only a subset of documents, and
 	// result count is estimated using the hyperloglog algorithm.
 	Approximate bool
+
+	// Symbol is the matching symbol of the package, for symbol searches.
+	Symbol *Symbol
 }
@@ -0,0 +1,197 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package docset exports the documentation of modules as a docset for the
+// Dash and Zeal documentation browsers. A docset holds the pages of a static
+// site generated by package staticsite, and a SQLite index of the packages
+// and symbols they document.
+package docset
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/safehtml/template"
+	_ "modernc.org/sqlite" // for the "sqlite" driver
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/staticsite"
+)
+
+// Config configures Write.
+type Config struct {
+	// Name is the name of the docset, shown by documentation browsers. The
+	// docset is written to the directory <Name>.docset.
+	Name string
+	// OutDir is the directory the docset directory is created in.
+	OutDir string
+	// StaticPath is the directory containing the templates and static
+	// assets, usually content/static.
+	StaticPath template.TrustedSource
+	// ExternalURL is the site that packages outside the docset are linked
+	// to. If empty, staticsite.DefaultExternalURL is used.
+	ExternalURL string
+	// BypassLicenseCheck causes the documentation of packages that are not
+	// redistributable to be included.
+	BypassLicenseCheck bool
+}
+
+// The layout of a docset is:
+//
+//   <Name>.docset/Contents/Info.plist
+//   <Name>.docset/Contents/Resources/docSet.dsidx    the search index
+//   <Name>.docset/Contents/Resources/Documents/      the static site
+const (
+	contentsDir  = "Contents"
+	documentsDir = "Documents"
+	indexFile    = "docSet.dsidx"
+	plistFile    = "Info.plist"
+)
+
+// entryTypes maps symbol kinds to the entry types of the search index.
+var entryTypes = map[internal.SymbolKind]string{
+	internal.SymbolKindConstant: "Constant",
+	internal.SymbolKindVariable: "Variable",
+	internal.SymbolKindFunction: "Function",
+	internal.SymbolKindType:     "Type",
+	internal.SymbolKindMethod:   "Method",
+	internal.SymbolKindField:    "Field",
+}
+
+// Write writes a docset documenting ms, and returns its directory. Every
+// package of ms has an entry in the search index, and so does every symbol
+// of a package, linked to its anchor in the package documentation.
+func Write(ctx context.Context, ms []*internal.Module, cfg Config) (_ string, err error) {
+	defer derrors.Wrap(&err, "docset.Write(%q)", cfg.Name)
+
+	if cfg.Name == "" {
+		return "", errors.New("missing docset name")
+	}
+	dir := filepath.Join(cfg.OutDir, cfg.Name+".docset")
+	resourcesDir := filepath.Join(dir, contentsDir, "Resources")
+	err = staticsite.GenerateModules(ctx, ms, staticsite.Config{
+		OutDir:             filepath.Join(resourcesDir, documentsDir),
+		StaticPath:         cfg.StaticPath,
+		ExternalURL:        cfg.ExternalURL,
+		BypassLicenseCheck: cfg.BypassLicenseCheck,
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := writePlist(filepath.Join(dir, contentsDir, plistFile), cfg.Name); err != nil {
+		return "", err
+	}
+	if err := writeIndex(ctx, filepath.Join(resourcesDir, indexFile), ms); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// writePlist writes the property list describing the docset.
+func writePlist(filename, name string) error {
+	id := strings.ToLower(strings.Map(func(r rune) rune {
+		if r == ' ' || r == '/' {
+			return '-'
+		}
+		return r
+	}, name))
+	plist := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>CFBundleIdentifier</key>
+	<string>%s</string>
+	<key>CFBundleName</key>
+	<string>%s</string>
+	<key>DocSetPlatformFamily</key>
+	<string>go</string>
+	<key>isDashDocset</key>
+	<true/>
+	<key>isJavaScriptEnabled</key>
+	<true/>
+	<key>dashIndexFilePath</key>
+	<string>index.html</string>
+</dict>
+</plist>
+`, xmlEscape(id), xmlEscape(name))
+	return ioutil.WriteFile(filename, []byte(plist), 0644)
+}
+
+func xmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// writeIndex writes the search index of the packages and symbols of ms to a
+// new SQLite database in filename.
+func writeIndex(ctx context.Context, filename string, ms []*internal.Module) (err error) {
+	defer derrors.Wrap(&err, "writeIndex(%q)", filename)
+
+	if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	db, err := sql.Open("sqlite", filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := db.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	for _, q := range []string{
+		`CREATE TABLE searchIndex(id INTEGER PRIMARY KEY, name TEXT, type TEXT, path TEXT)`,
+		`CREATE UNIQUE INDEX anchor ON searchIndex (name, type, path)`,
+	} {
+		if _, err := db.ExecContext(ctx, q); err != nil {
+			return err
+		}
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	stmt, err := tx.PrepareContext(ctx, `INSERT OR IGNORE INTO searchIndex(name, type, path) VALUES (?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, m := range ms {
+		for _, u := range m.Units {
+			if !u.IsPackage() {
+				continue
+			}
+			page := staticsite.UnitFile(m.ModulePath, u.Path)
+			if _, err := stmt.ExecContext(ctx, u.Path, "Package", page); err != nil {
+				return err
+			}
+			// The documentation of non-redistributable packages has been
+			// removed, so they have no symbols.
+			doc := internal.DocumentationForBuildContext(u.Documentation, internal.BuildContextAll)
+			if doc == nil {
+				continue
+			}
+			for _, s := range doc.Symbols {
+				typ, ok := entryTypes[s.Kind]
+				if !ok {
+					continue
+				}
+				if _, err := stmt.ExecContext(ctx, s.Name, typ, page+"#"+s.Name); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return tx.Commit()
+}
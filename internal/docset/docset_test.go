@@ -0,0 +1,150 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package docset
+
+import (
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/safehtml/template"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/fetch"
+	"golang.org/x/pkgsite/internal/godoc/dochtml"
+	"golang.org/x/pkgsite/internal/source"
+	"golang.org/x/pkgsite/internal/testing/testhelper"
+)
+
+var staticPath = template.TrustedSourceFromConstant("../../content/static")
+
+func TestWrite(t *testing.T) {
+	ctx := context.Background()
+	dochtml.LoadTemplates(template.TrustedSourceJoin(staticPath, template.TrustedSourceFromConstant("html/doc")))
+
+	var ms []*internal.Module
+	for _, files := range []map[string]string{
+		{
+			"go.mod":  "module example.com/a\n",
+			"LICENSE": testhelper.BSD0License,
+			"a.go": `// Package a uses b.
+package a
+
+import "example.com/b"
+
+// Max is the maximum.
+const Max = 1
+
+// F returns a b.T.
+func F() b.T { return b.T{} }
+`,
+		},
+		{
+			"go.mod":  "module example.com/b\n",
+			"LICENSE": testhelper.BSD0License,
+			"b.go": `// Package b has a type.
+package b
+
+// T is a type.
+type T struct {
+	// X is a field.
+	X int
+}
+
+// M is a method.
+func (T) M() {}
+
+// V is a variable.
+var V T
+`,
+		},
+	} {
+		moduleDir, err := testhelper.CreateTestDirectory(files)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(moduleDir)
+		res := fetch.FetchLocalModule(ctx, "", moduleDir, source.NewClientForTesting())
+		if res.Error != nil {
+			t.Fatal(res.Error)
+		}
+		ms = append(ms, res.Module)
+	}
+
+	outDir, err := ioutil.TempDir("", "docset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outDir)
+	dir, err := Write(ctx, ms, Config{Name: "Test Docs", OutDir: outDir, StaticPath: staticPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(outDir, "Test Docs.docset"); dir != want {
+		t.Errorf("got directory %q, want %q", dir, want)
+	}
+
+	plist, err := ioutil.ReadFile(filepath.Join(dir, "Contents", "Info.plist"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"<string>test-docs</string>", "<string>Test Docs</string>"} {
+		if !strings.Contains(string(plist), want) {
+			t.Errorf("Info.plist does not contain %q", want)
+		}
+	}
+
+	documents := filepath.Join(dir, "Contents", "Resources", "Documents")
+	page, err := ioutil.ReadFile(filepath.Join(documents, "example.com", "a", "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Links to the other module of the docset stay within it.
+	if want := `href="../b/index.html#T"`; !strings.Contains(string(page), want) {
+		t.Errorf("page of example.com/a does not contain %q", want)
+	}
+	if _, err := os.Stat(filepath.Join(documents, "index.html")); err != nil {
+		t.Error(err)
+	}
+
+	db, err := sql.Open("sqlite", filepath.Join(dir, "Contents", "Resources", "docSet.dsidx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	rows, err := db.Query(`SELECT name, type, path FROM searchIndex ORDER BY path, name`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var got [][3]string
+	for rows.Next() {
+		var e [3]string
+		if err := rows.Scan(&e[0], &e[1], &e[2]); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, e)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	want := [][3]string{
+		{"example.com/a", "Package", "example.com/a/index.html"},
+		{"F", "Function", "example.com/a/index.html#F"},
+		{"Max", "Constant", "example.com/a/index.html#Max"},
+		{"example.com/b", "Package", "example.com/b/index.html"},
+		{"T", "Type", "example.com/b/index.html#T"},
+		{"T.M", "Method", "example.com/b/index.html#T.M"},
+		{"T.X", "Field", "example.com/b/index.html#T.X"},
+		{"V", "Variable", "example.com/b/index.html#V"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("search index mismatch (-want +got):\n%s", diff)
+	}
+}
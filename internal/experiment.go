@@ -9,6 +9,7 @@ const (
 	ExperimentNotAtLatest   = "not-at-latest"
 	ExperimentNotAtV1       = "not-at-v1"
 	ExperimentDirectoryTree = "directory-tree"
+	ExperimentTypeCheck     = "type-check"
 )
 
 // Experiments represents all of the active experiments in the codebase and
@@ -17,6 +18,7 @@ var Experiments = map[string]string{
 	ExperimentNotAtLatest:   "Enable the display of a 'not at latest' badge.",
 	ExperimentNotAtV1:       "Redirect requests to a path not at v1 to the highest major version of that path.",
 	ExperimentDirectoryTree: "Enable the directory tree layout on the unit page.",
+	ExperimentTypeCheck:     "Type-check packages to link identifiers in declarations exactly.",
 }
 
 // Experiment holds data associated with an experimental feature for frontend
@@ -102,7 +102,8 @@ type FetchResult struct {
 
 // FetchModule queries the proxy or the Go repo for the requested module
 // version, downloads the module zip, and processes the contents to return an
-// *internal.Module and related information.
+// *internal.Module and related information. Modules matching the patterns
+// set by SetDirectFetchPatterns are instead built from their repositories.
 //
 // Even if err is non-nil, the result may contain useful information, like the go.mod path.
 //
@@ -139,6 +140,8 @@ func FetchModule(ctx context.Context, modulePath, requestedVersion string, proxy
 		commitTime time.Time
 		zipReader  *zip.Reader
 		zipSize    int64
+		goModBytes []byte
+		vm         *vcsModule // set if the module is fetched from its repository
 		err        error
 	)
 	// Get the just information we need to make a load-shedding decision.
@@ -150,6 +153,15 @@ func FetchModule(ctx context.Context, modulePath, requestedVersion string, proxy
 			return fr
 		}
 		fr.ResolvedVersion = resolvedVersion
+	} else if fetchesDirectly(modulePath) {
+		vm, err = fetchFromVCS(ctx, modulePath, requestedVersion, sourceClient)
+		if err != nil {
+			fr.Error = err
+			return fr
+		}
+		fr.ResolvedVersion = vm.version
+		commitTime = vm.commitTime
+		zipSize = vm.zipSize
 	} else {
 		getInfo := proxyClient.GetInfo
 		if disableProxyFetch {
@@ -207,10 +219,14 @@ func FetchModule(ctx context.Context, modulePath, requestedVersion string, proxy
 		}
 		fr.GoModPath = stdlib.ModulePath
 	} else {
-		goModBytes, err := proxyClient.GetMod(ctx, modulePath, fr.ResolvedVersion)
-		if err != nil {
-			fr.Error = err
-			return fr
+		if vm != nil {
+			goModBytes = vm.goMod
+		} else {
+			goModBytes, err = proxyClient.GetMod(ctx, modulePath, fr.ResolvedVersion)
+			if err != nil {
+				fr.Error = err
+				return fr
+			}
 		}
 		goModPath := modfile.ModulePath(goModBytes)
 		if goModPath == "" {
@@ -225,10 +241,14 @@ func FetchModule(ctx context.Context, modulePath, requestedVersion string, proxy
 			fr.Error = fmt.Errorf("module path=%s, go.mod path=%s: %w", modulePath, goModPath, derrors.AlternativeModule)
 			return fr
 		}
-		zipReader, err = proxyClient.GetZip(ctx, modulePath, fr.ResolvedVersion)
-		if err != nil {
-			fr.Error = err
-			return fr
+		if vm != nil {
+			zipReader = vm.zip
+		} else {
+			zipReader, err = proxyClient.GetZip(ctx, modulePath, fr.ResolvedVersion)
+			if err != nil {
+				fr.Error = err
+				return fr
+			}
 		}
 	}
 	mod, pvs, err := processZipFile(ctx, modulePath, fr.ResolvedVersion, commitTime, zipReader, sourceClient)
@@ -240,6 +260,10 @@ func FetchModule(ctx context.Context, modulePath, requestedVersion string, proxy
 	fr.PackageVersionStates = pvs
 	if modulePath == stdlib.ModulePath {
 		fr.Module.HasGoMod = true
+	} else if fr.Module.HasGoMod {
+		// The proxy synthesizes a go.mod file for modules without one,
+		// which has nothing worth keeping.
+		fr.Module.GoMod = parseGoMod(goModBytes)
 	}
 	for _, state := range fr.PackageVersionStates {
 		if state.Status != http.StatusOK {
@@ -264,6 +288,10 @@ func processZipFile(ctx context.Context, modulePath string, resolvedVersion stri
 	if err != nil {
 		return nil, nil, fmt.Errorf("extractReadmesFromZip(%q, %q, zipReader): %v", modulePath, resolvedVersion, err)
 	}
+	sourceFiles, err := extractSourceFilesFromZip(modulePath, resolvedVersion, zipReader)
+	if err != nil {
+		return nil, nil, err
+	}
 	logf := func(format string, args ...interface{}) {
 		log.Infof(ctx, format, args...)
 	}
@@ -287,8 +315,9 @@ func processZipFile(ctx context.Context, modulePath string, resolvedVersion stri
 			HasGoMod:          hasGoMod,
 			SourceInfo:        sourceInfo,
 		},
-		Licenses: allLicenses,
-		Units:    moduleUnits(modulePath, resolvedVersion, packages, readmes, d),
+		Licenses:    allLicenses,
+		Units:       moduleUnits(modulePath, resolvedVersion, packages, readmes, d),
+		SourceFiles: sourceFiles,
 	}, packageVersionStates, nil
 }
 
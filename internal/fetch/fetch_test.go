@@ -82,8 +82,12 @@ func TestFetchModule(t *testing.T) {
 		}{
 			{name: "proxy", fetch: proxyFetcher},
 			{name: "local", fetch: localFetcher},
+			{name: "zip", fetch: zipFetcher},
 		} {
-			if test.proxyOnly && fetcher.name == "local" {
+			// The zip fetcher fetches a zip of the local directory, so its
+			// results are the same as those of the local fetcher.
+			local := fetcher.name != "proxy"
+			if test.proxyOnly && local {
 				continue
 			}
 			t.Run(fmt.Sprintf("%s:%s", fetcher.name, test.name), func(t *testing.T) {
@@ -100,17 +104,19 @@ func TestFetchModule(t *testing.T) {
 					test.mod.fr = cleanFetchResult(t, test.mod.fr, d)
 					test.cleaned = true
 				}
-				fr := updateFetchResultVersions(t, test.mod.fr, fetcher.name == "local")
+				fr := updateFetchResultVersions(t, test.mod.fr, local)
 				sortFetchResult(fr)
 				sortFetchResult(got)
+				keepFirstBuildContext(got)
 				opts := []cmp.Option{
-					cmpopts.IgnoreFields(internal.Documentation{}, "Source"),
+					cmpopts.IgnoreFields(internal.Documentation{}, "Source", "Symbols", "Coverage"),
 					cmpopts.IgnoreFields(internal.PackageVersionState{}, "Error"),
+					cmpopts.IgnoreFields(internal.Module{}, "SourceFiles", "GoMod"),
 					cmpopts.IgnoreFields(FetchResult{}, "Defer"),
 					cmp.AllowUnexported(source.Info{}),
 					cmpopts.EquateEmpty(),
 				}
-				if fetcher.name == "local" {
+				if local {
 					opts = append(opts,
 						[]cmp.Option{
 							// Pre specified for all modules
@@ -170,6 +176,7 @@ func TestFetchModule_Errors(t *testing.T) {
 		}{
 			{name: "proxy", fetch: proxyFetcher},
 			{name: "local", fetch: localFetcher},
+			{name: "zip", fetch: zipFetcher},
 		} {
 			t.Run(fmt.Sprintf("%s:%s", fetcher.name, test.name), func(t *testing.T) {
 				got, _ := fetcher.fetch(t, false, ctx, test.mod, "")
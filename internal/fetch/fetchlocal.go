@@ -13,11 +13,13 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
 	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/source"
 )
@@ -82,7 +84,8 @@ func FetchLocalModule(ctx context.Context, modulePath, localPath string, sourceC
 	// Errors:
 	//   - Both are given and are different.
 	//   - Neither is given.
-	if goModBytes, err := ioutil.ReadFile(filepath.Join(localPath, "go.mod")); err != nil {
+	goModBytes, err := ioutil.ReadFile(filepath.Join(localPath, "go.mod"))
+	if err != nil {
 		fr.GoModPath = modulePath
 	} else {
 		fr.GoModPath = modfile.ModulePath(goModBytes)
@@ -113,6 +116,9 @@ func FetchLocalModule(ctx context.Context, modulePath, localPath string, sourceC
 	fr.Module = mod
 	fr.PackageVersionStates = pvs
 	fr.Module.SourceInfo = nil // version is not known, so even if info is found it most likely is wrong.
+	if goModBytes != nil {
+		fr.Module.GoMod = parseGoMod(goModBytes)
+	}
 	for _, state := range fr.PackageVersionStates {
 		if state.Status != http.StatusOK {
 			fr.Status = derrors.ToStatus(derrors.HasIncompletePackages)
@@ -121,6 +127,98 @@ func FetchLocalModule(ctx context.Context, modulePath, localPath string, sourceC
 	return fr
 }
 
+// FetchZipModule processes the contents of a module zip, in the format served
+// by the module proxy, to return an internal.Module and other related
+// information. The module path and version are taken from the zip's contents
+// directory. The commit time is not known, so it is left as LocalCommitTime.
+// FetchResult.Error should be checked to verify that the fetch succeeded. Even
+// if the error is non-nil the result may contain useful data.
+func FetchZipModule(ctx context.Context, zipReader *zip.Reader, sourceClient *source.Client) *FetchResult {
+	fr := &FetchResult{Defer: func() {}}
+	defer func() {
+		if fr.Error != nil {
+			derrors.Wrap(&fr.Error, "FetchZipModule(%q, %q)", fr.ModulePath, fr.ResolvedVersion)
+			fr.Status = derrors.ToStatus(fr.Error)
+		}
+		if fr.Status == 0 {
+			fr.Status = http.StatusOK
+		}
+	}()
+
+	modulePath, version, err := zipModuleVersion(zipReader)
+	if err != nil {
+		fr.Error = err
+		return fr
+	}
+	fr.ModulePath = modulePath
+	fr.RequestedVersion = version
+	fr.ResolvedVersion = version
+	fr.GoModPath = modulePath
+	var goModBytes []byte
+	if f := zipFile(zipReader, path.Join(moduleVersionDir(modulePath, version), "go.mod")); f != nil {
+		goModBytes, err = readZipFile(f, MaxFileSize)
+		if err != nil {
+			fr.Error = err
+			return fr
+		}
+		fr.GoModPath = modfile.ModulePath(goModBytes)
+		if fr.GoModPath != modulePath {
+			fr.Error = fmt.Errorf("module path=%s, go.mod path=%s: %w", modulePath, fr.GoModPath, derrors.AlternativeModule)
+			return fr
+		}
+	}
+
+	mod, pvs, err := processZipFile(ctx, modulePath, version, LocalCommitTime, zipReader, sourceClient)
+	if err != nil {
+		fr.Error = err
+		return fr
+	}
+	fr.Module = mod
+	fr.PackageVersionStates = pvs
+	if goModBytes != nil {
+		fr.Module.GoMod = parseGoMod(goModBytes)
+	}
+	for _, state := range fr.PackageVersionStates {
+		if state.Status != http.StatusOK {
+			fr.Status = derrors.ToStatus(derrors.HasIncompletePackages)
+		}
+	}
+	return fr
+}
+
+// zipModuleVersion returns the module path and version of a module zip, from
+// the name of its contents directory, "<module>@<version>".
+func zipModuleVersion(r *zip.Reader) (modulePath, version string, err error) {
+	if len(r.File) == 0 {
+		return "", "", fmt.Errorf("empty zip: %w", derrors.BadModule)
+	}
+	name := r.File[0].Name
+	i := strings.Index(name, "@")
+	if i <= 0 {
+		return "", "", fmt.Errorf("%q: missing module version: %w", name, derrors.BadModule)
+	}
+	j := strings.Index(name[i:], "/")
+	if j < 0 {
+		return "", "", fmt.Errorf("%q: not in a contents directory: %w", name, derrors.BadModule)
+	}
+	modulePath, version = name[:i], name[i+1:i+j]
+	if err := module.Check(modulePath, version); err != nil {
+		return "", "", fmt.Errorf("%v: %w", err, derrors.BadModule)
+	}
+	return modulePath, version, nil
+}
+
+// zipFile returns the file with the given name in the zip, or nil if there is
+// none.
+func zipFile(r *zip.Reader, name string) *zip.File {
+	for _, f := range r.File {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
 // createZipReader creates a zip file from a directory given a local path and
 // returns a zip.Reader to be passed to processZipFile. The purpose of the
 // function is to transform a local go module into a zip file to be processed by
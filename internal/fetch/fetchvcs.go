@@ -0,0 +1,400 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	modzip "golang.org/x/mod/zip"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/source"
+	"golang.org/x/pkgsite/internal/version"
+)
+
+var (
+	directFetchPatternsMu sync.Mutex
+	// directFetchPatterns match the modules that FetchModule fetches from
+	// their repositories rather than from the proxy.
+	directFetchPatterns string
+)
+
+// SetDirectFetchPatterns sets the modules that FetchModule fetches directly
+// from their git repositories instead of from the proxy. patterns is a
+// comma-separated list of glob patterns, in the syntax of GOPRIVATE, that
+// are matched against prefixes of module paths; "*" matches every module.
+func SetDirectFetchPatterns(patterns string) {
+	directFetchPatternsMu.Lock()
+	defer directFetchPatternsMu.Unlock()
+	directFetchPatterns = patterns
+}
+
+// fetchesDirectly reports whether modulePath should be fetched from its
+// repository instead of from the proxy.
+func fetchesDirectly(modulePath string) bool {
+	directFetchPatternsMu.Lock()
+	defer directFetchPatternsMu.Unlock()
+	return module.MatchPrefixPatterns(directFetchPatterns, modulePath)
+}
+
+// A vcsModule is a version of a module built from its repository, with the
+// same information the proxy would serve for it.
+type vcsModule struct {
+	version    string
+	commitTime time.Time
+	// goMod is the module's go.mod file, or a synthesized one if the module
+	// does not have one.
+	goMod   []byte
+	zip     *zip.Reader
+	zipSize int64
+}
+
+// fetchFromVCS finds the repository of modulePath from its path or go-import
+// meta tag, clones it into memory, and builds the module zip for
+// requestedVersion, which may be a semantic version, "latest", or any git
+// revision, such as a branch name or commit hash. Only git repositories are
+// supported.
+func fetchFromVCS(ctx context.Context, modulePath, requestedVersion string, sourceClient *source.Client) (_ *vcsModule, err error) {
+	defer derrors.Wrap(&err, "fetchFromVCS(%q, %q)", modulePath, requestedVersion)
+
+	info, err := source.ModuleInfo(ctx, sourceClient, modulePath, "")
+	if err != nil {
+		return nil, err
+	}
+	if info == nil || info.CloneURL() == "" {
+		return nil, fmt.Errorf("no repository for module: %w", derrors.NotFound)
+	}
+	repo, err := git.CloneContext(ctx, memory.NewStorage(), nil, &git.CloneOptions{
+		URL:  info.CloneURL(),
+		Tags: git.AllTags,
+	})
+	if err != nil {
+		if errors.Is(err, transport.ErrRepositoryNotFound) || errors.Is(err, transport.ErrAuthenticationRequired) {
+			err = fmt.Errorf("%v: %w", err, derrors.NotFound)
+		}
+		return nil, err
+	}
+	return buildVCSModule(repo, modulePath, info.ModuleDir(), requestedVersion)
+}
+
+// buildVCSModule builds the module zip for modulePath at requestedVersion
+// from repo. dir is the directory of the module in the repository, without
+// any "/vN" major version suffix; the suffix is added if that subdirectory
+// holds the module.
+func buildVCSModule(repo *git.Repository, modulePath, dir, requestedVersion string) (_ *vcsModule, err error) {
+	defer derrors.Wrap(&err, "buildVCSModule(%q, %q, %q)", modulePath, dir, requestedVersion)
+
+	_, pathMajor, ok := module.SplitPathVersion(modulePath)
+	if !ok {
+		return nil, fmt.Errorf("invalid module path: %w", derrors.InvalidArgument)
+	}
+	tagPrefix := ""
+	if dir != "" {
+		tagPrefix = dir + "/"
+	}
+	tags, err := semverTags(repo, tagPrefix, pathMajor)
+	if err != nil {
+		return nil, err
+	}
+	commit, resolvedVersion, err := resolveVersion(repo, tags, pathMajor, requestedVersion)
+	if err != nil {
+		return nil, err
+	}
+	root, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(pathMajor, "/") {
+		// The module may live in a major version subdirectory.
+		if _, err := root.File(path.Join(dir, pathMajor[1:], "go.mod")); err == nil {
+			dir = path.Join(dir, pathMajor[1:])
+		}
+	}
+	modTree := root
+	if dir != "" {
+		modTree, err = root.Tree(dir)
+		if err != nil {
+			return nil, fmt.Errorf("directory %q at %s: %v: %w", dir, resolvedVersion, err, derrors.NotFound)
+		}
+	}
+
+	var goMod []byte
+	if f, err := modTree.File("go.mod"); err == nil {
+		if strings.HasSuffix(resolvedVersion, "+incompatible") {
+			return nil, fmt.Errorf("%s has a go.mod file: %w", resolvedVersion, derrors.NotFound)
+		}
+		contents, err := f.Contents()
+		if err != nil {
+			return nil, err
+		}
+		goMod = []byte(contents)
+	} else {
+		// Like the proxy, synthesize a go.mod file for modules without one.
+		goMod = []byte(fmt.Sprintf("module %s\n", modulePath))
+	}
+
+	files, err := treeFiles(root, modTree, dir)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := modzip.Create(&buf, module.Version{Path: modulePath, Version: resolvedVersion}, files); err != nil {
+		return nil, fmt.Errorf("%v: %w", err, derrors.BadModule)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		return nil, err
+	}
+	return &vcsModule{
+		version:    resolvedVersion,
+		commitTime: commit.Committer.When.UTC(),
+		goMod:      goMod,
+		zip:        zr,
+		zipSize:    int64(buf.Len()),
+	}, nil
+}
+
+// semverTags returns a map from the versions tagged in repo for the module
+// whose tags start with tagPrefix and whose path has the major version
+// suffix pathMajor, to the commits they tag. Tags for major versions 2 and
+// higher of a module without a suffix are mapped as "+incompatible" versions.
+func semverTags(repo *git.Repository, tagPrefix, pathMajor string) (map[string]*object.Commit, error) {
+	refs, err := repo.Tags()
+	if err != nil {
+		return nil, err
+	}
+	tags := map[string]*object.Commit{}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().Short()
+		if !strings.HasPrefix(name, tagPrefix) {
+			return nil
+		}
+		v := strings.TrimPrefix(name, tagPrefix)
+		if !semver.IsValid(v) || semver.Canonical(v) != v || version.IsPseudo(v) {
+			// Ignore tags that the go command would not use as versions.
+			return nil
+		}
+		if module.CheckPathMajor(v, pathMajor) != nil {
+			if pathMajor != "" || semver.Compare(semver.Major(v), "v2") < 0 {
+				return nil
+			}
+			v += "+incompatible"
+		}
+		c, err := tagCommit(repo, ref)
+		if err != nil {
+			return err
+		}
+		tags[v] = c
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// tagCommit returns the commit that ref, a lightweight or annotated tag,
+// points to.
+func tagCommit(repo *git.Repository, ref *plumbing.Reference) (*object.Commit, error) {
+	if t, err := repo.TagObject(ref.Hash()); err == nil {
+		return t.Commit()
+	}
+	return repo.CommitObject(ref.Hash())
+}
+
+// resolveVersion returns the commit that requestedVersion refers to, and its
+// canonical version. Tagged commits have the version of their tag; others
+// get a pseudo-version.
+func resolveVersion(repo *git.Repository, tags map[string]*object.Commit, pathMajor, requestedVersion string) (_ *object.Commit, _ string, err error) {
+	if requestedVersion == internal.LatestVersion {
+		if v := latestTag(tags); v != "" {
+			return tags[v], v, nil
+		}
+		head, err := repo.Head()
+		if err != nil {
+			return nil, "", err
+		}
+		requestedVersion = head.Hash().String()
+	} else if semver.IsValid(requestedVersion) && !version.IsPseudo(requestedVersion) {
+		v := module.CanonicalVersion(requestedVersion)
+		c, ok := tags[v]
+		if !ok {
+			return nil, "", fmt.Errorf("no tag for version %s: %w", v, derrors.NotFound)
+		}
+		return c, v, nil
+	}
+
+	rev := requestedVersion
+	if version.IsPseudo(rev) {
+		rev = strings.TrimSuffix(rev, "+incompatible")
+		rev = rev[strings.LastIndex(rev, "-")+1:]
+	}
+	c, err := resolveRevision(repo, rev)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// A tagged commit has the highest version it is tagged with. Otherwise,
+	// its pseudo-version is based on the highest version tagged on one of
+	// its ancestors.
+	var tagged, older string
+	for v, tc := range tags {
+		if strings.HasSuffix(v, "+incompatible") {
+			continue
+		}
+		if tc.Hash == c.Hash {
+			if semver.Compare(v, tagged) > 0 {
+				tagged = v
+			}
+			continue
+		}
+		if semver.Compare(v, older) <= 0 {
+			continue
+		}
+		isAncestor, err := tc.IsAncestor(c)
+		if err != nil {
+			return nil, "", err
+		}
+		if isAncestor {
+			older = v
+		}
+	}
+	if tagged != "" {
+		return c, tagged, nil
+	}
+	major := semver.Major(older)
+	if pathMajor != "" {
+		major = module.PathMajorPrefix(pathMajor)
+	}
+	return c, version.Pseudo(major, older, c.Committer.When, c.Hash.String()[:12]), nil
+}
+
+// latestTag returns the highest release version in tags, or the highest
+// prerelease version if there are no releases. Compatible versions are
+// preferred to "+incompatible" ones.
+func latestTag(tags map[string]*object.Commit) string {
+	rank := func(v string) int {
+		r := 0
+		if semver.Prerelease(v) == "" {
+			r++
+		}
+		if !strings.HasSuffix(v, "+incompatible") {
+			r += 2
+		}
+		return r
+	}
+	latest := ""
+	for v := range tags {
+		if latest == "" || rank(v) > rank(latest) || (rank(v) == rank(latest) && semver.Compare(v, latest) > 0) {
+			latest = v
+		}
+	}
+	return latest
+}
+
+// resolveRevision returns the commit for rev, which may be a branch or tag
+// name, or a full or abbreviated commit hash.
+func resolveRevision(repo *git.Repository, rev string) (*object.Commit, error) {
+	if h, err := repo.ResolveRevision(plumbing.Revision(rev)); err == nil {
+		return repo.CommitObject(*h)
+	}
+	if len(rev) < 7 || len(rev) >= 40 || strings.Trim(rev, "0123456789abcdef") != "" {
+		return nil, fmt.Errorf("unknown revision %q: %w", rev, derrors.NotFound)
+	}
+	// ResolveRevision does not handle abbreviated hashes, as used in
+	// pseudo-versions.
+	iter, err := repo.CommitObjects()
+	if err != nil {
+		return nil, err
+	}
+	var found *object.Commit
+	err = iter.ForEach(func(c *object.Commit) error {
+		if strings.HasPrefix(c.Hash.String(), rev) {
+			if found != nil {
+				return fmt.Errorf("ambiguous revision %q: %w", rev, derrors.InvalidArgument)
+			}
+			found = c
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, fmt.Errorf("unknown revision %q: %w", rev, derrors.NotFound)
+	}
+	return found, nil
+}
+
+// treeFiles returns the files of modTree, the tree of the module in
+// directory dir of root, for building a module zip. Like the go command, it
+// includes the LICENSE file at the repository root if the module is in a
+// subdirectory without one.
+func treeFiles(root, modTree *object.Tree, dir string) ([]modzip.File, error) {
+	var files []modzip.File
+	hasLicense := false
+	err := modTree.Files().ForEach(func(f *object.File) error {
+		if f.Name == "LICENSE" {
+			hasLicense = true
+		}
+		files = append(files, gitFile{f})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if dir != "" && !hasLicense {
+		if f, err := root.File("LICENSE"); err == nil {
+			files = append(files, gitFile{f})
+		}
+	}
+	return files, nil
+}
+
+// A gitFile is a file in a git tree, as a file for a module zip. Its path
+// is relative to the tree it was found in.
+type gitFile struct {
+	f *object.File
+}
+
+func (g gitFile) Path() string                 { return g.f.Name }
+func (g gitFile) Lstat() (os.FileInfo, error)  { return gitFileInfo{g.f}, nil }
+func (g gitFile) Open() (io.ReadCloser, error) { return g.f.Reader() }
+
+// gitFileInfo implements os.FileInfo for a file in a git tree.
+type gitFileInfo struct {
+	f *object.File
+}
+
+func (i gitFileInfo) Name() string       { return path.Base(i.f.Name) }
+func (i gitFileInfo) Size() int64        { return i.f.Size }
+func (i gitFileInfo) ModTime() time.Time { return time.Time{} }
+func (i gitFileInfo) IsDir() bool        { return false }
+func (i gitFileInfo) Sys() interface{}   { return nil }
+
+func (i gitFileInfo) Mode() os.FileMode {
+	m, err := i.f.Mode.ToOSFileMode()
+	if err != nil {
+		return os.ModeIrregular
+	}
+	return m
+}
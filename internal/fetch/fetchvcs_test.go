@@ -0,0 +1,161 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/testing/testhelper"
+)
+
+func TestBuildVCSModule(t *testing.T) {
+	time1 := time.Date(2020, 9, 1, 10, 0, 0, 0, time.UTC)
+	time2 := time.Date(2020, 9, 2, 11, 30, 0, 0, time.UTC)
+
+	fs := memfs.New()
+	repo, err := git.Init(memory.NewStorage(), fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit := func(files map[string]string, when time.Time) plumbing.Hash {
+		t.Helper()
+		for name, contents := range files {
+			if err := util.WriteFile(fs, name, []byte(contents), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := wt.Add(name); err != nil {
+				t.Fatal(err)
+			}
+		}
+		h, err := wt.Commit("commit", &git.CommitOptions{Author: &object.Signature{
+			Name:  "Joe Random",
+			Email: "joe@example.com",
+			When:  when,
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+	tag := func(name string, h plumbing.Hash) {
+		t.Helper()
+		if _, err := repo.CreateTag(name, h, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	first := commit(map[string]string{
+		"go.mod":     "module example.com/repo\n",
+		"LICENSE":    testhelper.BSD0License,
+		"a.go":       "package a",
+		"sub/go.mod": "module example.com/repo/sub\n",
+		"sub/s.go":   "package sub",
+	}, time1)
+	tag("v1.0.0", first)
+	tag("sub/v0.1.0", first)
+	tag("latest-release", first)
+	second := commit(map[string]string{"a.go": "package a // changed"}, time2)
+	pseudo := "v1.0.1-0.20200902113000-" + second.String()[:12]
+
+	for _, test := range []struct {
+		name, modulePath, dir, version string
+		wantVersion                    string
+		wantCommitTime                 time.Time
+		wantFiles                      []string
+	}{
+		{
+			name:           "tag",
+			modulePath:     "example.com/repo",
+			version:        "v1.0.0",
+			wantVersion:    "v1.0.0",
+			wantCommitTime: time1,
+			wantFiles:      []string{"LICENSE", "a.go", "go.mod"},
+		},
+		{
+			name:           "latest",
+			modulePath:     "example.com/repo",
+			version:        "latest",
+			wantVersion:    "v1.0.0",
+			wantCommitTime: time1,
+			wantFiles:      []string{"LICENSE", "a.go", "go.mod"},
+		},
+		{
+			name:           "branch",
+			modulePath:     "example.com/repo",
+			version:        "master",
+			wantVersion:    pseudo,
+			wantCommitTime: time2,
+			wantFiles:      []string{"LICENSE", "a.go", "go.mod"},
+		},
+		{
+			name:           "pseudo-version",
+			modulePath:     "example.com/repo",
+			version:        pseudo,
+			wantVersion:    pseudo,
+			wantCommitTime: time2,
+			wantFiles:      []string{"LICENSE", "a.go", "go.mod"},
+		},
+		{
+			name:           "tagged commit",
+			modulePath:     "example.com/repo",
+			version:        first.String(),
+			wantVersion:    "v1.0.0",
+			wantCommitTime: time1,
+			wantFiles:      []string{"LICENSE", "a.go", "go.mod"},
+		},
+		{
+			name:           "nested module",
+			modulePath:     "example.com/repo/sub",
+			dir:            "sub",
+			version:        "latest",
+			wantVersion:    "v0.1.0",
+			wantCommitTime: time1,
+			wantFiles:      []string{"LICENSE", "go.mod", "s.go"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			vm, err := buildVCSModule(repo, test.modulePath, test.dir, test.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if vm.version != test.wantVersion {
+				t.Errorf("version = %q, want %q", vm.version, test.wantVersion)
+			}
+			if !vm.commitTime.Equal(test.wantCommitTime) {
+				t.Errorf("commit time = %v, want %v", vm.commitTime, test.wantCommitTime)
+			}
+			prefix := test.modulePath + "@" + test.wantVersion + "/"
+			var files []string
+			for _, f := range vm.zip.File {
+				files = append(files, f.Name[len(prefix):])
+			}
+			sort.Strings(files)
+			if diff := cmp.Diff(test.wantFiles, files); diff != "" {
+				t.Errorf("files mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	for _, v := range []string{"v1.1.0", "nosuchbranch"} {
+		if _, err := buildVCSModule(repo, "example.com/repo", "", v); !errors.Is(err, derrors.NotFound) {
+			t.Errorf("buildVCSModule(%q): got error %v, want NotFound", v, err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/pkgsite/internal"
+)
+
+// parseGoMod returns the directives of the go.mod file with the given
+// contents. go.mod files with directives unknown to the modfile package are
+// parsed leniently, which drops their replace and exclude directives but
+// keeps their retract directives. It
+// returns nil if the file cannot be parsed at all.
+func parseGoMod(contents []byte) *internal.GoMod {
+	f, err := modfile.Parse("go.mod", contents, nil)
+	if err != nil {
+		f, err = modfile.ParseLax("go.mod", contents, nil)
+		if err != nil {
+			return nil
+		}
+	}
+	gm := &internal.GoMod{}
+	if f.Go != nil {
+		gm.GoVersion = f.Go.Version
+	}
+	if f.Module != nil {
+		gm.DeprecationComment, gm.Deprecated = parseDeprecation(f.Module.Syntax)
+	}
+	for _, r := range f.Require {
+		gm.Requires = append(gm.Requires, &internal.GoModRequire{
+			ModulePath: r.Mod.Path,
+			Version:    r.Mod.Version,
+			Indirect:   r.Indirect,
+		})
+	}
+	for _, r := range f.Replace {
+		gm.Replaces = append(gm.Replaces, &internal.GoModReplace{
+			OldPath:    r.Old.Path,
+			OldVersion: r.Old.Version,
+			NewPath:    r.New.Path,
+			NewVersion: r.New.Version,
+		})
+	}
+	for _, e := range f.Exclude {
+		gm.Excludes = append(gm.Excludes, &internal.GoModExclude{
+			ModulePath: e.Mod.Path,
+			Version:    e.Mod.Version,
+		})
+	}
+	for _, r := range f.Retract {
+		gm.Retractions = append(gm.Retractions, &internal.GoModRetraction{
+			Low:       r.Low,
+			High:      r.High,
+			Rationale: r.Rationale,
+		})
+	}
+	return gm
+}
+
+// deprecatedRegexp matches a "Deprecated:" paragraph in the comment of a
+// module directive, as the go command does.
+var deprecatedRegexp = regexp.MustCompile(`(?s)(?:^|\n\n)Deprecated: *(.*?)(?:$|\n\n)`)
+
+// parseDeprecation returns the text of the "Deprecated:" paragraph of the
+// comments before and after the module directive line, and whether there is
+// one.
+func parseDeprecation(line *modfile.Line) (string, bool) {
+	if line == nil {
+		return "", false
+	}
+	var lines []string
+	for _, c := range append(line.Comments.Before, line.Comments.Suffix...) {
+		if !strings.HasPrefix(c.Token, "//") {
+			continue // blank line
+		}
+		lines = append(lines, strings.TrimSpace(strings.TrimPrefix(c.Token, "//")))
+	}
+	m := deprecatedRegexp.FindStringSubmatch(strings.Join(lines, "\n"))
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
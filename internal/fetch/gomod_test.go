@@ -0,0 +1,107 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+)
+
+func TestParseGoMod(t *testing.T) {
+	for _, test := range []struct {
+		name, contents string
+		want           *internal.GoMod
+	}{
+		{
+			name: "all directives",
+			contents: `module example.com/m
+
+go 1.16
+
+require (
+	example.com/a v1.2.3
+	example.com/b v0.1.0 // indirect
+)
+
+replace example.com/a => ../a
+
+replace example.com/b v0.1.0 => example.com/c v0.2.0
+
+exclude example.com/a v1.2.2
+
+retract (
+	v1.0.1 // Published accidentally.
+	[v0.1.0, v0.3.0]
+)
+`,
+			want: &internal.GoMod{
+				GoVersion: "1.16",
+				Requires: []*internal.GoModRequire{
+					{ModulePath: "example.com/a", Version: "v1.2.3"},
+					{ModulePath: "example.com/b", Version: "v0.1.0", Indirect: true},
+				},
+				Replaces: []*internal.GoModReplace{
+					{OldPath: "example.com/a", NewPath: "../a"},
+					{OldPath: "example.com/b", OldVersion: "v0.1.0", NewPath: "example.com/c", NewVersion: "v0.2.0"},
+				},
+				Excludes: []*internal.GoModExclude{
+					{ModulePath: "example.com/a", Version: "v1.2.2"},
+				},
+				Retractions: []*internal.GoModRetraction{
+					{Low: "v1.0.1", High: "v1.0.1", Rationale: "Published accidentally."},
+					{Low: "v0.1.0", High: "v0.3.0"},
+				},
+			},
+		},
+		{
+			name:     "module only",
+			contents: "module example.com/m\n",
+			want:     &internal.GoMod{},
+		},
+		{
+			name:     "unknown directive",
+			contents: "module example.com/m\n\ngo 1.16\n\nfrobnicate example.com/a\n\nrequire example.com/a v1.0.0\n\nexclude example.com/a v0.9.0\n\nretract v1.0.0\n",
+			want: &internal.GoMod{
+				GoVersion:   "1.16",
+				Requires:    []*internal.GoModRequire{{ModulePath: "example.com/a", Version: "v1.0.0"}},
+				Retractions: []*internal.GoModRetraction{{Low: "v1.0.0", High: "v1.0.0"}},
+			},
+		},
+		{
+			name:     "deprecated",
+			contents: "// Deprecated: use example.com/m/v2 instead.\nmodule example.com/m\n",
+			want:     &internal.GoMod{Deprecated: true, DeprecationComment: "use example.com/m/v2 instead."},
+		},
+		{
+			name:     "deprecated in suffix comment",
+			contents: "module example.com/m // Deprecated: unmaintained\n",
+			want:     &internal.GoMod{Deprecated: true, DeprecationComment: "unmaintained"},
+		},
+		{
+			name:     "deprecated paragraph",
+			contents: "// Package m does things.\n//\n// Deprecated: see\n// example.com/n.\n//\n// More text.\nmodule example.com/m\n",
+			want:     &internal.GoMod{Deprecated: true, DeprecationComment: "see\nexample.com/n."},
+		},
+		{
+			name:     "not a deprecation paragraph",
+			contents: "// This is not Deprecated: really.\nmodule example.com/m\n",
+			want:     &internal.GoMod{},
+		},
+		{
+			name:     "malformed",
+			contents: "module example.com/m\n\nrequire (\n",
+			want:     nil,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := parseGoMod([]byte(test.contents))
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
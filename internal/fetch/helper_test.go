@@ -73,10 +73,15 @@ func cleanFetchResult(t *testing.T, fr *FetchResult, detector *licenses.Detector
 			IsRedistributable: u.IsRedistributable,
 			Licenses:          u.Licenses,
 		}
-		if u.Documentation != nil {
-			if u.Documentation.GOOS == "" {
-				u.Documentation.GOOS = "linux"
-				u.Documentation.GOARCH = "amd64"
+		for _, d := range u.Documentation {
+			if d.GOOS == "" {
+				d.GOOS = "linux"
+				d.GOARCH = "amd64"
+			}
+		}
+		if u.BuildContexts == nil {
+			for _, d := range u.Documentation {
+				u.BuildContexts = append(u.BuildContexts, d.BuildContext())
 			}
 		}
 		if u.IsPackage() && shouldSetPVS {
@@ -173,6 +178,34 @@ func localFetcher(t *testing.T, withLicenseDetector bool, ctx context.Context, m
 	return got, d
 }
 
+// zipFetcher is a helper function that creates a module zip from a test
+// directory holding a module, fetches the module from the zip using
+// FetchZipModule, and returns a fetch result and a license detector. The zip
+// has version LocalVersion, so that the result matches that of localFetcher.
+func zipFetcher(t *testing.T, withLicenseDetector bool, ctx context.Context, mod *testModule, fetchVersion string) (*FetchResult, *licenses.Detector) {
+	t.Helper()
+
+	directory, err := testhelper.CreateTestDirectory(mod.mod.Files)
+	if err != nil {
+		t.Fatalf("couldn't create test files")
+	}
+	defer os.RemoveAll(directory)
+
+	modulePath := mod.mod.ModulePath
+	zipReader, err := createZipReader(directory, modulePath, LocalVersion)
+	if err != nil {
+		t.Fatal("couldn't create zip reader")
+	}
+	got := FetchZipModule(ctx, zipReader, source.NewClientForTesting())
+	if !withLicenseDetector {
+		return got, nil
+	}
+	d := licenses.NewDetector(modulePath, LocalVersion, zipReader, func(format string, args ...interface{}) {
+		log.Infof(ctx, format, args...)
+	})
+	return got, d
+}
+
 func licenseDetector(ctx context.Context, t *testing.T, modulePath, version string, proxyClient *proxy.Client) *licenses.Detector {
 	t.Helper()
 	var (
@@ -212,3 +245,19 @@ func sortFetchResult(fr *FetchResult) {
 		})
 	}
 }
+
+// keepFirstBuildContext removes from the units of fr the documentation for
+// all but the first build context, since the expected results in
+// fetchdata_test.go only list that one.
+func keepFirstBuildContext(fr *FetchResult) {
+	if fr.Module == nil {
+		return
+	}
+	for _, u := range fr.Module.Units {
+		if len(u.Documentation) > 1 {
+			bc := u.Documentation[0].BuildContext()
+			u.Documentation = u.Documentation[:1]
+			u.BuildContexts = []internal.BuildContext{bc}
+		}
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"sync"
 
 	"go.opencensus.io/trace"
 	"golang.org/x/pkgsite/internal"
@@ -44,19 +45,41 @@ type BadPackageError struct {
 
 func (bpe *BadPackageError) Error() string { return bpe.Err.Error() }
 
-// Go environments used to construct build contexts in loadPackage.
-var goEnvs = []struct{ GOOS, GOARCH string }{
-	{"linux", "amd64"},
-	{"windows", "amd64"},
-	{"darwin", "amd64"},
-	{"js", "wasm"},
+var (
+	buildContextsMu sync.Mutex
+	// buildContexts are the build contexts tried by loadPackage, in order.
+	buildContexts = internal.DefaultBuildContexts
+)
+
+// SetBuildContexts sets the build contexts used to process packages, in the
+// order they are tried. The first build context that results in a valid
+// package determines the package's name and imports. It returns an error if
+// bcs is not a valid list of build contexts; see
+// internal.ValidateBuildContexts.
+func SetBuildContexts(bcs []internal.BuildContext) error {
+	if err := internal.ValidateBuildContexts(bcs); err != nil {
+		return err
+	}
+	buildContextsMu.Lock()
+	defer buildContextsMu.Unlock()
+	buildContexts = append([]internal.BuildContext(nil), bcs...)
+	return nil
+}
+
+// BuildContexts returns the build contexts used to process packages.
+func BuildContexts() []internal.BuildContext {
+	buildContextsMu.Lock()
+	defer buildContextsMu.Unlock()
+	return buildContexts
 }
 
 // loadPackage loads a Go package by calling loadPackageWithBuildContext, trying
-// several build contexts in turn. It returns a goPackage with documentation
-// information for each build context that results in a valid package, in the
-// same order that the build contexts are listed. If none of them result in a
-// package, then loadPackage returns nil, nil.
+// each of the configured build contexts (see SetBuildContexts) in turn. It
+// returns a goPackage with documentation information for each build context
+// that results in a valid package, in the same order that the build contexts
+// are listed; the build contexts that succeeded are recorded in the
+// goPackage's buildContexts field. If none of them result in a package, then
+// loadPackage returns nil, nil.
 //
 // If a package is fine except that its documentation is too large, loadPackage
 // returns a goPackage whose err field is a non-nil error with godoc.ErrTooLarge in its chain.
@@ -76,8 +99,8 @@ func loadPackage(ctx context.Context, zipGoFiles []*zip.File, innerPath string,
 		files[name] = b
 	}
 
-	for _, env := range goEnvs {
-		pkg, err := loadPackageWithBuildContext(ctx, env.GOOS, env.GOARCH, files, innerPath, sourceInfo, modInfo)
+	for _, bc := range BuildContexts() {
+		pkg, err := loadPackageWithBuildContext(ctx, bc, files, innerPath, sourceInfo, modInfo)
 		if err != nil && !errors.Is(err, godoc.ErrTooLarge) && !errors.Is(err, derrors.NotFound) {
 			return nil, err
 		}
@@ -114,6 +137,7 @@ func mergePackages(pkgs []*goPackage) (*goPackage, error) {
 			}
 		}
 		result.docs = append(result.docs, pkg.docs[0])
+		result.buildContexts = append(result.buildContexts, pkg.buildContexts[0])
 	}
 	return result, nil
 }
@@ -122,7 +146,7 @@ func mergePackages(pkgs []*goPackage) (*goPackage, error) {
 var httpPost = http.Post
 
 // loadPackageWithBuildContext loads a Go package made of .go files in zipGoFiles
-// using a build context constructed from the given GOOS and GOARCH values.
+// using a build context constructed from bc.
 // modulePath is stdlib.ModulePath for the Go standard library and the module
 // path for all other modules. innerPath is the path of the Go package directory
 // relative to the module root.
@@ -136,12 +160,13 @@ var httpPost = http.Post
 // or all .go files have been excluded by constraints.
 // A *BadPackageError error is returned if the directory
 // contains .go files but do not make up a valid package.
-func loadPackageWithBuildContext(ctx context.Context, goos, goarch string, files map[string][]byte, innerPath string, sourceInfo *source.Info, modInfo *godoc.ModuleInfo) (_ *goPackage, err error) {
+func loadPackageWithBuildContext(ctx context.Context, bc internal.BuildContext, files map[string][]byte, innerPath string, sourceInfo *source.Info, modInfo *godoc.ModuleInfo) (_ *goPackage, err error) {
 	modulePath := modInfo.ModulePath
-	defer derrors.Wrap(&err, "loadPackageWithBuildContext(%q, %q, files, %q, %q, %+v)",
-		goos, goarch, innerPath, modulePath, sourceInfo)
+	defer derrors.Wrap(&err, "loadPackageWithBuildContext(%q, files, %q, %q, %+v)",
+		bc, innerPath, modulePath, sourceInfo)
 
-	packageName, goFiles, fset, err := loadFilesWithBuildContext(innerPath, goos, goarch, files)
+	goos, goarch := bc.GOOS, bc.GOARCH
+	packageName, goFiles, fset, err := loadFilesWithBuildContext(innerPath, bc, files)
 	if err != nil {
 		return nil, err
 	}
@@ -157,11 +182,12 @@ func loadPackageWithBuildContext(ctx context.Context, goos, goarch string, files
 		docPkg.AddFile(pf, removeNodes)
 	}
 
-	// Encode first, because Render messes with the AST.
+	// Encode and collect symbols first, because Render messes with the AST.
 	src, err := docPkg.Encode(ctx)
 	if err != nil {
 		return nil, err
 	}
+	symbols := docPkg.Symbols()
 
 	synopsis, imports, _, err := docPkg.Render(ctx, innerPath, sourceInfo, modInfo, goos, goarch)
 	if err != nil && !errors.Is(err, godoc.ErrTooLarge) {
@@ -173,26 +199,28 @@ func loadPackageWithBuildContext(ctx context.Context, goos, goarch string, files
 	}
 	v1path := internal.V1Path(importPath, modulePath)
 	return &goPackage{
-		path:    importPath,
-		name:    packageName,
-		v1path:  v1path,
-		imports: imports,
+		path:          importPath,
+		name:          packageName,
+		v1path:        v1path,
+		imports:       imports,
+		buildContexts: []internal.BuildContext{bc},
 		docs: []*internal.Documentation{{
 			GOOS:     goos,
 			GOARCH:   goarch,
 			Synopsis: synopsis,
 			Source:   src,
+			Symbols:  symbols,
+			Coverage: docPkg.Coverage(),
 		}},
 	}, err
 }
 
-// loadFilesWithBuildContext loads all the Go files at innerPath that match goos
-// and goarch in the zip. It returns the package name as it occurs in the
-// source, a map of the ASTs of all the Go files, and the token.FileSet used for
-// parsing.
-func loadFilesWithBuildContext(innerPath, goos, goarch string, allFiles map[string][]byte) (pkgName string, fileMap map[string]*ast.File, _ *token.FileSet, _ error) {
+// loadFilesWithBuildContext loads all the Go files at innerPath that match bc
+// in the zip. It returns the package name as it occurs in the source, a map of
+// the ASTs of all the Go files, and the token.FileSet used for parsing.
+func loadFilesWithBuildContext(innerPath string, bc internal.BuildContext, allFiles map[string][]byte) (pkgName string, fileMap map[string]*ast.File, _ *token.FileSet, _ error) {
 	// Apply build constraints to get a map from matching file names to their contents.
-	files, err := matchingFiles(goos, goarch, allFiles)
+	files, err := matchingFiles(bc, allFiles)
 	if err != nil {
 		return "", nil, nil, err
 	}
@@ -241,15 +269,16 @@ func loadFilesWithBuildContext(innerPath, goos, goarch string, allFiles map[stri
 }
 
 // matchingFiles returns a map from file names to their contents, read from zipGoFiles.
-// It includes only those files that match the build context determined by goos and goarch.
-func matchingFiles(goos, goarch string, allFiles map[string][]byte) (matchedFiles map[string][]byte, err error) {
-	defer derrors.Wrap(&err, "matchingFiles(%q, %q, zipGoFiles)", goos, goarch)
+// It includes only those files that match the build context bc.
+func matchingFiles(bc internal.BuildContext, allFiles map[string][]byte) (matchedFiles map[string][]byte, err error) {
+	defer derrors.Wrap(&err, "matchingFiles(%q, zipGoFiles)", bc)
 
 	// bctx is used to make decisions about which of the .go files are included
 	// by build constraints.
 	bctx := &build.Context{
-		GOOS:        goos,
-		GOARCH:      goarch,
+		GOOS:        bc.GOOS,
+		GOARCH:      bc.GOARCH,
+		BuildTags:   bc.Tags,
 		CgoEnabled:  true,
 		Compiler:    build.Default.Compiler,
 		ReleaseTags: build.Default.ReleaseTags,
@@ -36,9 +36,18 @@ func TestMatchingFiles(t *testing.T) {
 		"LICENSE.md": testhelper.MITLicense,
 		"js.go":      jsGoBody,
 	}
+	tagGoBody := `
+		// +build linux,mytag
+
+		package tag
+		type Value int`
+	tagContents := map[string]string{
+		"tag.go": tagGoBody,
+	}
 	for _, test := range []struct {
 		name         string
 		goos, goarch string
+		tags         []string
 		contents     map[string]string
 		want         map[string][]byte
 	}{
@@ -76,13 +85,30 @@ func TestMatchingFiles(t *testing.T) {
 				"js.go": []byte(jsGoBody),
 			},
 		},
+		{
+			name:     "tag-missing",
+			goos:     "linux",
+			goarch:   "amd64",
+			contents: tagContents,
+			want:     map[string][]byte{},
+		},
+		{
+			name:     "tag-present",
+			goos:     "linux",
+			goarch:   "amd64",
+			tags:     []string{"mytag"},
+			contents: tagContents,
+			want: map[string][]byte{
+				"tag.go": []byte(tagGoBody),
+			},
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			files := map[string][]byte{}
 			for n, c := range test.contents {
 				files[n] = []byte(c)
 			}
-			got, err := matchingFiles(test.goos, test.goarch, files)
+			got, err := matchingFiles(internal.BuildContext{GOOS: test.goos, GOARCH: test.goarch, Tags: test.tags}, files)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -107,10 +133,11 @@ func TestMergePackages(t *testing.T) {
 
 	pkg := func(name, imp string, doc *internal.Documentation, err error) *goPackage {
 		return &goPackage{
-			name:    name,
-			imports: []string{imp},
-			docs:    []*internal.Documentation{doc},
-			err:     err,
+			name:          name,
+			imports:       []string{imp},
+			docs:          []*internal.Documentation{doc},
+			buildContexts: []internal.BuildContext{doc.BuildContext()},
+			err:           err,
 		}
 	}
 
@@ -138,9 +165,10 @@ func TestMergePackages(t *testing.T) {
 				pkg("name1", "imp2", doc2, nil),
 			},
 			want: &goPackage{
-				name:    "name1",
-				imports: []string{"imp1"},                      // keep the first one
-				docs:    []*internal.Documentation{doc1, doc2}, // keep both, in order
+				name:          "name1",
+				imports:       []string{"imp1"},                      // keep the first one
+				docs:          []*internal.Documentation{doc1, doc2}, // keep both, in order
+				buildContexts: []internal.BuildContext{doc1.BuildContext(), doc2.BuildContext()},
 			},
 		},
 		{
@@ -174,3 +202,21 @@ func TestMergePackages(t *testing.T) {
 		})
 	}
 }
+
+func TestSetBuildContexts(t *testing.T) {
+	defer func(bcs []internal.BuildContext) { buildContexts = bcs }(BuildContexts())
+
+	if err := SetBuildContexts(nil); err == nil {
+		t.Error("SetBuildContexts(nil): got nil, want error")
+	}
+	want := []internal.BuildContext{
+		{GOOS: "linux", GOARCH: "arm64"},
+		{GOOS: "freebsd", GOARCH: "amd64", Tags: []string{"cgo"}},
+	}
+	if err := SetBuildContexts(want); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, BuildContexts()); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
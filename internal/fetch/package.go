@@ -37,7 +37,10 @@ type goPackage struct {
 	// series.
 	v1path string
 	docs   []*internal.Documentation // doc for different build contexts
-	err    error                     // non-fatal error when loading the package (e.g. documentation is too large)
+	// buildContexts are the build contexts that resulted in a valid package,
+	// in the same order as docs.
+	buildContexts []internal.BuildContext
+	err           error // non-fatal error when loading the package (e.g. documentation is too large)
 }
 
 // extractPackagesFromZip returns a slice of packages from the module zip r.
@@ -47,7 +50,7 @@ type goPackage struct {
 // that they contained .go files but couldn't be processed due to current
 // limitations of this site. The limitations are:
 // * a maximum file size (MaxFileSize)
-// * the particular set of build contexts we consider (goEnvs)
+// * the particular set of build contexts we consider (see SetBuildContexts)
 // * whether the import path is valid.
 func extractPackagesFromZip(ctx context.Context, modulePath, resolvedVersion string, r *zip.Reader, d *licenses.Detector, sourceInfo *source.Info) (_ []*goPackage, _ []*internal.PackageVersionState, err error) {
 	defer derrors.Wrap(&err, "extractPackagesFromZip(ctx, %q, %q, r, d)", modulePath, resolvedVersion)
@@ -243,8 +246,8 @@ func extractPackagesFromZip(ctx context.Context, modulePath, resolvedVersion str
 // The logic of the go tool for ignoring directories is documented at
 // https://golang.org/cmd/go/#hdr-Package_lists_and_patterns:
 //
-// 	Directory and file names that begin with "." or "_" are ignored
-// 	by the go tool, as are directories named "testdata".
+//	Directory and file names that begin with "." or "_" are ignored
+//	by the go tool, as are directories named "testdata".
 //
 // However, even though `go list` and other commands that take package
 // wildcards will ignore these, they can still be imported and used in
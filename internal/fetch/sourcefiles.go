@@ -0,0 +1,39 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"archive/zip"
+	"strings"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// extractSourceFilesFromZip returns the path and contents of all .go files
+// in r, for the source viewer. Files larger than MaxFileSize are skipped.
+func extractSourceFilesFromZip(modulePath, resolvedVersion string, r *zip.Reader) (_ []*internal.SourceFile, err error) {
+	defer derrors.Wrap(&err, "extractSourceFilesFromZip(%q, %q, r)", modulePath, resolvedVersion)
+
+	prefix := moduleVersionDir(modulePath, resolvedVersion) + "/"
+	var files []*internal.SourceFile
+	for _, f := range r.File {
+		if !strings.HasSuffix(f.Name, ".go") || !strings.HasPrefix(f.Name, prefix) {
+			continue
+		}
+		if f.UncompressedSize64 > MaxFileSize {
+			continue
+		}
+		c, err := readZipFile(f, MaxFileSize)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &internal.SourceFile{
+			Path:     strings.TrimPrefix(f.Name, prefix),
+			Contents: c,
+		})
+	}
+	return files, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/proxy"
+)
+
+func TestExtractSourceFilesFromZip(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	const modulePath = "github.com/my/module"
+	proxyClient, teardownProxy := proxy.SetupTestClient(t, []*proxy.Module{{
+		ModulePath: modulePath,
+		Files: map[string]string{
+			"a.go":                 "package a",
+			"a_test.go":            "package a_test",
+			"b/b.go":               "package b",
+			"b/testdata/x.go.txt":  "not Go",
+			"b/testdata/y/main.go": "package main",
+			"README.md":            "README",
+		},
+	}})
+	defer teardownProxy()
+	reader, err := proxyClient.GetZip(ctx, modulePath, "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := extractSourceFilesFromZip(modulePath, "v1.0.0", reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i].Path < got[j].Path })
+	want := []*internal.SourceFile{
+		{Path: "a.go", Contents: []byte("package a")},
+		{Path: "a_test.go", Contents: []byte("package a_test")},
+		{Path: "b/b.go", Contents: []byte("package b")},
+		{Path: "b/testdata/y/main.go", Contents: []byte("package main")},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -61,8 +61,9 @@ func moduleUnits(modulePath, version string,
 		if pkg, ok := pkgLookup[dirPath]; ok {
 			dir.Name = pkg.name
 			dir.Imports = pkg.imports
-			// TODO(golang/go#37232): keep all docs
-			dir.Documentation = pkg.docs[0]
+			dir.Documentation = pkg.docs
+			dir.BuildContexts = append([]internal.BuildContext(nil), pkg.buildContexts...)
+			internal.SortBuildContexts(dir.BuildContexts)
 		}
 		units = append(units, dir)
 	}
@@ -68,7 +68,7 @@ func (s *Server) servePathNotFoundPage(w http.ResponseWriter, r *http.Request,
 		if err != nil {
 			log.Error(ctx, err)
 		}
-		return pathNotFoundError(fullPath, requestedVersion)
+		return pathNotFoundError(ctx, db, fullPath, requestedVersion)
 	}
 	switch fr.status {
 	case http.StatusFound, derrors.ToStatus(derrors.AlternativeModule):
@@ -77,7 +77,7 @@ func (s *Server) servePathNotFoundPage(w http.ResponseWriter, r *http.Request,
 		http.Redirect(w, r, constructUnitURL(fr.goModPath, fr.goModPath, internal.LatestVersion), http.StatusFound)
 		return
 	case http.StatusInternalServerError:
-		return pathNotFoundError(fullPath, requestedVersion)
+		return pathNotFoundError(ctx, db, fullPath, requestedVersion)
 	default:
 		return &serverError{
 			status: fr.status,
@@ -91,9 +91,23 @@ func (s *Server) servePathNotFoundPage(w http.ResponseWriter, r *http.Request,
 	}
 }
 
+// maxPathSuggestions is the maximum number of similar paths suggested on the
+// 404 page.
+const maxPathSuggestions = 5
+
+// fetchPageData is the MessageData of fetch.tmpl.
+type fetchPageData struct {
+	// Path is the path that was not found, with the requested version if
+	// there is one.
+	Path string
+	// Suggestions are existing paths similar to the path.
+	Suggestions []string
+}
+
 // pathNotFoundError returns a page with an option on how to
-// add a package or module to the site.
-func pathNotFoundError(fullPath, requestedVersion string) error {
+// add a package or module to the site, and with the paths in the database
+// that are similar to fullPath, in case it was mistyped.
+func pathNotFoundError(ctx context.Context, db *postgres.DB, fullPath, requestedVersion string) error {
 	if !isSupportedVersion(fullPath, requestedVersion) {
 		return invalidVersionError(fullPath, requestedVersion)
 	}
@@ -104,11 +118,16 @@ func pathNotFoundError(fullPath, requestedVersion string) error {
 	if requestedVersion != internal.LatestVersion {
 		path = fmt.Sprintf("%s@%s", fullPath, requestedVersion)
 	}
+	suggestions, err := db.SimilarPaths(ctx, fullPath, maxPathSuggestions)
+	if err != nil {
+		// The page is still useful without suggestions.
+		log.Error(ctx, err)
+	}
 	return &serverError{
 		status: http.StatusNotFound,
 		epage: &errorPage{
 			templateName: "fetch.tmpl",
-			MessageData:  path,
+			MessageData:  fetchPageData{Path: path, Suggestions: suggestions},
 		},
 	}
 }
@@ -0,0 +1,309 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/licenses"
+	"golang.org/x/pkgsite/internal/log"
+)
+
+// apiUnitPrefix is the URL path prefix for the unit metadata endpoint of the
+// JSON API.
+const apiUnitPrefix = "/v1/unit/"
+
+// APIUnit is the JSON representation of a unit served by the API.
+type APIUnit struct {
+	Path              string    `json:"path"`
+	ModulePath        string    `json:"modulePath"`
+	Version           string    `json:"version"`
+	Name              string    `json:"name,omitempty"`
+	CommitTime        time.Time `json:"commitTime"`
+	IsPackage         bool      `json:"isPackage"`
+	IsModule          bool      `json:"isModule"`
+	IsCommand         bool      `json:"isCommand"`
+	IsRedistributable bool      `json:"isRedistributable"`
+	HasGoMod          bool      `json:"hasGoMod"`
+	Synopsis          string    `json:"synopsis,omitempty"`
+	RepositoryURL     string    `json:"repositoryURL,omitempty"`
+
+	Licenses       []*APILicense       `json:"licenses"`
+	Imports        []string            `json:"imports"`
+	Subdirectories []*APIPackage       `json:"subdirectories"`
+	Latest         *APILatest          `json:"latest,omitempty"`
+	BuildContexts  []string            `json:"buildContexts"`
+	Documentation  []*APIDocumentation `json:"documentation"`
+	Coverage       *APICoverage        `json:"coverage,omitempty"`
+}
+
+// APIDocumentation is the JSON representation of the documentation of a unit
+// for one build context.
+type APIDocumentation struct {
+	GOOS     string `json:"goos"`
+	GOARCH   string `json:"goarch"`
+	Synopsis string `json:"synopsis"`
+}
+
+// APICoverage is the documentation coverage of a package; see
+// internal.DocCoverage.
+type APICoverage struct {
+	Percent              int      `json:"percent"`
+	HasPackageDoc        bool     `json:"hasPackageDoc"`
+	NumExported          int      `json:"numExported"`
+	NumDocumented        int      `json:"numDocumented"`
+	Undocumented         []string `json:"undocumented"`
+	NumFuncs             int      `json:"numFuncs"`
+	NumFuncsWithExamples int      `json:"numFuncsWithExamples"`
+	NumExamples          int      `json:"numExamples"`
+}
+
+// APILicense is the JSON representation of the metadata of a license file.
+type APILicense struct {
+	Types    []string `json:"types"`
+	FilePath string   `json:"filePath"`
+}
+
+// APIPackage is the JSON representation of a package in a subdirectory of a
+// unit.
+type APIPackage struct {
+	Path              string `json:"path"`
+	Name              string `json:"name"`
+	Synopsis          string `json:"synopsis,omitempty"`
+	IsRedistributable bool   `json:"isRedistributable"`
+}
+
+// APILatest is the JSON representation of internal.LatestInfo.
+type APILatest struct {
+	MinorVersion      string `json:"minorVersion"`
+	MinorModulePath   string `json:"minorModulePath"`
+	UnitExistsAtMinor bool   `json:"unitExistsAtMinor"`
+	MajorModulePath   string `json:"majorModulePath"`
+	MajorUnitPath     string `json:"majorUnitPath"`
+	// Deprecated reports whether the module is deprecated, with
+	// DeprecationComment as the reason.
+	Deprecated         bool   `json:"deprecated,omitempty"`
+	DeprecationComment string `json:"deprecationComment,omitempty"`
+}
+
+// APIError is the JSON body returned by the API when a request fails.
+type APIError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// apiHandler is like errorHandler, but reports errors to the client as JSON
+// instead of rendering an error page.
+func (s *Server) apiHandler(f func(w http.ResponseWriter, r *http.Request, ds internal.DataSource) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ds := s.getDataSource(r.Context())
+		if err := f(w, r, ds); err != nil {
+			serveAPIError(w, r, err)
+		}
+	}
+}
+
+// serveAPIError writes err to w as an APIError. The status code is taken from
+// err if it is a *serverError, and is derived using derrors.ToStatus
+// otherwise.
+func serveAPIError(w http.ResponseWriter, r *http.Request, err error) {
+	ctx := r.Context()
+	var (
+		status = derrors.ToStatus(err)
+		msg    string
+		serr   *serverError
+	)
+	if errors.As(err, &serr) {
+		status = serr.status
+		msg = serr.responseText
+	}
+	// Non-HTTP codes, like those used for fetch errors, have no meaning to
+	// API clients.
+	if http.StatusText(status) == "" {
+		status = http.StatusInternalServerError
+	}
+	if status == http.StatusInternalServerError {
+		log.Error(ctx, err)
+	} else {
+		log.Infof(ctx, "API returning %d (%s) for error %v", status, http.StatusText(status), err)
+	}
+	if msg == "" {
+		msg = http.StatusText(status)
+	}
+	writeJSON(w, r, status, &APIError{Code: status, Message: msg})
+}
+
+// writeJSON writes v to w as JSON with the given status code. The content type
+// is application/json, unless w already has a Content-Type header.
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		log.Errorf(r.Context(), "writeJSON: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	}
+	w.WriteHeader(status)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		log.Errorf(r.Context(), "writeJSON: %v", err)
+	}
+}
+
+// serveAPIUnit handles requests for unit metadata. It expects paths of the
+// form "/v1/unit/<path>[@<version>]" or "/v1/unit/<module-path>@<version>/<suffix>",
+// which are interpreted the same way as the paths of unit pages. The GOOS and
+// GOARCH query parameters restrict the documentation returned to matching
+// build contexts.
+func (s *Server) serveAPIUnit(w http.ResponseWriter, r *http.Request, ds internal.DataSource) (err error) {
+	defer derrors.Wrap(&err, "serveAPIUnit(%q)", r.URL.Path)
+
+	if r.Method != http.MethodGet {
+		return &serverError{status: http.StatusMethodNotAllowed}
+	}
+	ctx := r.Context()
+	urlPath := "/" + strings.TrimPrefix(r.URL.Path, apiUnitPrefix)
+	info, err := extractURLPathInfo(urlPath)
+	if err != nil {
+		return &serverError{
+			status:       http.StatusBadRequest,
+			responseText: err.Error(),
+			err:          err,
+		}
+	}
+	if !isSupportedVersion(info.fullPath, info.requestedVersion) {
+		return &serverError{
+			status:       http.StatusBadRequest,
+			responseText: fmt.Sprintf("%s is not a valid semantic version", info.requestedVersion),
+		}
+	}
+	if err := checkExcluded(ctx, ds, info.fullPath); err != nil {
+		return err
+	}
+	um, err := ds.GetUnitMeta(ctx, info.fullPath, info.modulePath, info.requestedVersion)
+	if err != nil {
+		return err
+	}
+	fields := internal.WithMain | internal.WithAllDocumentation | internal.WithImports | internal.WithLicenses
+	unit, err := ds.GetUnit(ctx, um, fields, buildContextFromRequest(r))
+	if err != nil {
+		return err
+	}
+	au := newAPIUnit(unit)
+	latest, err := ds.GetLatestInfo(ctx, um.Path, um.ModulePath)
+	if err != nil {
+		// Latest version information is supplementary; don't fail the
+		// request because of it.
+		log.Errorf(ctx, "serveAPIUnit: GetLatestInfo(%q, %q): %v", um.Path, um.ModulePath, err)
+	} else if latest.MinorVersion != "" {
+		au.Latest = &APILatest{
+			MinorVersion:       latest.MinorVersion,
+			MinorModulePath:    latest.MinorModulePath,
+			UnitExistsAtMinor:  latest.UnitExistsAtMinor,
+			MajorModulePath:    latest.MajorModulePath,
+			MajorUnitPath:      latest.MajorUnitPath,
+			Deprecated:         latest.Deprecated,
+			DeprecationComment: latest.DeprecationComment,
+		}
+	}
+	if um.IsPackage() {
+		c, err := unitDocCoverage(ctx, ds, um)
+		if err != nil {
+			log.Errorf(ctx, "serveAPIUnit: unitDocCoverage(%q, %q, %q): %v", um.Path, um.ModulePath, um.Version, err)
+		} else if c != nil {
+			au.Coverage = &APICoverage{
+				Percent:              c.Percent(),
+				HasPackageDoc:        c.HasPackageDoc,
+				NumExported:          c.NumExported,
+				NumDocumented:        c.NumDocumented,
+				Undocumented:         append([]string{}, c.Undocumented...),
+				NumFuncs:             c.NumFuncs,
+				NumFuncsWithExamples: c.NumFuncsWithExamples,
+				NumExamples:          c.NumExamples,
+			}
+		}
+	}
+	writeJSON(w, r, http.StatusOK, au)
+	return nil
+}
+
+// newAPIUnit converts u to its API representation.
+func newAPIUnit(u *internal.Unit) *APIUnit {
+	au := &APIUnit{
+		Path:              u.Path,
+		ModulePath:        u.ModulePath,
+		Version:           u.Version,
+		Name:              u.Name,
+		CommitTime:        u.CommitTime,
+		IsPackage:         u.IsPackage(),
+		IsModule:          u.IsModule(),
+		IsCommand:         u.IsCommand(),
+		IsRedistributable: u.IsRedistributable,
+		HasGoMod:          u.HasGoMod,
+		RepositoryURL:     u.SourceInfo.RepoURL(),
+		Licenses:          []*APILicense{},
+		Imports:           []string{},
+		Subdirectories:    []*APIPackage{},
+		BuildContexts:     []string{},
+		Documentation:     []*APIDocumentation{},
+	}
+	if len(u.Documentation) > 0 {
+		au.Synopsis = u.Documentation[0].Synopsis
+	}
+	for _, bc := range u.BuildContexts {
+		au.BuildContexts = append(au.BuildContexts, bc.GOOS+"/"+bc.GOARCH)
+	}
+	for _, d := range u.Documentation {
+		au.Documentation = append(au.Documentation, &APIDocumentation{
+			GOOS:     d.GOOS,
+			GOARCH:   d.GOARCH,
+			Synopsis: d.Synopsis,
+		})
+	}
+	// Prefer the metadata of the license contents, since it is what the
+	// licenses tab shows. Fall back to the unit metadata for data sources
+	// that do not populate the contents.
+	lms := u.Licenses
+	if len(u.LicenseContents) > 0 {
+		lms = nil
+		for _, l := range u.LicenseContents {
+			lms = append(lms, l.Metadata)
+		}
+	}
+	au.Licenses = append(au.Licenses, apiLicenses(lms)...)
+	au.Imports = append(au.Imports, u.Imports...)
+	for _, p := range u.Subdirectories {
+		if p.Path == u.Path {
+			continue
+		}
+		au.Subdirectories = append(au.Subdirectories, &APIPackage{
+			Path:              p.Path,
+			Name:              p.Name,
+			Synopsis:          p.Synopsis,
+			IsRedistributable: p.IsRedistributable,
+		})
+	}
+	return au
+}
+
+func apiLicenses(lms []*licenses.Metadata) []*APILicense {
+	var als []*APILicense
+	for _, lm := range lms {
+		if lm == nil {
+			continue
+		}
+		als = append(als, &APILicense{Types: lm.Types, FilePath: lm.FilePath})
+	}
+	return als
+}
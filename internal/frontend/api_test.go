@@ -0,0 +1,93 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestServeAPIUnit(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	defer postgres.ResetTestDB(testDB, t)
+	m := sample.DefaultModule()
+	if err := testDB.InsertModule(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+	_, handler, _ := newTestServer(t, nil)
+
+	t.Run("package", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", apiUnitPrefix+sample.PackagePath+"@"+sample.VersionString, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("got status %d, want %d", w.Code, http.StatusOK)
+		}
+		var got APIUnit
+		if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		want := APIUnit{
+			Path:              sample.PackagePath,
+			ModulePath:        sample.ModulePath,
+			Version:           sample.VersionString,
+			Name:              sample.PackageName,
+			IsPackage:         true,
+			IsRedistributable: true,
+			HasGoMod:          true,
+			Synopsis:          sample.Synopsis,
+			RepositoryURL:     sample.RepositoryURL,
+			Licenses:          []*APILicense{{Types: []string{sample.LicenseType}, FilePath: sample.LicenseFilePath}},
+			Imports:           sample.Imports,
+			Subdirectories:    []*APIPackage{},
+			BuildContexts:     []string{sample.GOOS + "/" + sample.GOARCH},
+			Documentation: []*APIDocumentation{
+				{GOOS: sample.GOOS, GOARCH: sample.GOARCH, Synopsis: sample.Synopsis},
+			},
+			Latest: &APILatest{
+				MinorVersion:      sample.VersionString,
+				MinorModulePath:   sample.ModulePath,
+				UnitExistsAtMinor: true,
+				MajorModulePath:   sample.ModulePath,
+				MajorUnitPath:     sample.PackagePath,
+			},
+		}
+		if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(APIUnit{}, "CommitTime"), cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	for _, test := range []struct {
+		name, path string
+		wantCode   int
+	}{
+		{"not found", apiUnitPrefix + "github.com/not/found", http.StatusNotFound},
+		{"bad version", apiUnitPrefix + sample.PackagePath + "@v1.bad", http.StatusBadRequest},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+			if w.Code != test.wantCode {
+				t.Fatalf("got status %d, want %d", w.Code, test.wantCode)
+			}
+			var got APIError
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Code != test.wantCode {
+				t.Errorf("got error code %d, want %d", got.Code, test.wantCode)
+			}
+		})
+	}
+}
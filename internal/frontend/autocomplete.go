@@ -0,0 +1,171 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/postgres"
+)
+
+const (
+	// autocompleteLimit is the number of suggestions returned by the
+	// autocomplete endpoint.
+	autocompleteLimit = 10
+
+	// autocompleteMinPrefix is the length of the shortest query for which
+	// the autocomplete endpoint suggests packages, unless the query contains
+	// a slash. Shorter prefixes match too many packages to be useful.
+	autocompleteMinPrefix = 3
+
+	// openSearchFormat is the value of the format query parameter of the
+	// autocomplete endpoint that selects the OpenSearch suggestions format.
+	openSearchFormat = "opensearch"
+)
+
+// AutocompleteResult is the JSON representation of a package suggested by the
+// autocomplete endpoint.
+type AutocompleteResult struct {
+	PackagePath   string `json:"packagePath"`
+	Name          string `json:"name"`
+	Synopsis      string `json:"synopsis,omitempty"`
+	NumImportedBy uint64 `json:"numImportedBy"`
+}
+
+// serveAutocomplete handles requests for search suggestions, of the form
+// /autocomplete?q=<prefix>. It responds with a JSON list of the most imported
+// packages whose path or name starts with the prefix, or an empty list if the
+// prefix is too short (see autocompletePrefixOK). With format=opensearch,
+// the suggestions are in the format of the OpenSearch suggestions extension,
+// for use by browsers.
+func (s *Server) serveAutocomplete(w http.ResponseWriter, r *http.Request, ds internal.DataSource) (err error) {
+	defer derrors.Wrap(&err, "serveAutocomplete(%q)", r.URL.RawQuery)
+
+	if r.Method != http.MethodGet {
+		return &serverError{status: http.StatusMethodNotAllowed}
+	}
+	db, ok := ds.(*postgres.DB)
+	if !ok {
+		// The proxydatasource has no search index.
+		return proxydatasourceNotSupportedErr()
+	}
+	query := searchQuery(r)
+	if len(query) > maxSearchQueryLength {
+		return &serverError{
+			status:       http.StatusBadRequest,
+			responseText: "Search query too long.",
+		}
+	}
+	var results []*internal.SearchResult
+	if autocompletePrefixOK(query) {
+		results, err = db.Autocomplete(r.Context(), query, autocompleteLimit)
+		if err != nil {
+			return err
+		}
+	}
+	if r.FormValue("format") == openSearchFormat {
+		writeOpenSearchSuggestions(w, r, query, results)
+		return nil
+	}
+	suggestions := []*AutocompleteResult{}
+	for _, r := range results {
+		suggestions = append(suggestions, &AutocompleteResult{
+			PackagePath:   r.PackagePath,
+			Name:          r.Name,
+			Synopsis:      r.Synopsis,
+			NumImportedBy: r.NumImportedBy,
+		})
+	}
+	writeJSON(w, r, http.StatusOK, suggestions)
+	return nil
+}
+
+// autocompletePrefixOK reports whether the autocomplete endpoint looks up
+// suggestions for query: it must have at least autocompleteMinPrefix
+// characters, or contain a slash, as a full first path element such as "k8s/"
+// does.
+func autocompletePrefixOK(query string) bool {
+	return utf8.RuneCountInString(query) >= autocompleteMinPrefix || strings.Contains(query, "/")
+}
+
+// writeOpenSearchSuggestions writes the search suggestions for query in the
+// format of the OpenSearch suggestions extension: an array of the query, the
+// completions and their descriptions. Searching for a completion, which is a
+// package path, leads to the package page.
+func writeOpenSearchSuggestions(w http.ResponseWriter, r *http.Request, query string, results []*internal.SearchResult) {
+	completions := []string{}
+	descriptions := []string{}
+	for _, r := range results {
+		completions = append(completions, r.PackagePath)
+		descriptions = append(descriptions, r.Synopsis)
+	}
+	w.Header().Set("Content-Type", "application/x-suggestions+json; charset=utf-8")
+	writeJSON(w, r, http.StatusOK, []interface{}{query, completions, descriptions})
+}
+
+// openSearchDescription is an OpenSearch description document, which lets
+// browsers add the site as a search engine.
+type openSearchDescription struct {
+	XMLName       xml.Name        `xml:"http://a9.com/-/spec/opensearch/1.1/ OpenSearchDescription"`
+	ShortName     string          `xml:"ShortName"`
+	Description   string          `xml:"Description"`
+	InputEncoding string          `xml:"InputEncoding"`
+	Image         openSearchImage `xml:"Image"`
+	URLs          []openSearchURL `xml:"Url"`
+}
+
+type openSearchImage struct {
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+	Type   string `xml:"type,attr"`
+	URL    string `xml:",chardata"`
+}
+
+type openSearchURL struct {
+	Type     string `xml:"type,attr"`
+	Rel      string `xml:"rel,attr,omitempty"`
+	Method   string `xml:"method,attr"`
+	Template string `xml:"template,attr"`
+}
+
+// serveOpenSearchDescription serves the OpenSearch description document of
+// the site. Its URLs are absolute, and refer to the host of the request.
+func (s *Server) serveOpenSearchDescription(w http.ResponseWriter, r *http.Request) {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	base := fmt.Sprintf("%s://%s", scheme, r.Host)
+	desc := &openSearchDescription{
+		ShortName:     "pkg.go.dev",
+		Description:   "Search for Go packages",
+		InputEncoding: "UTF-8",
+		Image: openSearchImage{
+			Width:  16,
+			Height: 16,
+			Type:   "image/x-icon",
+			URL:    base + "/favicon.ico",
+		},
+		URLs: []openSearchURL{
+			{Type: "text/html", Method: "get", Template: base + "/search?q={searchTerms}"},
+			{Type: "application/x-suggestions+json", Method: "get", Template: base + "/autocomplete?q={searchTerms}&format=" + openSearchFormat},
+			{Type: "application/opensearchdescription+xml", Rel: "self", Method: "get", Template: base + "/opensearch.xml"},
+		},
+	}
+	out, err := xml.MarshalIndent(desc, "", "  ")
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/opensearchdescription+xml; charset=utf-8")
+	w.Write([]byte(xml.Header))
+	w.Write(out)
+}
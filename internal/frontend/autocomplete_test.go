@@ -0,0 +1,137 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestServeAutocomplete(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	defer postgres.ResetTestDB(testDB, t)
+	if err := testDB.InsertModule(ctx, sample.DefaultModule()); err != nil {
+		t.Fatal(err)
+	}
+	_, handler, _ := newTestServer(t, nil)
+
+	get := func(t *testing.T, url string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("got status %d, want %d", w.Code, http.StatusOK)
+		}
+		return w
+	}
+
+	for _, test := range []struct {
+		name, query string
+		want        []*AutocompleteResult
+	}{
+		{
+			name:  "path prefix",
+			query: "github.com/valid/",
+			want: []*AutocompleteResult{{
+				PackagePath: sample.PackagePath,
+				Name:        sample.PackageName,
+				Synopsis:    sample.Synopsis,
+			}},
+		},
+		{
+			name:  "name prefix",
+			query: sample.PackageName[:3],
+			want: []*AutocompleteResult{{
+				PackagePath: sample.PackagePath,
+				Name:        sample.PackageName,
+				Synopsis:    sample.Synopsis,
+			}},
+		},
+		{name: "no match", query: "nothing", want: []*AutocompleteResult{}},
+		{name: "short prefix", query: sample.PackageName[:2], want: []*AutocompleteResult{}},
+		{name: "empty", query: "", want: []*AutocompleteResult{}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			w := get(t, "/autocomplete?q="+test.query)
+			var got []*AutocompleteResult
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("opensearch", func(t *testing.T) {
+		w := get(t, "/autocomplete?format=opensearch&q=github.com/valid/")
+		if got, want := w.Header().Get("Content-Type"), "application/x-suggestions+json; charset=utf-8"; got != want {
+			t.Errorf("got Content-Type %q, want %q", got, want)
+		}
+		var got []interface{}
+		if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		want := []interface{}{
+			"github.com/valid/",
+			[]interface{}{sample.PackagePath},
+			[]interface{}{sample.Synopsis},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestAutocompletePrefixOK(t *testing.T) {
+	for _, test := range []struct {
+		query string
+		want  bool
+	}{
+		{"", false},
+		{"f", false},
+		{"fo", false},
+		{"foo", true},
+		{"a/", true},
+		{"日本", false},
+		{"日本語", true},
+	} {
+		if got := autocompletePrefixOK(test.query); got != test.want {
+			t.Errorf("autocompletePrefixOK(%q) = %t, want %t", test.query, got, test.want)
+		}
+	}
+}
+
+func TestServeOpenSearchDescription(t *testing.T) {
+	s := &Server{}
+	r := httptest.NewRequest("GET", "/opensearch.xml", nil)
+	r.Host = "pkg.example.com"
+	r.Header.Set("X-Forwarded-Proto", "https")
+	w := httptest.NewRecorder()
+	s.serveOpenSearchDescription(w, r)
+	if got, want := w.Header().Get("Content-Type"), "application/opensearchdescription+xml; charset=utf-8"; got != want {
+		t.Errorf("got Content-Type %q, want %q", got, want)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		`<OpenSearchDescription xmlns="http://a9.com/-/spec/opensearch/1.1/">`,
+		`template="https://pkg.example.com/search?q={searchTerms}"`,
+		`template="https://pkg.example.com/autocomplete?q={searchTerms}&amp;format=opensearch"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("description does not contain %q:\n%s", want, body)
+		}
+	}
+}
@@ -5,9 +5,15 @@
 package frontend
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"html"
 	"net/http"
 	"strings"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
 )
 
 type badgePage struct {
@@ -16,15 +22,62 @@ type badgePage struct {
 	LinkPath string
 	// BadgePath is the URL path of the badge SVG.
 	BadgePath string
+	// Variants are the badges showing information about the latest version
+	// of LinkPath.
+	Variants []*badgeVariant
+}
+
+// A badgeVariant is a badge showing one field of a path.
+type badgeVariant struct {
+	// Name describes the badge, for the generation tool and the alt text of
+	// the image.
+	Name string
+	// BadgePath is the URL path of the badge SVG.
+	BadgePath string
+}
+
+// The values of the field query parameter of badge requests, selecting what a
+// badge shows. The empty field selects the Go Reference badge.
+const (
+	badgeFieldReference  = ""
+	badgeFieldVersion    = "version"
+	badgeFieldLicense    = "license"
+	badgeFieldImportedBy = "importedby"
+	badgeFieldCoverage   = "coverage"
+)
+
+// badgeFields lists the fields that badges can show, other than the Go
+// Reference badge, in the order in which the badge generation tool shows
+// them, along with their names.
+var badgeFields = []struct {
+	field, name string
+}{
+	{badgeFieldVersion, "Go Version"},
+	{badgeFieldLicense, "Go License"},
+	{badgeFieldImportedBy, "Go Imported By"},
+	{badgeFieldCoverage, "Go Doc Coverage"},
 }
 
-// badgeHandler serves a Go SVG badge image for requests to /badge/<path>
+// badgeHandler serves an SVG badge image for requests to /badge/<path>
 // and a badge generation tool page for requests to /badge/[?path=<path>].
-func (s *Server) badgeHandler(w http.ResponseWriter, r *http.Request) {
+//
+// The field query parameter of badge image requests selects what the badge
+// shows about the latest version of the unit at path; see the badgeField
+// constants. Without it, the Go Reference badge is served.
+func (s *Server) badgeHandler(w http.ResponseWriter, r *http.Request, ds internal.DataSource) error {
 	path := strings.TrimPrefix(r.URL.Path, "/badge/")
 	if path != "" {
-		http.ServeFile(w, r, fmt.Sprintf("%s/img/badge.svg", s.staticPath))
-		return
+		field := r.FormValue("field")
+		if field == badgeFieldReference {
+			http.ServeFile(w, r, fmt.Sprintf("%s/img/badge.svg", s.staticPath))
+			return nil
+		}
+		b, err := badgeContent(r.Context(), ds, strings.TrimSuffix(path, ".svg"), field)
+		if err != nil {
+			return err
+		}
+		serveBadge(w, b)
+		return nil
 	}
 
 	// The user may input a fully qualified URL (https://pkg.go.dev/net/http
@@ -40,5 +93,155 @@ func (s *Server) badgeHandler(w http.ResponseWriter, r *http.Request) {
 		LinkPath:  path,
 		BadgePath: "badge/" + path + ".svg",
 	}
+	if path != "" {
+		for _, f := range badgeFields {
+			page.Variants = append(page.Variants, &badgeVariant{
+				Name:      f.name,
+				BadgePath: page.BadgePath + "?field=" + f.field,
+			})
+		}
+	}
 	s.servePage(r.Context(), w, "badge.tmpl", page)
+	return nil
+}
+
+// A badge is the content of an SVG badge: a label on a grey background, and
+// a message on a background of the given color.
+type badge struct {
+	label, message, color string
+}
+
+// Badge colors.
+const (
+	badgeGrey   = "#5C5C5C"
+	badgeBlue   = "#007D9C"
+	badgeGreen  = "#3C8527"
+	badgeYellow = "#A0780B"
+	badgeRed    = "#C5221F"
+)
+
+// badgeLabels maps badge fields to the labels of their badges.
+var badgeLabels = map[string]string{
+	badgeFieldVersion:    "version",
+	badgeFieldLicense:    "license",
+	badgeFieldImportedBy: "imported by",
+	badgeFieldCoverage:   "doc coverage",
+}
+
+// badgeContent returns the badge showing field for the latest version of the
+// unit at path.
+func badgeContent(ctx context.Context, ds internal.DataSource, path, field string) (_ *badge, err error) {
+	defer derrors.Wrap(&err, "badgeContent(%q, %q)", path, field)
+
+	label, ok := badgeLabels[field]
+	if !ok {
+		return nil, &serverError{
+			status:       http.StatusBadRequest,
+			responseText: fmt.Sprintf("unknown badge field %q", field),
+		}
+	}
+	b := &badge{label: label, message: "unknown", color: badgeGrey}
+	um, err := badgeUnitMeta(ctx, ds, path)
+	if errors.Is(err, derrors.NotFound) {
+		b.message = "not found"
+		return b, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !um.IsRedistributable && (field == badgeFieldLicense || field == badgeFieldCoverage) {
+		b.message = "not redistributable"
+		return b, nil
+	}
+	switch field {
+	case badgeFieldVersion:
+		latest, err := ds.GetLatestInfo(ctx, um.Path, um.ModulePath)
+		if err != nil {
+			return nil, err
+		}
+		b.message, b.color = um.Version, badgeBlue
+		if latest.MinorVersion != "" {
+			b.message = latest.MinorVersion
+		}
+	case badgeFieldLicense:
+		if types := licenseTypes(um); len(types) > 0 {
+			b.message, b.color = strings.Join(types, ", "), badgeBlue
+		}
+	case badgeFieldImportedBy:
+		if !um.IsPackage() {
+			b.message = "not a package"
+			return b, nil
+		}
+		count, err := getImportedByCount(ctx, ds, &internal.Unit{UnitMeta: *um})
+		if err != nil {
+			return nil, err
+		}
+		if count != "N/A" {
+			b.message, b.color = count, badgeBlue
+		}
+	case badgeFieldCoverage:
+		if !um.IsPackage() {
+			b.message = "not a package"
+			return b, nil
+		}
+		c, err := unitDocCoverage(ctx, ds, um)
+		if err != nil {
+			return nil, err
+		}
+		if c != nil {
+			b.message, b.color = fmt.Sprintf("%d%%", c.Percent()), coverageColor(c.Percent())
+		}
+	}
+	return b, nil
+}
+
+// badgeUnitMeta returns the UnitMeta for the latest version of the unit at
+// path. Excluded paths are reported as not found.
+func badgeUnitMeta(ctx context.Context, ds internal.DataSource, path string) (*internal.UnitMeta, error) {
+	if err := checkExcluded(ctx, ds, path); err != nil {
+		var serr *serverError
+		if errors.As(err, &serr) && serr.status == http.StatusNotFound {
+			return nil, derrors.NotFound
+		}
+		return nil, err
+	}
+	return ds.GetUnitMeta(ctx, path, internal.UnknownModulePath, internal.LatestVersion)
+}
+
+// licenseTypes returns the distinct license types detected for um, in the
+// order of its license files.
+func licenseTypes(um *internal.UnitMeta) []string {
+	var types []string
+	seen := map[string]bool{}
+	for _, l := range um.Licenses {
+		for _, t := range l.Types {
+			if !seen[t] {
+				seen[t] = true
+				types = append(types, t)
+			}
+		}
+	}
+	return types
+}
+
+// serveBadge writes the SVG for b to w.
+func serveBadge(w http.ResponseWriter, b *badge) {
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Write(badgeSVG(b.label, b.message, b.color))
+}
+
+// badgeSVG returns the SVG for a badge. The width of the text is estimated
+// from its length, which is good enough for the short strings on badges.
+func badgeSVG(label, message, color string) []byte {
+	const charWidth, padding = 7, 8
+	lw := len(label)*charWidth + 2*padding
+	mw := len(message)*charWidth + 2*padding
+	label, message = html.EscapeString(label), html.EscapeString(message)
+	return []byte(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]s: %[5]s">`+
+		`<title>%[4]s: %[5]s</title>`+
+		`<rect width="%[2]d" height="20" rx="2" fill="%[7]s"/>`+
+		`<rect x="%[2]d" width="%[3]d" height="20" rx="2" fill="%[6]s"/>`+
+		`<g fill="#FAFAFA" text-anchor="middle" font-family="Verdana,DejaVu Sans,sans-serif" font-size="11">`+
+		`<text x="%[8]d" y="14">%[4]s</text><text x="%[9]d" y="14">%[5]s</text></g></svg>`,
+		lw+mw, lw, mw, label, message, color, badgeGrey, lw/2, lw+mw/2))
 }
@@ -5,9 +5,14 @@
 package frontend
 
 import (
+	"context"
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/testing/sample"
 )
 
 func TestBadgeHandler_ServeSVG(t *testing.T) {
@@ -19,6 +24,73 @@ func TestBadgeHandler_ServeSVG(t *testing.T) {
 	}
 }
 
+func TestBadgeContent(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer postgres.ResetTestDB(testDB, t)
+
+	m := sample.Module(sample.ModulePath, "v1.2.0", sample.Suffix)
+	if err := testDB.InsertModule(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		path, field string
+		want        *badge
+	}{
+		{sample.PackagePath, badgeFieldVersion, &badge{"version", "v1.2.0", badgeBlue}},
+		{sample.PackagePath, badgeFieldLicense, &badge{"license", sample.LicenseType, badgeBlue}},
+		{sample.PackagePath, badgeFieldImportedBy, &badge{"imported by", "0", badgeBlue}},
+		{sample.PackagePath, badgeFieldCoverage, &badge{"doc coverage", "unknown", badgeGrey}},
+		{sample.ModulePath, badgeFieldImportedBy, &badge{"imported by", "not a package", badgeGrey}},
+		{"example.com/missing", badgeFieldVersion, &badge{"version", "not found", badgeGrey}},
+	} {
+		t.Run(test.path+"/"+test.field, func(t *testing.T) {
+			got, err := badgeContent(ctx, testDB, test.path, test.field)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got, cmp.AllowUnexported(badge{})); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := badgeContent(ctx, testDB, sample.PackagePath, "stars"); err == nil {
+		t.Error("got no error for unknown field")
+	}
+}
+
+func TestBadgeSVG(t *testing.T) {
+	got := string(badgeSVG("doc coverage", "<80%>", badgeGreen))
+	for _, want := range []string{
+		`width="151"`,
+		`aria-label="doc coverage: &lt;80%&gt;"`,
+		`fill="` + badgeGreen + `"`,
+		`<text x="50" y="14">doc coverage</text>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("badge does not contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestCoverageColor(t *testing.T) {
+	for _, test := range []struct {
+		percent int
+		want    string
+	}{
+		{100, badgeGreen},
+		{80, badgeGreen},
+		{79, badgeYellow},
+		{50, badgeYellow},
+		{0, badgeRed},
+	} {
+		if got := coverageColor(test.percent); got != test.want {
+			t.Errorf("coverageColor(%d) = %q, want %q", test.percent, got, test.want)
+		}
+	}
+}
+
 func TestBadgeHandler_ServeBadgeTool(t *testing.T) {
 	_, handler, _ := newTestServer(t, nil)
 
@@ -50,6 +122,10 @@ func TestBadgeHandler_ServeBadgeTool(t *testing.T) {
 			"/badge/?path=https://github.com/google/uuid",
 			"[![Go Reference](https://pkg.go.dev/badge/github.com/google/uuid.svg)](https://pkg.go.dev/github.com/google/uuid)",
 		},
+		{
+			"/badge/?path=github.com/google/uuid",
+			"[![Go License](https://pkg.go.dev/badge/github.com/google/uuid.svg?field=license)](https://pkg.go.dev/github.com/google/uuid)",
+		},
 	}
 
 	for _, test := range tests {
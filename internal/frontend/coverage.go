@@ -0,0 +1,78 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/postgres"
+)
+
+// CoverageDetails contains the documentation coverage of a package, for the
+// coverage tab.
+type CoverageDetails struct {
+	// Coverage is nil if the coverage of the package is not known, because
+	// it was processed before coverage was recorded.
+	Coverage *internal.DocCoverage
+	// DocURLPath is the URL path of the documentation of the package, to
+	// link to its identifiers.
+	DocURLPath string
+	// BadgePath is the URL path of the coverage badge of the package.
+	BadgePath string
+}
+
+// fetchCoverageDetails returns the documentation coverage of the package
+// described by um.
+func fetchCoverageDetails(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta) (_ *CoverageDetails, err error) {
+	defer derrors.Wrap(&err, "fetchCoverageDetails(%q, %q, %q)", um.Path, um.ModulePath, um.Version)
+
+	c, err := unitDocCoverage(ctx, ds, um)
+	if err != nil {
+		return nil, err
+	}
+	return &CoverageDetails{
+		Coverage:   c,
+		DocURLPath: constructUnitURL(um.Path, um.ModulePath, linkVersion(um.Version, um.ModulePath)),
+		BadgePath:  "/badge/" + um.Path + ".svg?field=coverage",
+	}, nil
+}
+
+// unitDocCoverage returns the documentation coverage of the package described
+// by um, or nil if it is not known.
+//
+// The database stores the coverage of each package when it is inserted. Other
+// data sources hold it in the documentation of the unit.
+func unitDocCoverage(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta) (*internal.DocCoverage, error) {
+	if db, ok := ds.(*postgres.DB); ok {
+		c, err := db.GetDocCoverage(ctx, um.Path, um.ModulePath, um.Version)
+		if errors.Is(err, derrors.NotFound) {
+			return nil, nil
+		}
+		return c, err
+	}
+	u, err := ds.GetUnit(ctx, um, internal.WithMain, internal.BuildContextAll)
+	if err != nil {
+		return nil, err
+	}
+	if d := internal.DocumentationForBuildContext(u.Documentation, internal.BuildContextAll); d != nil {
+		return d.Coverage, nil
+	}
+	return nil, nil
+}
+
+// coverageColor returns the badge color for a coverage percentage.
+func coverageColor(percent int) string {
+	switch {
+	case percent >= 80:
+		return badgeGreen
+	case percent >= 50:
+		return badgeYellow
+	default:
+		return badgeRed
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestFetchCoverageDetails(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer postgres.ResetTestDB(testDB, t)
+
+	m := sample.Module(sample.ModulePath, sample.VersionString, sample.Suffix)
+	coverage := &internal.DocCoverage{
+		HasPackageDoc: true,
+		NumExported:   4,
+		NumDocumented: 3,
+		Undocumented:  []string{"F"},
+	}
+	m.Units[1].Documentation[0].Coverage = coverage
+	if err := testDB.InsertModule(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+	um := sample.UnitMeta(sample.PackagePath, sample.ModulePath, sample.VersionString, sample.PackageName, true)
+	got, err := fetchCoverageDetails(ctx, testDB, um)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &CoverageDetails{
+		Coverage:   coverage,
+		DocURLPath: "/" + sample.PackagePath + "@" + sample.VersionString,
+		BadgePath:  "/badge/" + sample.PackagePath + ".svg?field=coverage",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
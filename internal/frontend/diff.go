@@ -0,0 +1,150 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"golang.org/x/mod/semver"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/godoc"
+	"golang.org/x/pkgsite/internal/godoc/apidiff"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/stdlib"
+)
+
+// DiffDetails contains the data needed to render the API diff tab, which
+// compares the exported API of a package at the displayed version with the
+// API at an earlier version.
+type DiffDetails struct {
+	// FromVersion and ToVersion are the versions being compared, in display
+	// form. FromVersion is empty if there is no version to compare with.
+	FromVersion, ToVersion string
+
+	// FromURL is the URL of the package at FromVersion.
+	FromURL string
+
+	// Message explains why no comparison is shown, if there is none.
+	Message string
+
+	// Incompatible and Compatible are the changes in the API, split by
+	// whether they may break users of the package.
+	Incompatible, Compatible []*apidiff.Change
+
+	// Deprecated is the names of the identifiers that were deprecated since
+	// FromVersion.
+	Deprecated []string
+
+	// IsV0 reports whether the versions are in major version 0, which makes
+	// no compatibility promises.
+	IsV0 bool
+
+	// BreaksCompatibility reports whether there are incompatible changes
+	// between two versions of the same major version v1 or later.
+	BreaksCompatibility bool
+}
+
+// fetchDiffDetails compares the API of the package described by um with the
+// API at fromVersion. If fromVersion is empty, the previous version of the
+// package's module is used, if the data source can list versions.
+func fetchDiffDetails(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta, fromVersion string, bc internal.BuildContext) (_ *DiffDetails, err error) {
+	defer derrors.Wrap(&err, "fetchDiffDetails(ctx, ds, %q, %q, %q)", um.Path, um.Version, fromVersion)
+
+	details := &DiffDetails{ToVersion: displayVersion(um.Version, um.ModulePath)}
+	if fromVersion == "" {
+		fromVersion, err = previousVersion(ctx, ds, um)
+		if err != nil {
+			return nil, err
+		}
+		if fromVersion == "" {
+			details.Message = "There is no earlier version of this package to compare with."
+			return details, nil
+		}
+	} else if um.ModulePath == stdlib.ModulePath && !semver.IsValid(fromVersion) {
+		fromVersion = stdlib.VersionForTag(fromVersion)
+	}
+	if !semver.IsValid(fromVersion) {
+		details.Message = fmt.Sprintf("%q is not a valid version.", fromVersion)
+		return details, nil
+	}
+	details.FromVersion = displayVersion(fromVersion, um.ModulePath)
+	details.FromURL = constructUnitURL(um.Path, um.ModulePath, fromVersion)
+
+	fromUM, err := ds.GetUnitMeta(ctx, um.Path, um.ModulePath, fromVersion)
+	if err != nil {
+		if !errors.Is(err, derrors.NotFound) {
+			return nil, err
+		}
+		details.Message = fmt.Sprintf("%s is not available at %s.", um.Path, details.FromVersion)
+		return details, nil
+	}
+	// Without the documentation of both versions, every identifier would
+	// appear to be added or removed, so show no comparison at all.
+	if !fromUM.IsRedistributable || !um.IsRedistributable {
+		details.Message = "The API diff is not available due to license restrictions."
+		return details, nil
+	}
+	fromPkg, err := decodedPackage(ctx, ds, fromUM, bc)
+	if err != nil {
+		return nil, err
+	}
+	if fromPkg == nil {
+		details.Message = fmt.Sprintf("The API diff is not available because there is no documentation for %s at %s.", um.Path, details.FromVersion)
+		return details, nil
+	}
+	toPkg, err := decodedPackage(ctx, ds, um, bc)
+	if err != nil {
+		return nil, err
+	}
+	if toPkg == nil {
+		details.Message = fmt.Sprintf("The API diff is not available because there is no documentation for %s at %s.", um.Path, details.ToVersion)
+		return details, nil
+	}
+	report := apidiff.Diff(fromPkg, toPkg)
+	details.Incompatible = report.Incompatible()
+	details.Compatible = report.Compatible()
+	details.Deprecated = report.Deprecated
+	major := semver.Major(um.Version)
+	details.IsV0 = major == "v0"
+	details.BreaksCompatibility = !details.IsV0 && major == semver.Major(fromVersion) && len(details.Incompatible) > 0
+	return details, nil
+}
+
+// decodedPackage returns the decoded documentation source of the package
+// described by um for bc, or nil if there is none.
+func decodedPackage(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta, bc internal.BuildContext) (*godoc.Package, error) {
+	u, err := ds.GetUnit(ctx, um, internal.WithMain, bc)
+	if err != nil {
+		return nil, err
+	}
+	if len(u.Documentation) == 0 || len(u.Documentation[0].Source) == 0 {
+		return nil, nil
+	}
+	return godoc.DecodePackage(u.Documentation[0].Source)
+}
+
+// previousVersion returns the version of um's module that precedes um.Version
+// among the versions of um.Path, or "" if there is none or ds cannot list
+// versions.
+func previousVersion(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta) (string, error) {
+	db, ok := ds.(*postgres.DB)
+	if !ok {
+		return "", nil
+	}
+	versions, err := db.GetVersionsForPath(ctx, um.Path)
+	if err != nil {
+		return "", err
+	}
+	// versions are sorted in descending order.
+	for _, mi := range versions {
+		if mi.ModulePath == um.ModulePath && semver.Compare(mi.Version, um.Version) < 0 {
+			return mi.Version, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/godoc"
+	"golang.org/x/pkgsite/internal/godoc/apidiff"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestFetchDiffDetails(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer postgres.ResetTestDB(testDB, t)
+
+	for _, v := range []struct {
+		version, src string
+	}{
+		{"v1.0.0", "package foo\nfunc F(int) {}\nfunc G() {}\n"},
+		{"v1.1.0", "package foo\n// Deprecated: use H.\nfunc F(string) {}\nfunc H() {}\n"},
+	} {
+		m := sample.Module(sample.ModulePath, v.version, sample.Suffix)
+		m.Units[1].Documentation[0].Source = encodeSource(t, v.src)
+		if err := testDB.InsertModule(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// At v1.2.0 the package is not redistributable, and at v1.3.0 it has no
+	// documentation.
+	nonRedist := sample.Module(sample.ModulePath, "v1.2.0", sample.Suffix)
+	nonRedist.Units[1].IsRedistributable = false
+	noDoc := sample.Module(sample.ModulePath, "v1.3.0", sample.Suffix)
+	noDoc.Units[1].Documentation = nil
+	for _, m := range []*internal.Module{nonRedist, noDoc} {
+		if err := testDB.InsertModule(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	um := sample.UnitMeta(sample.PackagePath, sample.ModulePath, "v1.1.0", sample.PackageName, true)
+
+	for _, test := range []struct {
+		name, from string
+		um         *internal.UnitMeta // defaults to um
+		want       *DiffDetails
+	}{
+		{
+			name: "previous version",
+			from: "",
+			want: &DiffDetails{
+				FromVersion: "v1.0.0",
+				ToVersion:   "v1.1.0",
+				FromURL:     "/" + sample.PackagePath + "@v1.0.0",
+				Incompatible: []*apidiff.Change{
+					{Name: "F", Kind: apidiff.Changed, Old: "func F(int)", New: "func F(string)"},
+					{Name: "G", Kind: apidiff.Removed, Old: "func G()"},
+				},
+				Compatible: []*apidiff.Change{
+					{Name: "H", Kind: apidiff.Added, New: "func H()"},
+				},
+				Deprecated:          []string{"F"},
+				BreaksCompatibility: true,
+			},
+		},
+		{
+			name: "missing version",
+			from: "v0.9.0",
+			want: &DiffDetails{
+				FromVersion: "v0.9.0",
+				ToVersion:   "v1.1.0",
+				FromURL:     "/" + sample.PackagePath + "@v0.9.0",
+				Message:     sample.PackagePath + " is not available at v0.9.0.",
+			},
+		},
+		{
+			name: "invalid version",
+			from: "master",
+			want: &DiffDetails{
+				ToVersion: "v1.1.0",
+				Message:   `"master" is not a valid version.`,
+			},
+		},
+		{
+			name: "not redistributable",
+			from: "v1.1.0",
+			um:   sample.UnitMeta(sample.PackagePath, sample.ModulePath, "v1.2.0", sample.PackageName, false),
+			want: &DiffDetails{
+				FromVersion: "v1.1.0",
+				ToVersion:   "v1.2.0",
+				FromURL:     "/" + sample.PackagePath + "@v1.1.0",
+				Message:     "The API diff is not available due to license restrictions.",
+			},
+		},
+		{
+			name: "no documentation",
+			from: "v1.3.0",
+			want: &DiffDetails{
+				FromVersion: "v1.3.0",
+				ToVersion:   "v1.1.0",
+				FromURL:     "/" + sample.PackagePath + "@v1.3.0",
+				Message:     "The API diff is not available because there is no documentation for " + sample.PackagePath + " at v1.3.0.",
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			tum := test.um
+			if tum == nil {
+				tum = um
+			}
+			got, err := fetchDiffDetails(ctx, testDB, tum, test.from, internal.BuildContextAll)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// encodeSource returns the encoded documentation source of a package
+// consisting of the single file src.
+func encodeSource(t *testing.T, src string) []byte {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "foo.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := godoc.NewPackage(fset, sample.GOOS, sample.GOARCH, nil)
+	p.AddFile(f, true)
+	b, err := p.Encode(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
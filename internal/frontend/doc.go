@@ -10,17 +10,21 @@ import (
 	"path"
 	"sort"
 	"strings"
+	"time"
 
+	"golang.org/x/mod/semver"
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/godoc"
 	"golang.org/x/pkgsite/internal/godoc/dochtml"
 	"golang.org/x/pkgsite/internal/log"
 	"golang.org/x/pkgsite/internal/middleware"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/source"
 	"golang.org/x/pkgsite/internal/stdlib"
 )
 
-func renderDocParts(ctx context.Context, u *internal.Unit, docPkg *godoc.Package) (_ *dochtml.Parts, err error) {
+func renderDocParts(ctx context.Context, u *internal.Unit, docPkg *godoc.Package, sinceVersions map[string]string) (_ *dochtml.Parts, err error) {
 	defer derrors.Wrap(&err, "renderDocParts")
 	defer middleware.ElapsedStat(ctx, "renderDocParts")()
 
@@ -28,6 +32,7 @@ func renderDocParts(ctx context.Context, u *internal.Unit, docPkg *godoc.Package
 		ModulePath:      u.ModulePath,
 		ResolvedVersion: u.Version,
 		ModulePackages:  nil, // will be provided by docPkg
+		SinceVersions:   sinceVersions,
 	}
 	var innerPath string
 	if u.ModulePath == stdlib.ModulePath {
@@ -35,7 +40,146 @@ func renderDocParts(ctx context.Context, u *internal.Unit, docPkg *godoc.Package
 	} else if u.Path != u.ModulePath {
 		innerPath = u.Path[len(u.ModulePath)+1:]
 	}
-	return docPkg.RenderParts(ctx, innerPath, u.SourceInfo, modInfo)
+	return docPkg.RenderParts(ctx, innerPath, sourceInfo(&u.UnitMeta), modInfo)
+}
+
+// sourceInfo returns the source.Info used to link to the source files of um.
+// Modules whose source location is unknown link to the built-in source
+// viewer, if their source is redistributable.
+func sourceInfo(um *internal.UnitMeta) *source.Info {
+	if um.SourceInfo != nil {
+		return um.SourceInfo
+	}
+	if !um.IsRedistributable {
+		return nil
+	}
+	return source.NewViewerInfo(sourceViewerPath(um.ModulePath, um.Version))
+}
+
+// getSinceVersions returns a map from the names of the exported symbols of
+// the package u to the version of its module in which they were added, for
+// display. Symbols present in the earliest known version of the package are
+// omitted. It returns nil if ds does not record symbol history.
+func getSinceVersions(ctx context.Context, ds internal.DataSource, u *internal.Unit) (_ map[string]string, err error) {
+	defer derrors.Wrap(&err, "getSinceVersions(%q, %q)", u.Path, u.ModulePath)
+	defer middleware.ElapsedStat(ctx, "getSinceVersions")()
+
+	db, ok := ds.(*postgres.DB)
+	if !ok {
+		return nil, nil
+	}
+	history, err := db.GetSymbolHistory(ctx, u.Path, u.ModulePath)
+	if err != nil {
+		return nil, err
+	}
+	var first string
+	for _, v := range history {
+		if first == "" || semver.Compare(v, first) < 0 {
+			first = v
+		}
+	}
+	sinceVersions := map[string]string{}
+	for name, v := range history {
+		if v != first {
+			sinceVersions[name] = displayVersion(v, u.ModulePath)
+		}
+	}
+	return sinceVersions, nil
+}
+
+const (
+	// typeCheckTimeout bounds the time spent loading the imports of a
+	// package to type-check it while serving a page.
+	typeCheckTimeout = 2 * time.Second
+
+	// typeCacheSize and typeCacheTTL are the number of imported packages
+	// kept across requests, and for how long.
+	typeCacheSize = 2000
+	typeCacheTTL  = time.Hour
+)
+
+// typeCheck type-checks docPkg, the documentation source of u for the build
+// context bc, loading the packages it imports from ds or from cache, so that
+// identifiers in declarations are linked exactly. Failures are only logged:
+// rendering links the identifiers that could not be resolved heuristically.
+func typeCheck(ctx context.Context, ds internal.DataSource, u *internal.Unit, docPkg *godoc.Package, bc internal.BuildContext, cache *godoc.TypeCache) {
+	defer middleware.ElapsedStat(ctx, "typeCheck")()
+
+	ctx, cancel := context.WithTimeout(ctx, typeCheckTimeout)
+	defer cancel()
+	getSource := func(ctx context.Context, importPath string) ([]byte, error) {
+		return importedSource(ctx, ds, u, importPath, bc)
+	}
+	cacheKey := func(importPath string) string {
+		modulePath, version := importedVersion(u, importPath)
+		return fmt.Sprintf("%s %s %s %s", importPath, modulePath, version, bc)
+	}
+	if err := docPkg.TypeCheck(ctx, u.Path, getSource, cache, cacheKey); err != nil {
+		log.Debugf(ctx, "typeCheck(%q, %q, %q): %v", u.Path, u.ModulePath, u.Version, err)
+	}
+}
+
+// importedVersion returns the module path and version at which the package
+// imported by u with the given import path is loaded: packages in the module
+// of u are loaded at the version of u, and others at their latest version.
+func importedVersion(u *internal.Unit, importPath string) (modulePath, version string) {
+	switch {
+	case stdlib.Contains(importPath):
+		if u.ModulePath == stdlib.ModulePath {
+			return stdlib.ModulePath, u.Version
+		}
+		return stdlib.ModulePath, internal.LatestVersion
+	case importPath == u.ModulePath || strings.HasPrefix(importPath, u.ModulePath+"/"):
+		return u.ModulePath, u.Version
+	default:
+		return internal.UnknownModulePath, internal.LatestVersion
+	}
+}
+
+// importedSource returns the encoded documentation source of the package
+// imported by u with the given import path, at the version given by
+// importedVersion, preferring the documentation for bc.
+func importedSource(ctx context.Context, ds internal.DataSource, u *internal.Unit, importPath string, bc internal.BuildContext) (_ []byte, err error) {
+	defer derrors.Wrap(&err, "importedSource(%q, %q)", u.Path, importPath)
+
+	modulePath, version := importedVersion(u, importPath)
+	um, err := ds.GetUnitMeta(ctx, importPath, modulePath, version)
+	if err != nil {
+		return nil, err
+	}
+	if !um.IsPackage() {
+		return nil, fmt.Errorf("not a package: %w", derrors.NotFound)
+	}
+	docs, err := documentationSource(ctx, ds, um, bc)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		// The package has no documentation for bc; use its first build
+		// context instead.
+		docs, err = documentationSource(ctx, ds, um, internal.BuildContextAll)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(docs) == 0 || len(docs[0].Source) == 0 {
+		return nil, fmt.Errorf("no documentation source: %w", derrors.NotFound)
+	}
+	return docs[0].Source, nil
+}
+
+// documentationSource returns the documentation of the unit described by um
+// for bc, including its source. It reads only the documentation if ds is a
+// database.
+func documentationSource(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta, bc internal.BuildContext) ([]*internal.Documentation, error) {
+	if db, ok := ds.(*postgres.DB); ok {
+		return db.GetDocumentation(ctx, um.Path, um.ModulePath, um.Version, bc, false)
+	}
+	u, err := ds.GetUnit(ctx, um, internal.WithMain, bc)
+	if err != nil {
+		return nil, err
+	}
+	return u.Documentation, nil
 }
 
 // sourceFiles returns the .go files for a package.
@@ -47,7 +191,7 @@ func sourceFiles(u *internal.Unit, docPkg *godoc.Package) []*File {
 		}
 		files = append(files, &File{
 			Name: f.Name,
-			URL:  u.SourceInfo.FileURL(path.Join(internal.Suffix(u.Path, u.ModulePath), f.Name)),
+			URL:  sourceInfo(&u.UnitMeta).FileURL(path.Join(internal.Suffix(u.Path, u.ModulePath), f.Name)),
 		})
 	}
 	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
@@ -0,0 +1,110 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// GoModDetails contains the directives of the go.mod file of a module, for
+// the go.mod tab.
+type GoModDetails struct {
+	// ModulePath is the path of the module.
+	ModulePath string
+	// HasGoMod reports whether the go.mod file of the module is known. It is
+	// false if the module has no go.mod file, or if the module was processed
+	// before go.mod files were recorded.
+	HasGoMod  bool
+	GoVersion string
+	Requires  []*GoModRequirement
+	Replaces  []*GoModReplacement
+	Excludes  []*GoModModuleVersion
+	// Retractions are the versions of the module that its author
+	// retracted. They only apply if this is the highest version.
+	Retractions []*internal.GoModRetraction
+
+	// Deprecated reports whether the module directive has a "Deprecated:"
+	// comment, whose text is DeprecationComment.
+	Deprecated         bool
+	DeprecationComment string
+}
+
+// GoModModuleVersion is a module version mentioned in a go.mod file.
+type GoModModuleVersion struct {
+	ModulePath string
+	// Version is empty for a replace directive that applies to all versions
+	// of a module, and for a replacement by a directory.
+	Version string
+	// URL is the URL path of the module version on the site, or empty for a
+	// replacement by a directory.
+	URL string
+}
+
+// GoModRequirement is a require directive of a go.mod file.
+type GoModRequirement struct {
+	GoModModuleVersion
+	Indirect bool
+}
+
+// GoModReplacement is a replace directive of a go.mod file.
+type GoModReplacement struct {
+	Old, New GoModModuleVersion
+}
+
+// fetchGoModDetails returns the directives of the go.mod file of the module
+// described by um.
+func fetchGoModDetails(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta) (_ *GoModDetails, err error) {
+	defer derrors.Wrap(&err, "fetchGoModDetails(%q, %q)", um.ModulePath, um.Version)
+
+	details := &GoModDetails{ModulePath: um.ModulePath}
+	gm, err := ds.GetGoMod(ctx, um.ModulePath, um.Version)
+	if errors.Is(err, derrors.NotFound) {
+		return details, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	details.HasGoMod = true
+	details.GoVersion = gm.GoVersion
+	details.Deprecated = gm.Deprecated
+	details.DeprecationComment = gm.DeprecationComment
+	for _, r := range gm.Requires {
+		details.Requires = append(details.Requires, &GoModRequirement{
+			GoModModuleVersion: goModModuleVersion(r.ModulePath, r.Version),
+			Indirect:           r.Indirect,
+		})
+	}
+	for _, r := range gm.Replaces {
+		details.Replaces = append(details.Replaces, &GoModReplacement{
+			Old: goModModuleVersion(r.OldPath, r.OldVersion),
+			New: goModModuleVersion(r.NewPath, r.NewVersion),
+		})
+	}
+	for _, e := range gm.Excludes {
+		mv := goModModuleVersion(e.ModulePath, e.Version)
+		details.Excludes = append(details.Excludes, &mv)
+	}
+	details.Retractions = gm.Retractions
+	return details, nil
+}
+
+// goModModuleVersion returns the GoModModuleVersion for a module path and
+// version of a go.mod directive. A path without a version links to the
+// latest version of the module, unless it is a directory, which has no page.
+func goModModuleVersion(modulePath, version string) GoModModuleVersion {
+	mv := GoModModuleVersion{ModulePath: modulePath, Version: version}
+	switch {
+	case version != "":
+		mv.URL = constructUnitURL(modulePath, modulePath, version)
+	case !modfile.IsDirectoryPath(modulePath):
+		mv.URL = constructUnitURL(modulePath, modulePath, internal.LatestVersion)
+	}
+	return mv
+}
@@ -0,0 +1,77 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestFetchGoModDetails(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer postgres.ResetTestDB(testDB, t)
+
+	m := sample.DefaultModule()
+	m.GoMod = &internal.GoMod{
+		GoVersion: "1.16",
+		Requires: []*internal.GoModRequire{
+			{ModulePath: "example.com/a", Version: "v1.2.3"},
+			{ModulePath: "example.com/b/v2", Version: "v2.0.0", Indirect: true},
+		},
+		Replaces: []*internal.GoModReplace{
+			{OldPath: "example.com/a", NewPath: "../a"},
+			{OldPath: "example.com/b/v2", OldVersion: "v2.0.0", NewPath: "example.com/c", NewVersion: "v0.2.0"},
+		},
+		Excludes: []*internal.GoModExclude{
+			{ModulePath: "example.com/a", Version: "v1.2.2"},
+		},
+		Retractions: []*internal.GoModRetraction{
+			{Low: "v0.1.0", High: "v0.2.0", Rationale: "Broken."},
+		},
+	}
+	if err := testDB.InsertModule(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+	um := sample.UnitMeta(sample.PackagePath, sample.ModulePath, sample.VersionString, sample.PackageName, true)
+	got, err := fetchGoModDetails(ctx, testDB, um)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &GoModDetails{
+		ModulePath: sample.ModulePath,
+		HasGoMod:   true,
+		GoVersion:  "1.16",
+		Requires: []*GoModRequirement{
+			{GoModModuleVersion: GoModModuleVersion{ModulePath: "example.com/a", Version: "v1.2.3", URL: "/example.com/a@v1.2.3"}},
+			{
+				GoModModuleVersion: GoModModuleVersion{ModulePath: "example.com/b/v2", Version: "v2.0.0", URL: "/example.com/b/v2@v2.0.0"},
+				Indirect:           true,
+			},
+		},
+		Replaces: []*GoModReplacement{
+			{
+				Old: GoModModuleVersion{ModulePath: "example.com/a", URL: "/example.com/a"},
+				New: GoModModuleVersion{ModulePath: "../a"},
+			},
+			{
+				Old: GoModModuleVersion{ModulePath: "example.com/b/v2", Version: "v2.0.0", URL: "/example.com/b/v2@v2.0.0"},
+				New: GoModModuleVersion{ModulePath: "example.com/c", Version: "v0.2.0", URL: "/example.com/c@v0.2.0"},
+			},
+		},
+		Excludes: []*GoModModuleVersion{
+			{ModulePath: "example.com/a", Version: "v1.2.2", URL: "/example.com/a@v1.2.2"},
+		},
+		Retractions: m.GoMod.Retractions,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
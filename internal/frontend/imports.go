@@ -37,7 +37,7 @@ func fetchImportsDetails(ctx context.Context, ds internal.DataSource, pkgPath, m
 		Path:       pkgPath,
 		ModulePath: modulePath,
 		Version:    resolvedVersion,
-	}, internal.WithImports)
+	}, internal.WithImports, internal.BuildContextAll)
 	if err != nil {
 		return nil, err
 	}
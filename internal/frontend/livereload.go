@@ -0,0 +1,77 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// LiveReloadPath is the path at which a LiveReloader serves its events.
+const LiveReloadPath = "/_live-reload"
+
+// A LiveReloader tells the pages open in browsers, using server-sent events,
+// that a module they may show has changed, so that they reload. It is used
+// when serving local modules.
+//
+// A LiveReloader streams its responses, so it must be served outside of
+// middleware that buffers them.
+type LiveReloader struct {
+	mu      sync.Mutex
+	clients map[chan string]bool
+}
+
+// NewLiveReloader returns a LiveReloader with no clients.
+func NewLiveReloader() *LiveReloader {
+	return &LiveReloader{clients: map[chan string]bool{}}
+}
+
+// Reload tells the connected pages that modulePath has changed.
+func (l *LiveReloader) Reload(modulePath string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for c := range l.clients {
+		select {
+		case c <- modulePath:
+		default:
+			// The client is behind; it will see the next reload.
+		}
+	}
+}
+
+// ServeHTTP sends a "reload" event, whose data is the module path, each time
+// Reload is called, until the client disconnects.
+func (l *LiveReloader) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	c := make(chan string, 1)
+	l.mu.Lock()
+	l.clients[c] = true
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		delete(l.clients, c)
+		l.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Ask the browser to reconnect quickly if the server restarts.
+	fmt.Fprint(w, "retry: 1000\n\n")
+	flusher.Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case modulePath := <-c:
+			fmt.Fprintf(w, "event: reload\ndata: %s\n\n", modulePath)
+			flusher.Flush()
+		}
+	}
+}
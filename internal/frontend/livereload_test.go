@@ -0,0 +1,58 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLiveReloader(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	l := NewLiveReloader()
+	srv := httptest.NewServer(l)
+	defer srv.Close()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if got, want := res.Header.Get("Content-Type"), "text/event-stream"; got != want {
+		t.Errorf("Content-Type = %q, want %q", got, want)
+	}
+
+	lines := bufio.NewScanner(res.Body)
+	readEvent := func() string {
+		t.Helper()
+		var event []string
+		for lines.Scan() {
+			if lines.Text() == "" {
+				return strings.Join(event, "\n")
+			}
+			event = append(event, lines.Text())
+		}
+		t.Fatalf("reading event: %v", lines.Err())
+		return ""
+	}
+	if got, want := readEvent(), "retry: 1000"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	// The client is registered by the time the first event has been read.
+	l.Reload("example.com/mod")
+	if got, want := readEvent(), "event: reload\ndata: example.com/mod"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
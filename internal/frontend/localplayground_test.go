@@ -0,0 +1,303 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestLocalPlayground returns a LocalPlayground configured by cfg, with a
+// temporary snippet directory, and temporary caches unless cfg has some.
+func newTestLocalPlayground(t *testing.T, cfg LocalPlaygroundConfig) *LocalPlayground {
+	t.Helper()
+	if _, err := exec.LookPath("prlimit"); err != nil {
+		t.Skip("prlimit command not found")
+	}
+	cfg.SnippetDir = tempDir(t, "snippets")
+	if cfg.BuildCache == "" {
+		cfg.BuildCache = tempDir(t, "gocache")
+	}
+	if cfg.ModCache == "" {
+		cfg.ModCache = tempDir(t, "gomodcache")
+	}
+	lp, err := NewLocalPlayground(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return lp
+}
+
+// tempDir returns a temporary directory that is removed when the test ends.
+func tempDir(t *testing.T, prefix string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", prefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func TestLocalPlaygroundShare(t *testing.T) {
+	s := &Server{playground: newTestLocalPlayground(t, LocalPlaygroundConfig{})}
+	const src = "package main\n\nfunc main() {}\n"
+
+	share := func() string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		s.handlePlay(w, httptest.NewRequest("POST", "/play/", strings.NewReader(src)))
+		if w.Code != http.StatusOK {
+			t.Fatalf("share: status = %d, want %d", w.Code, http.StatusOK)
+		}
+		return w.Body.String()
+	}
+	id := share()
+	if !snippetIDRegexp.MatchString(id) {
+		t.Fatalf("got ID %q", id)
+	}
+	if id2 := share(); id2 != id {
+		t.Errorf("sharing again: got ID %q, want %q", id2, id)
+	}
+
+	for _, test := range []struct {
+		id         string
+		wantStatus int
+		wantBody   string
+	}{
+		{id, http.StatusOK, src},
+		{"0123456789abcdef", http.StatusNotFound, ""},
+		{"..%2Fsecret", http.StatusNotFound, ""},
+	} {
+		w := httptest.NewRecorder()
+		s.handlePlay(w, httptest.NewRequest("GET", "/play/p/"+test.id, nil))
+		if w.Code != test.wantStatus {
+			t.Errorf("%s: status = %d, want %d", test.id, w.Code, test.wantStatus)
+		}
+		if test.wantBody != "" && w.Body.String() != test.wantBody {
+			t.Errorf("%s: got %q, want %q", test.id, w.Body.String(), test.wantBody)
+		}
+	}
+}
+
+func TestLocalPlaygroundSnippetQuota(t *testing.T) {
+	lp := newTestLocalPlayground(t, LocalPlaygroundConfig{MaxSnippets: 2, MaxSnippetBytes: 100})
+	srcs := []string{"package a\n", "package b\n", "package c\n", strings.Repeat("/", 90) + "\n"}
+	var ids []string
+	for i, src := range srcs {
+		if i == 2 {
+			// Reading the first snippet makes the second the oldest, so it
+			// is evicted when the third is stored.
+			w := httptest.NewRecorder()
+			lp.ServeSnippet(w, httptest.NewRequest("GET", "/play/p/"+ids[0], nil), ids[0])
+			if w.Code != http.StatusOK {
+				t.Fatalf("reading %s: status = %d", ids[0], w.Code)
+			}
+		}
+		id := snippetID([]byte(src))
+		ids = append(ids, id)
+		if err := lp.storeSnippet(id, []byte(src)); err != nil {
+			t.Fatal(err)
+		}
+		// Give the snippets distinct times, in the order they were stored.
+		tm := time.Now().Add(time.Duration(i-len(srcs)) * time.Minute)
+		if err := os.Chtimes(filepath.Join(lp.cfg.SnippetDir, id+".go"), tm, tm); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The last snippet is over the size limit together with any other, so
+	// it is the only one kept.
+	for i, want := range []bool{false, false, false, true} {
+		_, err := os.Stat(filepath.Join(lp.cfg.SnippetDir, ids[i]+".go"))
+		if got := err == nil; got != want {
+			t.Errorf("snippet %d: exists = %t, want %t", i, got, want)
+		}
+	}
+}
+
+func TestLocalPlaygroundRun(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+	s := &Server{playground: newTestLocalPlayground(t, LocalPlaygroundConfig{
+		RunTimeout: 2 * time.Second,
+		MaxOutput:  10,
+	})}
+
+	for _, test := range []struct {
+		name, src  string
+		wantOutput string
+		wantErrors string
+		wantStatus int
+	}{
+		{
+			name:       "hello",
+			src:        "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Print(\"hello\") }\n",
+			wantOutput: "hello",
+		},
+		{
+			name:       "exit status",
+			src:        "package main\n\nimport \"os\"\n\nfunc main() { os.Exit(3) }\n",
+			wantStatus: 3,
+		},
+		{
+			name:       "build error",
+			src:        "package main\n\nfunc main() { undefined() }\n",
+			wantErrors: "./prog.go:3:15: undefined: undefined\n",
+		},
+		{
+			name:       "truncated",
+			src:        "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Print(\"hello, world\") }\n",
+			wantOutput: "hello, wor",
+			wantErrors: "output truncated",
+		},
+		{
+			name:       "timeout",
+			src:        "package main\n\nfunc main() { for {} }\n",
+			wantErrors: "process took too long",
+			wantStatus: -1,
+		},
+		{
+			name:       "memory limit",
+			src:        "package main\n\nvar b []byte\n\nfunc main() { b = make([]byte, 2<<30) }\n",
+			wantOutput: "runtime: o",
+			wantErrors: "output truncated",
+			wantStatus: 2,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			form := url.Values{"version": {"2"}, "body": {test.src}}
+			r := httptest.NewRequest("POST", "/play/compile", strings.NewReader(form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+			s.handlePlay(w, r)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+			}
+			var res playResult
+			if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+				t.Fatal(err)
+			}
+			var output string
+			for _, e := range res.Events {
+				output += e.Message
+			}
+			if output != test.wantOutput {
+				t.Errorf("output = %q, want %q", output, test.wantOutput)
+			}
+			if res.Errors != test.wantErrors {
+				t.Errorf("Errors = %q, want %q", res.Errors, test.wantErrors)
+			}
+			if res.Status != test.wantStatus {
+				t.Errorf("Status = %d, want %d", res.Status, test.wantStatus)
+			}
+		})
+	}
+}
+
+func TestLocalPlaygroundModules(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+	// A module proxy in a directory, holding example.com/greet.
+	proxyDir := tempDir(t, "proxy")
+	vdir := filepath.Join(proxyDir, "example.com", "greet", "@v")
+	if err := os.MkdirAll(vdir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	var zbuf bytes.Buffer
+	zw := zip.NewWriter(&zbuf)
+	for name, contents := range map[string]string{
+		"go.mod":   "module example.com/greet\n",
+		"greet.go": "package greet\n\nfunc Hello() string { return \"hello\" }\n",
+	} {
+		w, err := zw.Create("example.com/greet@v1.0.0/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, contents)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for name, contents := range map[string][]byte{
+		"list":        []byte("v1.0.0\n"),
+		"v1.0.0.info": []byte(`{"Version":"v1.0.0"}`),
+		"v1.0.0.mod":  []byte("module example.com/greet\n"),
+		"v1.0.0.zip":  zbuf.Bytes(),
+	} {
+		if err := ioutil.WriteFile(filepath.Join(vdir, name), contents, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	const src = "package main\n\nimport (\n\t\"fmt\"\n\n\t\"example.com/greet\"\n)\n\nfunc main() { fmt.Print(greet.Hello()) }\n"
+	run := func(cfg LocalPlaygroundConfig) *playResult {
+		t.Helper()
+		// -modcacherw lets the module cache be removed after the test.
+		cfg.GOFLAGS = "-mod=mod -modcacherw"
+		res, err := newTestLocalPlayground(t, cfg).run(context.Background(), src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	buildCache, modCache := tempDir(t, "gocache"), tempDir(t, "gomodcache")
+	// By default, modules are not downloaded.
+	if res := run(LocalPlaygroundConfig{BuildCache: buildCache, ModCache: tempDir(t, "gomodcache")}); !strings.Contains(res.Errors, "example.com/greet") {
+		t.Errorf("with the default GOPROXY and an empty module cache: got Errors %q, want an error about example.com/greet", res.Errors)
+	}
+	// Modules downloaded from the configured proxy are kept in the module
+	// cache, so they can be used without a proxy afterwards.
+	for _, goproxy := range []string{"file://" + filepath.ToSlash(proxyDir), ""} {
+		res := run(LocalPlaygroundConfig{BuildCache: buildCache, ModCache: modCache, GOPROXY: goproxy})
+		if res.Errors != "" || len(res.Events) != 1 || res.Events[0].Message != "hello" {
+			t.Errorf("GOPROXY=%q: got Errors %q, Events %v; want output \"hello\"", goproxy, res.Errors, res.Events)
+		}
+	}
+	if entries, err := ioutil.ReadDir(buildCache); err != nil || len(entries) == 0 {
+		t.Errorf("build cache is empty (error: %v)", err)
+	}
+}
+
+func TestRemotePlayground(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/compile" {
+			http.NotFound(w, r)
+			return
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(b)
+	}))
+	defer ts.Close()
+	s := &Server{playground: NewRemotePlayground(ts.URL)}
+
+	w := httptest.NewRecorder()
+	s.handlePlay(w, httptest.NewRequest("POST", "/play/compile", strings.NewReader("body=x")))
+	if w.Code != http.StatusOK || w.Body.String() != "body=x" {
+		t.Errorf("run: got %d %q, want the forwarded response", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	s.handlePlay(w, httptest.NewRequest("GET", "/play/p/abc", nil))
+	if got, want := w.Header().Get("Location"), ts.URL+"/p/abc"; w.Code != http.StatusFound || got != want {
+		t.Errorf("snippet: got %d, Location %q; want %d, %q", w.Code, got, http.StatusFound, want)
+	}
+}
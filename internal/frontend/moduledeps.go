@@ -0,0 +1,159 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"golang.org/x/mod/semver"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/postgres"
+)
+
+// ModuleDependentsDetails contains the modules that require a module, for
+// the dependents tab.
+type ModuleDependentsDetails struct {
+	ModulePath string
+
+	// Groups holds the dependents grouped by the version of the module that
+	// they require, highest version first.
+	Groups []*ModuleDependentsGroup
+
+	Total        int  // number of dependents in Groups
+	TotalIsExact bool // if false, then there may be more than Total
+}
+
+// ModuleDependentsGroup is the collection of dependents that require the
+// same version of a module.
+type ModuleDependentsGroup struct {
+	// Required is the required version of the module.
+	Required GoModModuleVersion
+	// Dependents are the latest versions of the modules that require it,
+	// ordered by module path.
+	Dependents []*GoModModuleVersion
+}
+
+// ModuleDependenciesDetails contains the build list of a module version, for
+// the dependencies tab.
+type ModuleDependenciesDetails struct {
+	ModulePath string
+	Version    string
+
+	// HasGoMod reports whether the go.mod file of the module version is
+	// known. If it is not, the dependencies cannot be computed.
+	HasGoMod bool
+
+	// Dependencies are the module versions selected by minimal version
+	// selection, ordered by module path.
+	Dependencies []*ModuleDependencyVersion
+
+	// Incomplete reports whether the requirements of some of the
+	// dependencies are unknown, so that the list may be missing some
+	// dependencies or have lower versions of them.
+	Incomplete bool
+}
+
+// ModuleDependencyVersion is a module version in a build list.
+type ModuleDependencyVersion struct {
+	GoModModuleVersion
+	// Direct reports whether the module is required by the go.mod file of
+	// the main module.
+	Direct bool
+	// RequirementsUnknown reports whether the requirements of the module
+	// version are unknown.
+	RequirementsUnknown bool
+}
+
+// tabModuleDependentsLimit is the maximum number of dependents displayed on
+// the dependents page.
+var tabModuleDependentsLimit = 10001
+
+// fetchModuleDependentsDetails fetches the modules that require the module
+// described by um and returns a ModuleDependentsDetails.
+func fetchModuleDependentsDetails(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta) (_ *ModuleDependentsDetails, err error) {
+	defer derrors.Wrap(&err, "fetchModuleDependentsDetails(%q)", um.ModulePath)
+
+	db, ok := ds.(*postgres.DB)
+	if !ok {
+		// The proxydatasource does not support the dependents page.
+		return nil, proxydatasourceNotSupportedErr()
+	}
+	dependents, err := db.GetModuleDependents(ctx, um.ModulePath, tabModuleDependentsLimit)
+	if err != nil {
+		return nil, err
+	}
+	// If we reached the query limit, then we don't know the total.
+	totalIsExact := true
+	if len(dependents) == tabModuleDependentsLimit {
+		dependents = dependents[:len(dependents)-1]
+		totalIsExact = false
+	}
+	return &ModuleDependentsDetails{
+		ModulePath:   um.ModulePath,
+		Groups:       groupModuleDependents(um.ModulePath, dependents),
+		Total:        len(dependents),
+		TotalIsExact: totalIsExact,
+	}, nil
+}
+
+// groupModuleDependents groups dependents of the module with the given path
+// by the version they require, highest version first.
+func groupModuleDependents(modulePath string, dependents []*internal.ModuleDependent) []*ModuleDependentsGroup {
+	byVersion := map[string]*ModuleDependentsGroup{}
+	var groups []*ModuleDependentsGroup
+	for _, d := range dependents {
+		g := byVersion[d.RequiredVersion]
+		if g == nil {
+			g = &ModuleDependentsGroup{Required: goModModuleVersion(modulePath, d.RequiredVersion)}
+			byVersion[d.RequiredVersion] = g
+			groups = append(groups, g)
+		}
+		mv := goModModuleVersion(d.ModulePath, d.Version)
+		g.Dependents = append(g.Dependents, &mv)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		vi, vj := groups[i].Required.Version, groups[j].Required.Version
+		if c := semver.Compare(vi, vj); c != 0 {
+			return c > 0
+		}
+		return vi > vj
+	})
+	return groups
+}
+
+// fetchModuleDependenciesDetails computes the build list of the module
+// version described by um and returns a ModuleDependenciesDetails.
+func fetchModuleDependenciesDetails(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta) (_ *ModuleDependenciesDetails, err error) {
+	defer derrors.Wrap(&err, "fetchModuleDependenciesDetails(%q, %q)", um.ModulePath, um.Version)
+
+	db, ok := ds.(*postgres.DB)
+	if !ok {
+		// The proxydatasource does not support the dependencies page.
+		return nil, proxydatasourceNotSupportedErr()
+	}
+	details := &ModuleDependenciesDetails{ModulePath: um.ModulePath, Version: um.Version}
+	deps, err := db.GetModuleDependencies(ctx, um.ModulePath, um.Version)
+	if errors.Is(err, derrors.NotFound) {
+		return details, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	details.HasGoMod = true
+	for _, d := range deps {
+		details.Dependencies = append(details.Dependencies, &ModuleDependencyVersion{
+			GoModModuleVersion:  goModModuleVersion(d.ModulePath, d.Version),
+			Direct:              d.Direct,
+			RequirementsUnknown: d.RequirementsUnknown,
+		})
+		if d.RequirementsUnknown {
+			details.Incomplete = true
+		}
+	}
+	return details, nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+)
+
+func TestGroupModuleDependents(t *testing.T) {
+	dependents := []*internal.ModuleDependent{
+		{ModulePath: "example.com/a", Version: "v0.1.0", RequiredVersion: "v1.2.0"},
+		{ModulePath: "example.com/b", Version: "v1.0.0", RequiredVersion: "v1.10.0"},
+		{ModulePath: "example.com/c", Version: "v2.0.0", RequiredVersion: "v1.2.0"},
+	}
+	got := groupModuleDependents("example.com/lib", dependents)
+	want := []*ModuleDependentsGroup{
+		{
+			Required: GoModModuleVersion{ModulePath: "example.com/lib", Version: "v1.10.0", URL: "/example.com/lib@v1.10.0"},
+			Dependents: []*GoModModuleVersion{
+				{ModulePath: "example.com/b", Version: "v1.0.0", URL: "/example.com/b@v1.0.0"},
+			},
+		},
+		{
+			Required: GoModModuleVersion{ModulePath: "example.com/lib", Version: "v1.2.0", URL: "/example.com/lib@v1.2.0"},
+			Dependents: []*GoModModuleVersion{
+				{ModulePath: "example.com/a", Version: "v0.1.0", URL: "/example.com/a@v0.1.0"},
+				{ModulePath: "example.com/c", Version: "v2.0.0", URL: "/example.com/c@v2.0.0"},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
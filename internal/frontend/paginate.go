@@ -70,6 +70,17 @@ func (p paginationParams) offset() int {
 	return offset(p.page, p.limit)
 }
 
+// firstPageURL returns the base URL with the query parameter key set to value,
+// and without a page, so that it displays the first page of the results.
+func (p paginationParams) firstPageURL(key, value string) string {
+	u := *p.baseURL
+	q := u.Query()
+	q.Set(key, value)
+	q.Del("page")
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
 // newPaginationParams extracts pagination params from the request.
 func newPaginationParams(r *http.Request, defaultLimit int) paginationParams {
 	positiveParam := func(key string, dflt int) (val int) {
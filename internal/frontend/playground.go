@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
@@ -15,7 +16,7 @@ import (
 	"golang.org/x/pkgsite/internal/log"
 )
 
-// playgroundURL is the playground endpoint used for share links.
+// playgroundURL is the playground endpoint used by default.
 const playgroundURL = "https://play.golang.org"
 
 var (
@@ -35,9 +36,65 @@ var (
 	}
 )
 
-// handlePlay handles requests that mirror play.golang.org/share.
+// A PlaygroundBackend serves the requests made by the buttons of runnable
+// examples, under /play/.
+type PlaygroundBackend interface {
+	// Share stores the program in the body of the POST request r, and
+	// responds with an ID for it.
+	Share(w http.ResponseWriter, r *http.Request)
+	// Run compiles and runs the program in the "body" form value of the POST
+	// request r, and responds with the result, JSON-encoded like the
+	// responses of play.golang.org/compile.
+	Run(w http.ResponseWriter, r *http.Request)
+	// ServeSnippet serves the program shared with the given ID.
+	ServeSnippet(w http.ResponseWriter, r *http.Request, id string)
+}
+
+// NewRemotePlayground returns a PlaygroundBackend that forwards requests to
+// the Go playground at pgURL, such as https://play.golang.org.
+func NewRemotePlayground(pgURL string) PlaygroundBackend {
+	return remotePlayground{url: pgURL}
+}
+
+// remotePlayground is a PlaygroundBackend that forwards requests to a Go
+// playground.
+type remotePlayground struct {
+	url string
+}
+
+// Share implements PlaygroundBackend.Share.
+func (p remotePlayground) Share(w http.ResponseWriter, r *http.Request) {
+	makeFetchPlayRequest(w, r, p.url)
+}
+
+// Run implements PlaygroundBackend.Run.
+func (p remotePlayground) Run(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		httpErrorStatus(w, http.StatusMethodNotAllowed)
+		return
+	}
+	forwardPlayRequest(w, r, p.url+"/compile", "application/x-www-form-urlencoded")
+}
+
+// ServeSnippet implements PlaygroundBackend.ServeSnippet.
+func (p remotePlayground) ServeSnippet(w http.ResponseWriter, r *http.Request, id string) {
+	http.Redirect(w, r, p.url+"/p/"+id, http.StatusFound)
+}
+
+// handlePlay handles requests that mirror play.golang.org/share at /play/,
+// play.golang.org/compile at /play/compile, and play.golang.org/p/<id> at
+// /play/p/<id>, using the server's playground backend.
 func (s *Server) handlePlay(w http.ResponseWriter, r *http.Request) {
-	makeFetchPlayRequest(w, r, playgroundURL)
+	switch p := strings.TrimPrefix(r.URL.Path, "/play/"); {
+	case p == "":
+		s.playground.Share(w, r)
+	case p == "compile":
+		s.playground.Run(w, r)
+	case strings.HasPrefix(p, "p/") && !strings.Contains(p[len("p/"):], "/"):
+		s.playground.ServeSnippet(w, r, p[len("p/"):])
+	default:
+		httpErrorStatus(w, http.StatusNotFound)
+	}
 }
 
 func httpErrorStatus(w http.ResponseWriter, status int) {
@@ -45,29 +102,38 @@ func httpErrorStatus(w http.ResponseWriter, status int) {
 }
 
 func makeFetchPlayRequest(w http.ResponseWriter, r *http.Request, pgURL string) {
-	ctx := r.Context()
 	if r.Method != http.MethodPost {
 		httpErrorStatus(w, http.StatusMethodNotAllowed)
 		return
 	}
-	req, err := http.NewRequest("POST", pgURL+"/share", r.Body)
+	status := forwardPlayRequest(w, r, pgURL+"/share", "text/plain; charset=utf-8")
+	if status != 0 {
+		stats.RecordWithTags(r.Context(),
+			[]tag.Mutator{tag.Upsert(keyPlaygroundShareStatus, strconv.Itoa(status))},
+			playgroundShareStatus.M(int64(status)),
+		)
+	}
+}
+
+// forwardPlayRequest forwards the body of r to target in a POST request with
+// the given content type, and copies the response to w. It returns the status
+// of the response, or 0 if the request could not be made.
+func forwardPlayRequest(w http.ResponseWriter, r *http.Request, target, contentType string) int {
+	ctx := r.Context()
+	req, err := http.NewRequest("POST", target, r.Body)
 	if err != nil {
-		log.Errorf(ctx, "ERROR share error: %v", err)
+		log.Errorf(ctx, "ERROR playground request to %s: %v", target, err)
 		httpErrorStatus(w, http.StatusInternalServerError)
-		return
+		return 0
 	}
-	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Set("Content-Type", contentType)
 	req = req.WithContext(r.Context())
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		log.Errorf(ctx, "ERROR share error: %v", err)
+		log.Errorf(ctx, "ERROR playground request to %s: %v", target, err)
 		httpErrorStatus(w, http.StatusInternalServerError)
-		return
+		return 0
 	}
-	stats.RecordWithTags(r.Context(),
-		[]tag.Mutator{tag.Upsert(keyPlaygroundShareStatus, strconv.Itoa(resp.StatusCode))},
-		playgroundShareStatus.M(int64(resp.StatusCode)),
-	)
 	copyHeader := func(k string) {
 		if v := resp.Header.Get(k); v != "" {
 			w.Header().Set(k, v)
@@ -78,6 +144,7 @@ func makeFetchPlayRequest(w http.ResponseWriter, r *http.Request, pgURL string)
 	defer resp.Body.Close()
 	w.WriteHeader(resp.StatusCode)
 	if _, err := io.Copy(w, resp.Body); err != nil {
-		log.Errorf(ctx, "ERROR writing shareId: %v", err)
+		log.Errorf(ctx, "ERROR writing playground response: %v", err)
 	}
+	return resp.StatusCode
 }
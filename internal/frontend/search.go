@@ -8,9 +8,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"go/token"
 	"math"
 	"net/http"
 	"path"
+	"regexp"
 	"strings"
 	"time"
 
@@ -29,6 +31,26 @@ type SearchPage struct {
 	basePage
 	Pagination pagination
 	Results    []*SearchResult
+
+	// Filters are the search filters of the query, as removable chips.
+	Filters []*SearchFilterChip
+
+	// SuggestedQuery is a correction of a query that has no results, and
+	// SuggestedQueryURL is the URL of its search.
+	SuggestedQuery    string
+	SuggestedQueryURL string
+
+	// SuggestedPaths are existing paths similar to a query for a path that
+	// has no results.
+	SuggestedPaths []string
+}
+
+// SearchFilterChip describes a search filter that is applied to the results.
+type SearchFilterChip struct {
+	// Text is the filter as it is written in the query, as in "license:MIT".
+	Text string
+	// RemoveURL is the URL of the search without the filter.
+	RemoveURL string
 }
 
 // SearchResult contains data needed to display a single search result.
@@ -42,20 +64,35 @@ type SearchResult struct {
 	CommitTime     string
 	NumImportedBy  uint64
 	Approximate    bool
+
+	// SymbolName, SymbolKind, SymbolSynopsis and SymbolDeprecated describe
+	// the matching symbol of the package, for symbol searches.
+	SymbolName       string
+	SymbolKind       string
+	SymbolSynopsis   string
+	SymbolDeprecated bool
 }
 
-// fetchSearchPage fetches data matching the search query from the database and
-// returns a SearchPage.
-func fetchSearchPage(ctx context.Context, db *postgres.DB, query string, pageParams paginationParams) (*SearchPage, error) {
+// fetchSearchPage fetches data matching the search query and filters from the
+// database and returns a SearchPage.
+func fetchSearchPage(ctx context.Context, db *postgres.DB, query string, filters []internal.SearchFilter, pageParams paginationParams) (*SearchPage, error) {
 	maxResultCount := maxSearchOffset + pageParams.limit
-	dbresults, err := db.Search(ctx, query, pageParams.limit, pageParams.offset(), maxResultCount)
+	var (
+		dbresults []*internal.SearchResult
+		err       error
+	)
+	if identifier, kind, ok := parseSymbolQuery(query); ok {
+		dbresults, err = db.SearchSymbols(ctx, identifier, kind, filters, pageParams.limit, pageParams.offset(), maxResultCount)
+	} else {
+		dbresults, err = db.Search(ctx, query, filters, pageParams.limit, pageParams.offset(), maxResultCount)
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	var results []*SearchResult
 	for _, r := range dbresults {
-		results = append(results, &SearchResult{
+		sr := &SearchResult{
 			Name:           r.Name,
 			PackagePath:    r.PackagePath,
 			ModulePath:     r.ModulePath,
@@ -64,7 +101,14 @@ func fetchSearchPage(ctx context.Context, db *postgres.DB, query string, pagePar
 			Licenses:       r.Licenses,
 			CommitTime:     elapsedTime(r.CommitTime),
 			NumImportedBy:  r.NumImportedBy,
-		})
+		}
+		if r.Symbol != nil {
+			sr.SymbolName = r.Symbol.Name
+			sr.SymbolKind = string(r.Symbol.Kind)
+			sr.SymbolSynopsis = r.Symbol.Synopsis
+			sr.SymbolDeprecated = r.Symbol.Deprecated
+		}
+		results = append(results, sr)
 	}
 
 	var (
@@ -89,6 +133,135 @@ func fetchSearchPage(ctx context.Context, db *postgres.DB, query string, pagePar
 	}, nil
 }
 
+// symbolQueryKinds maps the prefixes of symbol queries that restrict the
+// kind of symbol to that kind.
+var symbolQueryKinds = map[string]internal.SymbolKind{
+	"const":  internal.SymbolKindConstant,
+	"var":    internal.SymbolKindVariable,
+	"func":   internal.SymbolKindFunction,
+	"type":   internal.SymbolKindType,
+	"method": internal.SymbolKindMethod,
+	"field":  internal.SymbolKindField,
+}
+
+// parseSymbolQuery reports whether query is a search for symbols, of the form
+// "#Identifier" or "kind:Identifier", where kind is a key of
+// symbolQueryKinds. If so, it returns the identifier and the kind, which is
+// empty for the first form.
+func parseSymbolQuery(query string) (identifier string, kind internal.SymbolKind, ok bool) {
+	if strings.HasPrefix(query, "#") {
+		identifier = query[1:]
+	} else if i := strings.IndexByte(query, ':'); i > 0 {
+		k, found := symbolQueryKinds[query[:i]]
+		if !found {
+			return "", "", false
+		}
+		identifier, kind = query[i+1:], k
+	} else {
+		return "", "", false
+	}
+	if !token.IsIdentifier(identifier) {
+		return "", "", false
+	}
+	return identifier, kind, true
+}
+
+// searchFilterRegexp matches a search filter in a query, of the form
+// "[-]kind:value".
+var searchFilterRegexp = regexp.MustCompile(`^(-?)(license|module|is|goos):(\S+)$`)
+
+// goosRegexp matches the possible values of a GOOS.
+var goosRegexp = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// parseSearchFilters extracts the search filters from query. It returns the
+// rest of the query as text, and an error wrapping derrors.InvalidArgument if
+// a filter is invalid. Words inside double quotes are never filters.
+func parseSearchFilters(query string) (text string, filters []internal.SearchFilter, err error) {
+	var (
+		words  []string
+		quoted bool
+	)
+	for _, w := range strings.Fields(query) {
+		if quoted {
+			quoted = strings.Count(w, `"`)%2 == 0
+			words = append(words, w)
+			continue
+		}
+		m := searchFilterRegexp.FindStringSubmatch(w)
+		if m == nil {
+			quoted = strings.Count(w, `"`)%2 == 1
+			words = append(words, w)
+			continue
+		}
+		f := internal.SearchFilter{
+			Kind:   internal.SearchFilterKind(m[2]),
+			Value:  m[3],
+			Negate: m[1] == "-",
+		}
+		switch f.Kind {
+		case internal.SearchFilterIs:
+			if f.Value != internal.SearchFilterIsCommand && f.Value != internal.SearchFilterIsInternal {
+				return "", nil, fmt.Errorf("%q: %w", w, derrors.InvalidArgument)
+			}
+		case internal.SearchFilterGOOS:
+			if !goosRegexp.MatchString(f.Value) {
+				return "", nil, fmt.Errorf("%q: %w", w, derrors.InvalidArgument)
+			}
+		}
+		filters = append(filters, f)
+	}
+	return strings.Join(words, " "), filters, nil
+}
+
+// searchFilterChips returns the chips for the filters of a search for text.
+// Each chip links to the search without its filter.
+func searchFilterChips(pageParams paginationParams, text string, filters []internal.SearchFilter) []*SearchFilterChip {
+	var chips []*SearchFilterChip
+	for i, f := range filters {
+		words := []string{}
+		if text != "" {
+			words = append(words, text)
+		}
+		for j, g := range filters {
+			if j != i {
+				words = append(words, g.String())
+			}
+		}
+		chips = append(chips, &SearchFilterChip{
+			Text:      f.String(),
+			RemoveURL: pageParams.firstPageURL("q", strings.Join(words, " ")),
+		})
+	}
+	return chips
+}
+
+// addSearchSuggestions adds suggestions to the page of a search for text
+// that has no results: a corrected query, and paths similar to text if it
+// looks like a path. Errors are logged, since the page is still useful without
+// suggestions.
+func addSearchSuggestions(ctx context.Context, db *postgres.DB, page *SearchPage, pageParams paginationParams, text string, filters []internal.SearchFilter) {
+	if strings.Contains(text, "/") && !strings.ContainsAny(text, " \"") {
+		paths, err := db.SimilarPaths(ctx, text, maxPathSuggestions)
+		if err != nil {
+			log.Error(ctx, err)
+		}
+		page.SuggestedPaths = paths
+	}
+	q, err := db.SuggestQuery(ctx, text)
+	if err != nil {
+		log.Error(ctx, err)
+	}
+	if q == "" {
+		return
+	}
+	words := []string{q}
+	for _, f := range filters {
+		words = append(words, f.String())
+	}
+	page.SuggestedQuery = strings.Join(words, " ")
+	page.SuggestedQueryURL = pageParams.firstPageURL("q", page.SuggestedQuery)
+}
+
 // approximateNumber returns an approximation of the estimate, calibrated by
 // the statistical estimate of standard error.
 // i.e., a number that isn't misleading when we say '1-10 of approximately N
@@ -166,13 +339,30 @@ func (s *Server) serveSearch(w http.ResponseWriter, r *http.Request, ds internal
 		}
 	}
 
-	if path := searchRequestRedirectPath(ctx, ds, query); path != "" {
-		http.Redirect(w, r, path, http.StatusFound)
-		return nil
+	text, filters, err := parseSearchFilters(query)
+	if err != nil {
+		return &serverError{
+			status: http.StatusBadRequest,
+			epage: &errorPage{
+				messageTemplate: template.MakeTrustedTemplate(
+					`<h3 class="Error-message">Invalid search filter.</h3>
+					<p class="Error-message">See the <a href="/search-help">search help</a> for the supported filters.</p>`),
+			},
+		}
 	}
-	page, err := fetchSearchPage(ctx, db, query, pageParams)
+	if len(filters) == 0 {
+		if path := searchRequestRedirectPath(ctx, ds, query); path != "" {
+			http.Redirect(w, r, path, http.StatusFound)
+			return nil
+		}
+	}
+	page, err := fetchSearchPage(ctx, db, text, filters, pageParams)
 	if err != nil {
-		return fmt.Errorf("fetchSearchPage(ctx, db, %q): %v", query, err)
+		return fmt.Errorf("fetchSearchPage(ctx, db, %q, %v): %v", text, filters, err)
+	}
+	page.Filters = searchFilterChips(pageParams, text, filters)
+	if len(page.Results) == 0 {
+		addSearchSuggestions(ctx, db, page, pageParams, text, filters)
 	}
 	page.basePage = s.newBasePage(r, fmt.Sprintf("%s - Search Results", query))
 	s.servePage(ctx, w, "search.tmpl", page)
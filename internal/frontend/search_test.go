@@ -6,12 +6,15 @@ package frontend
 
 import (
 	"context"
+	"errors"
+	"net/url"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/licenses"
 	"golang.org/x/pkgsite/internal/postgres"
 	"golang.org/x/pkgsite/internal/testing/sample"
@@ -42,10 +45,10 @@ func TestFetchSearchPage(t *testing.T) {
 						Version:           "v1.0.0",
 						IsRedistributable: true,
 					},
-					Documentation: &internal.Documentation{
+					Documentation: []*internal.Documentation{{
 						Synopsis: "foo is a package.",
 						Source:   []byte{},
-					},
+					}},
 					Readme: &internal.Readme{
 						Filepath: "readme",
 						Contents: "readme",
@@ -71,10 +74,10 @@ func TestFetchSearchPage(t *testing.T) {
 						Version:           "v1.0.0",
 						IsRedistributable: true,
 					},
-					Documentation: &internal.Documentation{
+					Documentation: []*internal.Documentation{{
 						Synopsis: "bar is used by foo.",
 						Source:   []byte{},
-					},
+					}},
 					Readme: &internal.Readme{
 						Filepath: "readme",
 						Contents: "readme",
@@ -112,7 +115,7 @@ func TestFetchSearchPage(t *testing.T) {
 						Name:           moduleBar.Packages()[0].Name,
 						PackagePath:    moduleBar.Packages()[0].Path,
 						ModulePath:     moduleBar.ModulePath,
-						Synopsis:       moduleBar.Packages()[0].Documentation.Synopsis,
+						Synopsis:       moduleBar.Packages()[0].Documentation[0].Synopsis,
 						DisplayVersion: moduleBar.Version,
 						Licenses:       []string{"MIT"},
 						CommitTime:     elapsedTime(moduleBar.CommitTime),
@@ -139,7 +142,7 @@ func TestFetchSearchPage(t *testing.T) {
 						Name:           moduleFoo.Packages()[0].Name,
 						PackagePath:    moduleFoo.Packages()[0].Path,
 						ModulePath:     moduleFoo.ModulePath,
-						Synopsis:       moduleFoo.Packages()[0].Documentation.Synopsis,
+						Synopsis:       moduleFoo.Packages()[0].Documentation[0].Synopsis,
 						DisplayVersion: moduleFoo.Version,
 						Licenses:       []string{"MIT"},
 						CommitTime:     elapsedTime(moduleFoo.CommitTime),
@@ -150,7 +153,7 @@ func TestFetchSearchPage(t *testing.T) {
 		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
-			got, err := fetchSearchPage(ctx, testDB, test.query, paginationParams{limit: 20, page: 1})
+			got, err := fetchSearchPage(ctx, testDB, test.query, nil, paginationParams{limit: 20, page: 1})
 			if err != nil {
 				t.Fatalf("fetchSearchPage(db, %q): %v", test.query, err)
 			}
@@ -191,6 +194,95 @@ func TestApproximateNumber(t *testing.T) {
 	}
 }
 
+func TestParseSymbolQuery(t *testing.T) {
+	for _, test := range []struct {
+		query, wantIdentifier string
+		wantKind              internal.SymbolKind
+		wantOK                bool
+	}{
+		{"#NewClient", "NewClient", "", true},
+		{"func:NewClient", "NewClient", internal.SymbolKindFunction, true},
+		{"method:ServeHTTP", "ServeHTTP", internal.SymbolKindMethod, true},
+		{"NewClient", "", "", false},
+		{"#New Client", "", "", false},
+		{"#", "", "", false},
+		{"package:http", "", "", false},
+		{"https://github.com/a/b", "", "", false},
+	} {
+		identifier, kind, ok := parseSymbolQuery(test.query)
+		if identifier != test.wantIdentifier || kind != test.wantKind || ok != test.wantOK {
+			t.Errorf("parseSymbolQuery(%q) = %q, %q, %t; want %q, %q, %t",
+				test.query, identifier, kind, ok, test.wantIdentifier, test.wantKind, test.wantOK)
+		}
+	}
+}
+
+func TestParseSearchFilters(t *testing.T) {
+	for _, test := range []struct {
+		query, wantText string
+		wantFilters     []internal.SearchFilter
+	}{
+		{"yaml", "yaml", nil},
+		{
+			"yaml license:MIT -is:internal",
+			"yaml",
+			[]internal.SearchFilter{
+				{Kind: internal.SearchFilterLicense, Value: "MIT"},
+				{Kind: internal.SearchFilterIs, Value: internal.SearchFilterIsInternal, Negate: true},
+			},
+		},
+		{
+			"module:github.com/foo/bar goos:windows is:command",
+			"",
+			[]internal.SearchFilter{
+				{Kind: internal.SearchFilterModule, Value: "github.com/foo/bar"},
+				{Kind: internal.SearchFilterGOOS, Value: "windows"},
+				{Kind: internal.SearchFilterIs, Value: internal.SearchFilterIsCommand},
+			},
+		},
+		// Symbol queries and quoted words are not filters.
+		{"func:NewClient", "func:NewClient", nil},
+		{`"go license:MIT" http`, `"go license:MIT" http`, nil},
+		{`"license:MIT" http`, `"license:MIT" http`, nil},
+	} {
+		text, filters, err := parseSearchFilters(test.query)
+		if err != nil {
+			t.Fatalf("parseSearchFilters(%q): %v", test.query, err)
+		}
+		if text != test.wantText {
+			t.Errorf("parseSearchFilters(%q): got text %q, want %q", test.query, text, test.wantText)
+		}
+		if diff := cmp.Diff(test.wantFilters, filters); diff != "" {
+			t.Errorf("parseSearchFilters(%q) mismatch (-want +got):\n%s", test.query, diff)
+		}
+	}
+
+	for _, query := range []string{"is:library", "http goos:Windows"} {
+		if _, _, err := parseSearchFilters(query); !errors.Is(err, derrors.InvalidArgument) {
+			t.Errorf("parseSearchFilters(%q): got error %v, want InvalidArgument", query, err)
+		}
+	}
+}
+
+func TestSearchFilterChips(t *testing.T) {
+	baseURL, err := url.Parse("/search?q=yaml+license%3AMIT+-is%3Ainternal&page=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filters := []internal.SearchFilter{
+		{Kind: internal.SearchFilterLicense, Value: "MIT"},
+		{Kind: internal.SearchFilterIs, Value: internal.SearchFilterIsInternal, Negate: true},
+	}
+	got := searchFilterChips(paginationParams{baseURL: baseURL, page: 2, limit: 10}, "yaml", filters)
+	want := []*SearchFilterChip{
+		{Text: "license:MIT", RemoveURL: "/search?q=yaml+-is%3Ainternal"},
+		{Text: "-is:internal", RemoveURL: "/search?q=yaml+license%3AMIT"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestSearchRequestRedirectPath(t *testing.T) {
 	// Experiments need to be set in the context, for DB work, and as
 	// a middleware, for request handling.
@@ -22,6 +22,7 @@ import (
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/experiment"
+	"golang.org/x/pkgsite/internal/godoc"
 	"golang.org/x/pkgsite/internal/godoc/dochtml"
 	"golang.org/x/pkgsite/internal/licenses"
 	"golang.org/x/pkgsite/internal/log"
@@ -46,6 +47,11 @@ type Server struct {
 	appVersionLabel      string
 	googleTagManagerID   string
 	serveStats           bool
+	liveReload           bool
+	playground           PlaygroundBackend
+	// typeCache holds the packages loaded to type-check the packages
+	// of unit pages.
+	typeCache *godoc.TypeCache
 
 	mu        sync.Mutex // Protects all fields below
 	templates map[string]*template.Template
@@ -65,6 +71,12 @@ type ServerConfig struct {
 	AppVersionLabel      string
 	GoogleTagManagerID   string
 	ServeStats           bool
+	// LiveReload makes pages reload when a LiveReloader served at
+	// LiveReloadPath reports that their module has changed.
+	LiveReload bool
+	// Playground serves the requests of the buttons of runnable examples.
+	// If nil, they are forwarded to play.golang.org.
+	Playground PlaygroundBackend
 }
 
 // NewServer creates a new Server for the given database and template directory.
@@ -90,6 +102,12 @@ func NewServer(scfg ServerConfig) (_ *Server, err error) {
 		appVersionLabel:      scfg.AppVersionLabel,
 		googleTagManagerID:   scfg.GoogleTagManagerID,
 		serveStats:           scfg.ServeStats,
+		liveReload:           scfg.LiveReload,
+		playground:           scfg.Playground,
+		typeCache:            godoc.NewTypeCache(typeCacheSize, typeCacheTTL),
+	}
+	if s.playground == nil {
+		s.playground = NewRemotePlayground(playgroundURL)
 	}
 	errorPageBytes, err := s.renderErrorPage(context.Background(), http.StatusInternalServerError, "error.tmpl", nil)
 	if err != nil {
@@ -107,10 +125,24 @@ func (s *Server) Install(handle func(string, http.Handler), redisClient *redis.C
 		detailHandler http.Handler = s.errorHandler(s.serveDetails)
 		fetchHandler  http.Handler = s.errorHandler(s.serveFetch)
 		searchHandler http.Handler = s.errorHandler(s.serveSearch)
+		apiHandler    http.Handler = s.apiHandler(s.serveAPIUnit)
+		badgeHandler  http.Handler = s.errorHandler(s.badgeHandler)
+		sourceHandler http.Handler = s.errorHandler(s.serveSourceFile)
+		// Suggestions are requested as the user types, so they are served
+		// as JSON even on error.
+		autocompleteHandler http.Handler = s.apiHandler(s.serveAutocomplete)
 	)
 	if redisClient != nil {
 		detailHandler = middleware.Cache("details", redisClient, detailsTTL, authValues)(detailHandler)
+		// API responses include latest version information, so they are
+		// always volatile.
+		apiHandler = middleware.Cache("api", redisClient, middleware.TTL(shortTTL), authValues)(apiHandler)
 		searchHandler = middleware.Cache("search", redisClient, middleware.TTL(defaultTTL), authValues)(searchHandler)
+		// Badges show the latest version and imported-by count, so they are
+		// volatile.
+		badgeHandler = middleware.Cache("badge", redisClient, middleware.TTL(shortTTL), authValues)(badgeHandler)
+		sourceHandler = middleware.Cache("source", redisClient, middleware.TTL(longTTL), authValues)(sourceHandler)
+		autocompleteHandler = middleware.Cache("autocomplete", redisClient, middleware.TTL(defaultTTL), authValues)(autocompleteHandler)
 	}
 	// Each AppEngine instance is created in response to a start request, which
 	// is an empty HTTP GET request to /_ah/start when scaling is set to manual
@@ -133,10 +165,14 @@ func (s *Server) Install(handle func(string, http.Handler), redisClient *redis.C
 	handle("/fetch/", fetchHandler)
 	handle("/play/", http.HandlerFunc(s.handlePlay))
 	handle("/search", searchHandler)
+	handle("/autocomplete", autocompleteHandler)
+	handle("/opensearch.xml", http.HandlerFunc(s.serveOpenSearchDescription))
 	handle("/search-help", s.staticPageHandler("search_help.tmpl", "Search Help"))
 	handle("/license-policy", s.licensePolicyHandler())
 	handle("/about", http.RedirectHandler("https://go.dev/about", http.StatusFound))
-	handle("/badge/", http.HandlerFunc(s.badgeHandler))
+	handle("/badge/", badgeHandler)
+	handle(sourceViewerPrefix, sourceHandler)
+	handle(apiUnitPrefix, apiHandler)
 	handle("/C", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Package "C" is a special case: redirect to /cmd/cgo.
 		// (This is what golang.org/C does.)
@@ -151,6 +187,7 @@ func (s *Server) Install(handle func(string, http.Handler), redisClient *redis.C
 		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 		http.ServeContent(w, r, "", time.Time{}, strings.NewReader(`User-agent: *
 Disallow: /search?*
+Disallow: /autocomplete?*
 Disallow: /fetch/*
 `))
 	}))
@@ -258,6 +295,10 @@ type basePage struct {
 	// AllowWideContent indicates whether the content should be displayed in a
 	// way that’s amenable to wider viewports.
 	AllowWideContent bool
+
+	// LiveReload indicates whether the page should reload when the local
+	// module it shows changes.
+	LiveReload bool
 }
 
 // licensePolicyPage is used to generate the static license policy page.
@@ -289,6 +330,7 @@ func (s *Server) newBasePage(r *http.Request, title string) basePage {
 		DevMode:            s.devMode,
 		AppVersionLabel:    s.appVersionLabel,
 		GoogleTagManagerID: s.googleTagManagerID,
+		LiveReload:         s.liveReload,
 	}
 }
 
@@ -511,7 +553,13 @@ func parsePageTemplates(base template.TrustedSource) (map[string]*template.Templ
 		{tsc("license_policy.tmpl")},
 		{tsc("search.tmpl")},
 		{tsc("search_help.tmpl")},
+		{tsc("source.tmpl")},
+		{tsc("unit_coverage.tmpl"), tsc("unit.tmpl")},
+		{tsc("unit_dependencies.tmpl"), tsc("unit.tmpl")},
+		{tsc("unit_dependents.tmpl"), tsc("unit.tmpl")},
 		{tsc("unit_details.tmpl"), tsc("unit.tmpl")},
+		{tsc("unit_diff.tmpl"), tsc("unit.tmpl")},
+		{tsc("unit_gomod.tmpl"), tsc("unit.tmpl")},
 		{tsc("unit_importedby.tmpl"), tsc("unit.tmpl")},
 		{tsc("unit_imports.tmpl"), tsc("unit.tmpl")},
 		{tsc("unit_licenses.tmpl"), tsc("unit.tmpl")},
@@ -730,6 +730,14 @@ func serverTestCases() []serverTestCase {
 			want: in("",
 				in("h3.Fetch-message.js-fetchMessage", hasText("example.com/unknown"))),
 		},
+		{
+			name:           "path not found, with suggestion",
+			urlPath:        "/github.com/valid/module_nam/foo",
+			wantStatusCode: http.StatusNotFound,
+			want: in("",
+				in("h3.Fetch-message.js-fetchMessage", hasText("github.com/valid/module_nam/foo")),
+				in(".Fetch-suggestions a", href("/"+sample.ModulePath+"/foo"))),
+		},
 		{
 			name:           "bad request, invalid github module path",
 			urlPath:        "/github.com/foo",
@@ -1388,12 +1396,23 @@ func TestCheckTemplates(t *testing.T) {
 		{"license_policy", nil, licensePolicyPage{}},
 		{"search", nil, SearchPage{}},
 		{"search_help", nil, basePage{}},
+		{"source", nil, SourcePage{}},
 		{"unit_details", nil, UnitPage{}},
 		{
 			"unit_details",
 			[]string{"unit_outline", "unit_readme", "unit_doc", "unit_files", "unit_directories"},
 			MainDetails{},
 		},
+		{"unit_coverage", nil, UnitPage{}},
+		{"unit_coverage", []string{"coverage"}, CoverageDetails{}},
+		{"unit_diff", nil, UnitPage{}},
+		{"unit_diff", []string{"diff"}, DiffDetails{}},
+		{"unit_gomod", nil, UnitPage{}},
+		{"unit_gomod", []string{"gomod"}, GoModDetails{}},
+		{"unit_dependents", nil, UnitPage{}},
+		{"unit_dependents", []string{"dependents"}, ModuleDependentsDetails{}},
+		{"unit_dependencies", nil, UnitPage{}},
+		{"unit_dependencies", []string{"dependencies"}, ModuleDependenciesDetails{}},
 		{"unit_importedby", nil, UnitPage{}},
 		{"unit_importedby", []string{"importedby"}, ImportedByDetails{}},
 		{"unit_imports", nil, UnitPage{}},
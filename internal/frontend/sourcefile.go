@@ -0,0 +1,132 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/google/safehtml"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// sourceViewerPrefix is the URL path prefix of the built-in source viewer.
+const sourceViewerPrefix = "/src/"
+
+// SourcePage contains data for the source viewer template.
+type SourcePage struct {
+	basePage
+	ModulePath     string
+	DisplayVersion string
+	// FilePath is the path of the file relative to the module root.
+	FilePath string
+	// PackageURL is the URL path of the package containing the file.
+	PackageURL string
+	// UpstreamURL is the URL of the file on its hosting site, if known.
+	UpstreamURL string
+	Lines       []*sourceLine
+}
+
+// A sourceLine is a line of a source file.
+type sourceLine struct {
+	// ID is the anchor of the line, "line-N".
+	ID     safehtml.Identifier
+	Number int
+	Text   string
+}
+
+// sourceViewerPath returns the URL path prefix under which the source viewer
+// serves the files of the given module version.
+func sourceViewerPath(modulePath, version string) string {
+	return sourceViewerPrefix + modulePath + "@" + version
+}
+
+// parseSourcePath parses the path of a source viewer request, of the form
+// <module>@<version>/<file>, where <file> is relative to the module root.
+func parseSourcePath(p string) (modulePath, version, filePath string, err error) {
+	defer derrors.Wrap(&err, "parseSourcePath(%q)", p)
+
+	i := strings.Index(p, "@")
+	if i < 0 {
+		return "", "", "", errors.New("missing version")
+	}
+	modulePath = p[:i]
+	j := strings.Index(p[i+1:], "/")
+	if j < 0 {
+		return "", "", "", errors.New("missing file path")
+	}
+	version, filePath = p[i+1:i+1+j], p[i+2+j:]
+	if modulePath == "" || version == "" || filePath == "" {
+		return "", "", "", errors.New("empty path element")
+	}
+	if path.Clean(filePath) != filePath {
+		return "", "", "", errors.New("file path is not clean")
+	}
+	return modulePath, version, filePath, nil
+}
+
+// serveSourceFile serves the built-in source viewer, for requests to
+// /src/<module>@<version>/<file>.
+func (s *Server) serveSourceFile(w http.ResponseWriter, r *http.Request, ds internal.DataSource) error {
+	ctx := r.Context()
+	modulePath, version, filePath, err := parseSourcePath(strings.TrimPrefix(r.URL.Path, sourceViewerPrefix))
+	if err != nil {
+		return &serverError{
+			status:       http.StatusBadRequest,
+			responseText: "Source paths have the form /src/<module>@<version>/<file>.",
+			err:          err,
+		}
+	}
+	if err := checkExcluded(ctx, ds, modulePath); err != nil {
+		return err
+	}
+	um, err := ds.GetUnitMeta(ctx, modulePath, internal.UnknownModulePath, version)
+	if err != nil && !errors.Is(err, derrors.NotFound) {
+		return err
+	}
+	if err != nil || um.ModulePath != modulePath {
+		return &serverError{status: http.StatusNotFound}
+	}
+	contents, err := ds.GetSourceFile(ctx, um.ModulePath, um.Version, filePath)
+	if errors.Is(err, derrors.NotFound) {
+		return &serverError{status: http.StatusNotFound, err: err}
+	}
+	if err != nil {
+		return err
+	}
+
+	page := &SourcePage{
+		basePage:       s.newBasePage(r, fmt.Sprintf("%s - %s", path.Base(filePath), modulePath)),
+		ModulePath:     um.ModulePath,
+		DisplayVersion: displayVersion(um.Version, um.ModulePath),
+		FilePath:       filePath,
+		PackageURL: constructUnitURL(path.Join(um.ModulePath, path.Dir(filePath)), um.ModulePath,
+			linkVersion(um.Version, um.ModulePath)),
+		UpstreamURL: um.SourceInfo.FileURL(filePath),
+		Lines:       sourceLines(string(contents)),
+	}
+	s.servePage(ctx, w, "source.tmpl", page)
+	return nil
+}
+
+// sourceLines splits contents into numbered lines.
+func sourceLines(contents string) []*sourceLine {
+	texts := strings.Split(strings.TrimSuffix(contents, "\n"), "\n")
+	lines := make([]*sourceLine, len(texts))
+	for i, t := range texts {
+		n := i + 1
+		lines[i] = &sourceLine{
+			ID:     safehtml.IdentifierFromConstantPrefix("line", strconv.Itoa(n)),
+			Number: n,
+			Text:   t,
+		}
+	}
+	return lines
+}
@@ -0,0 +1,112 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestParseSourcePath(t *testing.T) {
+	for _, test := range []struct {
+		in                        string
+		modulePath, version, file string
+		wantErr                   bool
+	}{
+		{in: "example.com/m@v1.0.0/a.go", modulePath: "example.com/m", version: "v1.0.0", file: "a.go"},
+		{in: "example.com/m@v1.0.0/p/q/b.go", modulePath: "example.com/m", version: "v1.0.0", file: "p/q/b.go"},
+		{in: "example.com/m/a.go", wantErr: true},
+		{in: "example.com/m@v1.0.0", wantErr: true},
+		{in: "example.com/m@v1.0.0/", wantErr: true},
+		{in: "@v1.0.0/a.go", wantErr: true},
+		{in: "example.com/m@/a.go", wantErr: true},
+		{in: "example.com/m@v1.0.0/p/../../a.go", wantErr: true},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			modulePath, version, file, err := parseSourcePath(test.in)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error: %t", err, test.wantErr)
+			}
+			if modulePath != test.modulePath || version != test.version || file != test.file {
+				t.Errorf("got (%q, %q, %q), want (%q, %q, %q)",
+					modulePath, version, file, test.modulePath, test.version, test.file)
+			}
+		})
+	}
+}
+
+func TestSourceInfo(t *testing.T) {
+	um := sample.UnitMeta(sample.PackagePath, sample.ModulePath, sample.VersionString, sample.Suffix, true)
+	um.SourceInfo = nil
+	if got, want := sourceInfo(um).FileURL("foo/a.go"), "/src/"+sample.ModulePath+"@"+sample.VersionString+"/foo/a.go"; got != want {
+		t.Errorf("FileURL = %q, want %q", got, want)
+	}
+	if got, want := sourceInfo(um).LineURL("foo/a.go", 3), "/src/"+sample.ModulePath+"@"+sample.VersionString+"/foo/a.go#line-3"; got != want {
+		t.Errorf("LineURL = %q, want %q", got, want)
+	}
+	um.IsRedistributable = false
+	if got := sourceInfo(um); got != nil {
+		t.Errorf("got %v for non-redistributable unit, want nil", got)
+	}
+}
+
+func TestServeSourceFile(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer postgres.ResetTestDB(testDB, t)
+
+	m := sample.Module(sample.ModulePath, sample.VersionString, sample.Suffix)
+	m.SourceFiles = []*internal.SourceFile{
+		{Path: sample.Suffix + "/file.go", Contents: []byte("package foo\n\nconst C = 1 < 2\n")},
+	}
+	if err := testDB.InsertModule(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+	_, handler, _ := newTestServer(t, nil)
+	prefix := "/src/" + sample.ModulePath + "@" + sample.VersionString + "/"
+	for _, test := range []struct {
+		path       string
+		wantStatus int
+		want       []string
+	}{
+		{
+			path:       prefix + sample.Suffix + "/file.go",
+			wantStatus: http.StatusOK,
+			want: []string{
+				`<span class="Source-line" id="line-3"><a class="Source-lineNumber" href="#line-3">3</a>const C = 1 &lt; 2</span>`,
+				`<a href="/` + sample.PackagePath + `@` + sample.VersionString + `">Package documentation</a>`,
+			},
+		},
+		{path: prefix + "missing.go", wantStatus: http.StatusNotFound},
+		{path: "/src/example.com/missing@v1.0.0/a.go", wantStatus: http.StatusNotFound},
+		{path: "/src/" + sample.ModulePath + "/a.go", wantStatus: http.StatusBadRequest},
+	} {
+		t.Run(test.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+			res := w.Result()
+			if res.StatusCode != test.wantStatus {
+				t.Fatalf("status = %d, want %d", res.StatusCode, test.wantStatus)
+			}
+			body, err := ioutil.ReadAll(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range test.want {
+				if !strings.Contains(string(body), want) {
+					t.Errorf("body does not contain %q", want)
+				}
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/godoc"
 )
 
 // TabSettings defines tab-specific metadata.
@@ -34,11 +35,16 @@ type TabSettings struct {
 }
 
 const (
-	tabMain       = ""
-	tabVersions   = "versions"
-	tabImports    = "imports"
-	tabImportedBy = "importedby"
-	tabLicenses   = "licenses"
+	tabMain         = ""
+	tabVersions     = "versions"
+	tabImports      = "imports"
+	tabImportedBy   = "importedby"
+	tabLicenses     = "licenses"
+	tabDiff         = "diff"
+	tabCoverage     = "coverage"
+	tabGoMod        = "gomod"
+	tabDependents   = "dependents"
+	tabDependencies = "dependencies"
 )
 
 var (
@@ -63,6 +69,26 @@ var (
 			Name:         tabLicenses,
 			TemplateName: "unit_licenses.tmpl",
 		},
+		{
+			Name:         tabDiff,
+			TemplateName: "unit_diff.tmpl",
+		},
+		{
+			Name:         tabCoverage,
+			TemplateName: "unit_coverage.tmpl",
+		},
+		{
+			Name:         tabGoMod,
+			TemplateName: "unit_gomod.tmpl",
+		},
+		{
+			Name:         tabDependents,
+			TemplateName: "unit_dependents.tmpl",
+		},
+		{
+			Name:         tabDependencies,
+			TemplateName: "unit_dependencies.tmpl",
+		},
 	}
 	unitTabLookup = make(map[string]TabSettings, len(unitTabs))
 )
@@ -75,12 +101,12 @@ func init() {
 
 // fetchDetailsForPackage returns tab details by delegating to the correct detail
 // handler.
-func fetchDetailsForUnit(ctx context.Context, r *http.Request, tab string, ds internal.DataSource, um *internal.UnitMeta) (_ interface{}, err error) {
+func fetchDetailsForUnit(ctx context.Context, r *http.Request, tab string, ds internal.DataSource, um *internal.UnitMeta, typeCache *godoc.TypeCache) (_ interface{}, err error) {
 	defer derrors.Wrap(&err, "fetchDetailsForUnit(r, %q, ds, um=%q,%q,%q)", tab, um.Path, um.ModulePath, um.Version)
 	switch tab {
 	case tabMain:
 		_, expandReadme := r.URL.Query()["readme"]
-		return fetchMainDetails(ctx, ds, um, expandReadme)
+		return fetchMainDetails(ctx, ds, um, expandReadme, buildContextFromRequest(r), typeCache)
 	case tabVersions:
 		return fetchVersionsDetails(ctx, ds, um.Path, um.ModulePath)
 	case tabImports:
@@ -89,6 +115,25 @@ func fetchDetailsForUnit(ctx context.Context, r *http.Request, tab string, ds in
 		return fetchImportedByDetails(ctx, ds, um.Path, um.ModulePath)
 	case tabLicenses:
 		return fetchLicensesDetails(ctx, ds, um)
+	case tabDiff:
+		return fetchDiffDetails(ctx, ds, um, r.FormValue("from"), buildContextFromRequest(r))
+	case tabCoverage:
+		return fetchCoverageDetails(ctx, ds, um)
+	case tabGoMod:
+		return fetchGoModDetails(ctx, ds, um)
+	case tabDependents:
+		return fetchModuleDependentsDetails(ctx, ds, um)
+	case tabDependencies:
+		return fetchModuleDependenciesDetails(ctx, ds, um)
 	}
 	return nil, fmt.Errorf("BUG: unable to fetch details: unknown tab %q", tab)
 }
+
+// buildContextFromRequest returns the build context requested by the GOOS and
+// GOARCH query parameters of r. Missing parameters match any value.
+func buildContextFromRequest(r *http.Request) internal.BuildContext {
+	return internal.BuildContext{
+		GOOS:   r.FormValue("GOOS"),
+		GOARCH: r.FormValue("GOARCH"),
+	}
+}
@@ -138,7 +138,7 @@ func (s *Server) serveUnitPage(ctx context.Context, w http.ResponseWriter, r *ht
 		PageLabels:       pageLabels(um),
 		PageType:         pageType(um),
 	}
-	d, err := fetchDetailsForUnit(ctx, r, tab, ds, um)
+	d, err := fetchDetailsForUnit(ctx, r, tab, ds, um, s.typeCache)
 	if err != nil {
 		return err
 	}
@@ -171,7 +171,7 @@ func isValidTabForUnit(tab string, um *internal.UnitMeta) bool {
 	if tab == tabLicenses && !um.IsRedistributable {
 		return false
 	}
-	if !um.IsPackage() && (tab == tabImports || tab == tabImportedBy) {
+	if !um.IsPackage() && (tab == tabImports || tab == tabImportedBy || tab == tabDiff || tab == tabCoverage) {
 		return false
 	}
 	return true
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"net/url"
 	"sort"
 	"strconv"
 	"strings"
@@ -18,6 +19,7 @@ import (
 	"golang.org/x/mod/semver"
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/experiment"
 	"golang.org/x/pkgsite/internal/godoc"
 	"golang.org/x/pkgsite/internal/godoc/dochtml"
 	"golang.org/x/pkgsite/internal/log"
@@ -103,6 +105,18 @@ type MainDetails struct {
 
 	// IsStableVersion is true if the major version is v1 or greater.
 	IsStableVersion bool
+
+	// BuildContexts are links to the documentation of the unit for each
+	// build context it has documentation for.
+	BuildContexts []*BuildContextLink
+}
+
+// BuildContextLink is a link to the documentation of a unit for a build
+// context.
+type BuildContextLink struct {
+	Name     string // GOOS/GOARCH
+	URL      string
+	Selected bool // whether this is the build context being displayed
 }
 
 // File is a source file for a package.
@@ -141,10 +155,13 @@ type UnitDirectory struct {
 	Subdirectories []*Subdirectory
 }
 
-func fetchMainDetails(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta, expandReadme bool) (_ *MainDetails, err error) {
+// fetchMainDetails returns the details of the main tab of the unit described
+// by um. Packages imported by the unit that are type-checked for it are kept
+// in typeCache.
+func fetchMainDetails(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta, expandReadme bool, bc internal.BuildContext, typeCache *godoc.TypeCache) (_ *MainDetails, err error) {
 	defer middleware.ElapsedStat(ctx, "fetchMainDetails")()
 
-	unit, err := ds.GetUnit(ctx, um, internal.WithMain)
+	unit, err := ds.GetUnit(ctx, um, internal.WithMain, bc)
 	if err != nil {
 		return nil, err
 	}
@@ -169,11 +186,14 @@ func fetchMainDetails(ctx context.Context, ds internal.DataSource, um *internal.
 		docLinks, modLinks []link
 		files              []*File
 		synopsis           string
+		docBuildContext    internal.BuildContext
 	)
-	if unit.Documentation != nil {
-		synopsis = unit.Documentation.Synopsis
+	if len(unit.Documentation) > 0 {
+		doc := unit.Documentation[0]
+		synopsis = doc.Synopsis
+		docBuildContext = doc.BuildContext()
 		end := middleware.ElapsedStat(ctx, "DecodePackage")
-		docPkg, err := godoc.DecodePackage(unit.Documentation.Source)
+		docPkg, err := godoc.DecodePackage(doc.Source)
 		end()
 		if err != nil {
 			if errors.Is(err, godoc.ErrInvalidEncodingType) {
@@ -184,7 +204,14 @@ func fetchMainDetails(ctx context.Context, ds internal.DataSource, um *internal.
 			}
 			return nil, err
 		}
-		docParts, err = getHTML(ctx, unit, docPkg)
+		if experiment.IsActive(ctx, internal.ExperimentTypeCheck) {
+			typeCheck(ctx, ds, unit, docPkg, docBuildContext, typeCache)
+		}
+		sinceVersions, err := getSinceVersions(ctx, ds, unit)
+		if err != nil {
+			return nil, err
+		}
+		docParts, err = getHTML(ctx, unit, docPkg, sinceVersions)
 		// If err  is ErrTooLarge, then docBody will have an appropriate message.
 		if err != nil && !errors.Is(err, dochtml.ErrTooLarge) {
 			return nil, err
@@ -195,6 +222,12 @@ func fetchMainDetails(ctx context.Context, ds internal.DataSource, um *internal.
 		end = middleware.ElapsedStat(ctx, "sourceFiles")
 		files = sourceFiles(unit, docPkg)
 		end()
+	} else if len(unit.BuildContexts) > 0 {
+		// The unit has documentation, but not for the requested build context.
+		docParts.Body, err = godoc.NoDocumentationHTML(orAny(bc.GOOS), orAny(bc.GOARCH))
+		if err != nil {
+			return nil, err
+		}
 	}
 	// If the unit is not a module, fetch the module readme to extract its
 	// links.
@@ -245,9 +278,37 @@ func fetchMainDetails(ctx context.Context, ds internal.DataSource, um *internal.
 		ModFileURL:        um.SourceInfo.ModuleURL() + "/go.mod",
 		IsTaggedVersion:   isTaggedVersion,
 		IsStableVersion:   semver.Major(um.Version) != "v0",
+		BuildContexts:     buildContextLinks(unit.BuildContexts, docBuildContext),
 	}, nil
 }
 
+// buildContextLinks returns a link for each build context in bcs, marking the
+// one for selected. It returns nil if the only build context is the selected
+// one, since there is nothing to choose from.
+func buildContextLinks(bcs []internal.BuildContext, selected internal.BuildContext) []*BuildContextLink {
+	if len(bcs) == 1 && bcs[0].GOOS == selected.GOOS && bcs[0].GOARCH == selected.GOARCH {
+		return nil
+	}
+	var links []*BuildContextLink
+	for _, bc := range bcs {
+		q := url.Values{"GOOS": {bc.GOOS}, "GOARCH": {bc.GOARCH}}
+		links = append(links, &BuildContextLink{
+			Name:     bc.GOOS + "/" + bc.GOARCH,
+			URL:      "?" + q.Encode(),
+			Selected: bc.GOOS == selected.GOOS && bc.GOARCH == selected.GOARCH,
+		})
+	}
+	return links
+}
+
+// orAny returns s, or "*" if s is empty.
+func orAny(s string) string {
+	if s == "" {
+		return "*"
+	}
+	return s
+}
+
 // readmeContent renders the readme to html and collects the headings
 // into an outline.
 func readmeContent(ctx context.Context, u *internal.Unit) (_ *Readme, err error) {
@@ -345,11 +406,11 @@ func getSubdirectories(um *internal.UnitMeta, pkgs []*internal.PackageMeta) []*S
 
 const missingDocReplacement = `<p>Documentation is missing.</p>`
 
-func getHTML(ctx context.Context, u *internal.Unit, docPkg *godoc.Package) (_ *dochtml.Parts, err error) {
+func getHTML(ctx context.Context, u *internal.Unit, docPkg *godoc.Package, sinceVersions map[string]string) (_ *dochtml.Parts, err error) {
 	defer derrors.Wrap(&err, "getHTML(%s)", u.Path)
 
-	if len(u.Documentation.Source) > 0 {
-		return renderDocParts(ctx, u, docPkg)
+	if len(u.Documentation[0].Source) > 0 {
+		return renderDocParts(ctx, u, docPkg, sinceVersions)
 	}
 	log.Errorf(ctx, "unit %s (%s@%s) missing documentation source", u.Path, u.ModulePath, u.Version)
 	return &dochtml.Parts{Body: template.MustParseAndExecuteToHTML(missingDocReplacement)}, nil
@@ -252,3 +252,21 @@ func TestUnitDirectories(t *testing.T) {
 		t.Errorf("unitDirectories mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func TestBuildContextLinks(t *testing.T) {
+	bcs := []internal.BuildContext{
+		{GOOS: "linux", GOARCH: "amd64"},
+		{GOOS: "windows", GOARCH: "amd64"},
+	}
+	got := buildContextLinks(bcs, internal.BuildContext{GOOS: "windows", GOARCH: "amd64"})
+	want := []*BuildContextLink{
+		{Name: "linux/amd64", URL: "?GOARCH=amd64&GOOS=linux"},
+		{Name: "windows/amd64", URL: "?GOARCH=amd64&GOOS=windows", Selected: true},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+	if got := buildContextLinks(bcs[:1], bcs[0]); got != nil {
+		t.Errorf("got %v for a single selected build context, want nil", got)
+	}
+}
@@ -59,6 +59,10 @@ type VersionSummary struct {
 	// Link to this version, for use in the anchor href.
 	Link    string
 	Version string
+	// Retracted reports whether the version is retracted by the module
+	// author, with RetractionRationale as the reason, if any.
+	Retracted           bool
+	RetractionRationale string
 }
 
 func fetchVersionsDetails(ctx context.Context, ds internal.DataSource, fullPath, modulePath string) (*VersionsDetails, error) {
@@ -146,9 +150,11 @@ func buildVersionDetails(currentModulePath string, modInfos []*internal.ModuleIn
 		}
 		key := VersionListKey{ModulePath: mi.ModulePath, Major: major}
 		vs := &VersionSummary{
-			Link:       linkify(mi),
-			CommitTime: absoluteTime(mi.CommitTime),
-			Version:    linkVersion(mi.Version, mi.ModulePath),
+			Link:                linkify(mi),
+			CommitTime:          absoluteTime(mi.CommitTime),
+			Version:             linkVersion(mi.Version, mi.ModulePath),
+			Retracted:           mi.Retracted,
+			RetractionRationale: mi.RetractionRationale,
 		}
 		if _, ok := lists[key]; !ok {
 			seenLists = append(seenLists, key)
@@ -61,7 +61,7 @@ func TestFetchPackageVersionsDetails(t *testing.T) {
 			"v1.2.1",
 			sample.Suffix,
 			true),
-		Documentation: sample.Documentation,
+		Documentation: []*internal.Documentation{sample.Documentation},
 	}
 	pkg2 := &internal.Unit{
 		UnitMeta: *sample.UnitMeta(
@@ -70,7 +70,7 @@ func TestFetchPackageVersionsDetails(t *testing.T) {
 			"v1.2.1-alpha.1",
 			sample.Suffix,
 			true),
-		Documentation: sample.Documentation,
+		Documentation: []*internal.Documentation{sample.Documentation},
 	}
 	nethttpPkg := &internal.Unit{
 		UnitMeta: *sample.UnitMeta(
@@ -79,7 +79,7 @@ func TestFetchPackageVersionsDetails(t *testing.T) {
 			"v1.12.5",
 			"http",
 			true),
-		Documentation: sample.Documentation,
+		Documentation: []*internal.Documentation{sample.Documentation},
 	}
 	makeList := func(pkgPath, modulePath, major string, versions []string) *VersionList {
 		return &VersionList{
@@ -259,3 +259,25 @@ func TestPseudoVersionBase(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildVersionDetailsRetracted(t *testing.T) {
+	mis := []*internal.ModuleInfo{
+		{ModulePath: modulePath1, Version: "v1.1.0", CommitTime: sample.CommitTime, Retracted: true, RetractionRationale: "Data race."},
+		{ModulePath: modulePath1, Version: "v1.0.0", CommitTime: sample.CommitTime},
+	}
+	got := buildVersionDetails(modulePath1, mis, func(mi *internal.ModuleInfo) string { return mi.Version })
+	want := &VersionsDetails{
+		ThisModule: []*VersionList{
+			{
+				VersionListKey: VersionListKey{ModulePath: modulePath1, Major: "v1"},
+				Versions: []*VersionSummary{
+					{Link: "v1.1.0", Version: "v1.1.0", CommitTime: commitTime, Retracted: true, RetractionRationale: "Data race."},
+					{Link: "v1.0.0", Version: "v1.0.0", CommitTime: commitTime},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package apidiff computes the differences between the exported APIs of two
+// versions of a package.
+//
+// The comparison is syntactic: it works on the ASTs stored with a package's
+// documentation, without type information. Two declarations are considered
+// the same if their types are written the same way, ignoring parameter and
+// receiver names.
+package apidiff
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/pkgsite/internal/godoc"
+)
+
+// A ChangeKind describes how an exported identifier changed.
+type ChangeKind int
+
+const (
+	// Added means the identifier is present only in the new version.
+	Added ChangeKind = iota
+	// Removed means the identifier is present only in the old version.
+	Removed
+	// Changed means the identifier is present in both versions, with
+	// different declarations.
+	Changed
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	default:
+		return "unknown"
+	}
+}
+
+// A Change is a difference in a single exported identifier.
+type Change struct {
+	// Name is the name of the identifier. Methods, struct fields and
+	// interface methods are qualified by their type name, as in "T.M".
+	Name string
+	Kind ChangeKind
+	// Old and New are the declarations of the identifier in the old and new
+	// versions. Old is empty for added identifiers, New for removed ones.
+	Old, New string
+}
+
+// Incompatible reports whether the change may break existing users of the
+// package. Removing or changing an identifier is incompatible. Adding one is
+// not, except for adding a method to an interface, which breaks existing
+// implementations of it. Embedding another interface in an interface changes
+// the declaration of the interface type, so it is incompatible too.
+func (c *Change) Incompatible() bool {
+	if c.Kind == Added {
+		return strings.HasPrefix(c.New, "method ")
+	}
+	return true
+}
+
+// A Report is the list of differences between two versions of a package,
+// sorted by name.
+type Report struct {
+	Changes []*Change
+	// Deprecated is the sorted names of the identifiers that are deprecated
+	// in the new version but not in the old one. Deprecation does not
+	// change the API, so it is not reported as a Change.
+	Deprecated []string
+}
+
+// Incompatible returns the incompatible changes in r.
+func (r *Report) Incompatible() []*Change {
+	var cs []*Change
+	for _, c := range r.Changes {
+		if c.Incompatible() {
+			cs = append(cs, c)
+		}
+	}
+	return cs
+}
+
+// Compatible returns the compatible changes in r.
+func (r *Report) Compatible() []*Change {
+	var cs []*Change
+	for _, c := range r.Changes {
+		if !c.Incompatible() {
+			cs = append(cs, c)
+		}
+	}
+	return cs
+}
+
+// Diff returns the differences between the exported APIs of from, the old
+// version of a package, and to, the new one. Either may be nil, meaning the
+// package does not exist in that version.
+func Diff(from, to *godoc.Package) *Report {
+	oldAPI, newAPI := exportedAPI(from), exportedAPI(to)
+	r := &Report{}
+	for name, o := range oldAPI {
+		n, ok := newAPI[name]
+		switch {
+		case !ok:
+			r.Changes = append(r.Changes, &Change{Name: name, Kind: Removed, Old: o})
+		case o != n:
+			r.Changes = append(r.Changes, &Change{Name: name, Kind: Changed, Old: o, New: n})
+		}
+	}
+	for name, n := range newAPI {
+		if _, ok := oldAPI[name]; !ok {
+			r.Changes = append(r.Changes, &Change{Name: name, Kind: Added, New: n})
+		}
+	}
+	sort.Slice(r.Changes, func(i, j int) bool { return r.Changes[i].Name < r.Changes[j].Name })
+	oldDeprecated := map[string]bool{}
+	for _, name := range DeprecatedSymbols(from) {
+		oldDeprecated[name] = true
+	}
+	for _, name := range DeprecatedSymbols(to) {
+		if !oldDeprecated[name] {
+			r.Deprecated = append(r.Deprecated, name)
+		}
+	}
+	return r
+}
+
+// DeprecatedSymbols returns the sorted names of the exported identifiers of p
+// whose doc comments mark them as deprecated, qualified as described for
+// Change.Name. Like godoc.Package.Symbols, it must be called before p is
+// rendered.
+func DeprecatedSymbols(p *godoc.Package) []string {
+	if p == nil {
+		return nil
+	}
+	var names []string
+	for _, s := range p.Symbols() {
+		if s.Deprecated {
+			names = append(names, s.Name)
+		}
+	}
+	return names
+}
+
+// exportedAPI returns a map from the names of the exported identifiers of p,
+// qualified as described for Change.Name, to their declarations. The
+// identifiers are those of godoc.Package.Symbols, so the differences agree
+// with symbol search and symbol history.
+func exportedAPI(p *godoc.Package) map[string]string {
+	if p == nil {
+		return map[string]string{}
+	}
+	return p.Declarations()
+}
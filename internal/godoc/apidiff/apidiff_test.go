@@ -0,0 +1,148 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package apidiff
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/godoc"
+)
+
+func newPackage(t *testing.T, src string) *godoc.Package {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := godoc.NewPackage(fset, "linux", "amd64", nil)
+	p.AddFile(f, true)
+	return p
+}
+
+func TestDiff(t *testing.T) {
+	from := newPackage(t, `
+		package p
+
+		const C = 1
+		var V int
+		func F(a, b int) error { return nil }
+		func G() {}
+		func unexported() {}
+		type S struct {
+			A int
+			b string
+		}
+		func (s *S) M(x string) {}
+		type I interface {
+			M()
+		}
+		type N int
+	`)
+	to := newPackage(t, `
+		package p
+
+		const C = 2
+		var V int64
+		func F(x, y int) error { return nil }
+		func H() {}
+		type S struct {
+			A int
+			B string
+		}
+		func (r *S) M(y string) {}
+		type I interface {
+			M()
+			N()
+		}
+		type N = int
+	`)
+	got := Diff(from, to)
+	want := &Report{Changes: []*Change{
+		{Name: "G", Kind: Removed, Old: "func G()"},
+		{Name: "H", Kind: Added, New: "func H()"},
+		{Name: "I.N", Kind: Added, New: "method N()"},
+		{Name: "N", Kind: Changed, Old: "type N int", New: "type N = int"},
+		{Name: "S.B", Kind: Added, New: "field B string"},
+		{Name: "V", Kind: Changed, Old: "var V int", New: "var V int64"},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	var incompatible []string
+	for _, c := range got.Incompatible() {
+		incompatible = append(incompatible, c.Name)
+	}
+	if diff := cmp.Diff([]string{"G", "I.N", "N", "V"}, incompatible); diff != "" {
+		t.Errorf("Incompatible mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestDiffMissingPackage(t *testing.T) {
+	p := newPackage(t, "package p; func F() {}")
+	got := Diff(nil, p)
+	want := &Report{Changes: []*Change{{Name: "F", Kind: Added, New: "func F()"}}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestDiffEmbeddedInterface(t *testing.T) {
+	from := newPackage(t, `package p
+		import "io"
+		type I interface{ N() }`)
+	to := newPackage(t, `package p
+		import "io"
+		type I interface{ io.Closer; N() }`)
+	got := Diff(from, to)
+	want := &Report{Changes: []*Change{
+		{Name: "I", Kind: Changed, Old: "type I interface", New: "type I interface embedding io.Closer"},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("mismatch (-want, +got):\n%s", diff)
+	}
+	if !got.Changes[0].Incompatible() {
+		t.Error("embedding an interface is compatible, want incompatible")
+	}
+}
+
+func TestDiffDeprecated(t *testing.T) {
+	from := newPackage(t, `
+		package p
+
+		// Deprecated: use G.
+		func F() {}
+		func G() {}
+		type S struct {
+			A int
+		}
+	`)
+	to := newPackage(t, `
+		package p
+
+		// Deprecated: use G.
+		func F() {}
+		// G does nothing.
+		//
+		// Deprecated: use H.
+		func G() {}
+		func H() {}
+		type S struct {
+			A int // Deprecated: use B.
+			B int
+		}
+	`)
+	got := Diff(from, to)
+	if diff := cmp.Diff([]string{"G", "S.A"}, got.Deprecated); diff != "" {
+		t.Errorf("Deprecated mismatch (-want, +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"F", "G", "S.A"}, DeprecatedSymbols(to)); diff != "" {
+		t.Errorf("DeprecatedSymbols mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import (
+	"go/ast"
+	"sort"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/godoc/internal/doc"
+)
+
+// Coverage returns the documentation coverage of the package, as computed by
+// the last call to Render. It returns nil if Render has not been called, or
+// if it did not render documentation.
+func (p *Package) Coverage() *internal.DocCoverage {
+	return p.coverage
+}
+
+// docCoverage computes the documentation coverage of d.
+func docCoverage(d *doc.Package) *internal.DocCoverage {
+	c := &internal.DocCoverage{
+		HasPackageDoc: d.Doc != "",
+		NumExamples:   len(d.Examples),
+	}
+	add := func(name string, documented bool) {
+		if !ast.IsExported(name) {
+			return
+		}
+		c.NumExported++
+		if documented {
+			c.NumDocumented++
+		} else {
+			c.Undocumented = append(c.Undocumented, name)
+		}
+	}
+	addValues := func(vs []*doc.Value) {
+		for _, v := range vs {
+			for _, spec := range v.Decl.Specs {
+				s := spec.(*ast.ValueSpec)
+				documented := v.Doc != "" || s.Doc != nil || s.Comment != nil
+				for _, n := range s.Names {
+					add(n.Name, documented)
+				}
+			}
+		}
+	}
+	addFuncs := func(fs []*doc.Func, prefix string) {
+		for _, f := range fs {
+			if f.Level > 0 {
+				// Promoted methods are counted with their own type.
+				continue
+			}
+			name := prefix + f.Name
+			if !ast.IsExported(f.Name) {
+				continue
+			}
+			add(name, f.Doc != "")
+			c.NumFuncs++
+			c.NumExamples += len(f.Examples)
+			if len(f.Examples) > 0 {
+				c.NumFuncsWithExamples++
+			}
+		}
+	}
+
+	addValues(d.Consts)
+	addValues(d.Vars)
+	addFuncs(d.Funcs, "")
+	for _, t := range d.Types {
+		add(t.Name, t.Doc != "")
+		c.NumExamples += len(t.Examples)
+		addValues(t.Consts)
+		addValues(t.Vars)
+		addFuncs(t.Funcs, "")
+		addFuncs(t.Methods, t.Name+".")
+	}
+	sort.Strings(c.Undocumented)
+	return c
+}
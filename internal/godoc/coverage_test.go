@@ -0,0 +1,101 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+)
+
+func TestDocCoverage(t *testing.T) {
+	files := map[string]string{
+		"p.go": `
+package p
+
+// C is a constant.
+const C = 1
+
+const (
+	// D is documented.
+	D = 2
+	E = 3
+	f = 4
+)
+
+var V int
+
+// NewClient returns a new Client.
+func NewClient() *Client { return nil }
+
+func Run() {}
+
+// A Client is a client.
+type Client struct{ Inner }
+
+// Do does it.
+func (c *Client) Do() {}
+
+func (c *Client) Close() {}
+
+type Inner struct{}
+
+// Embedded is promoted to Client.
+func (Inner) Embedded() {}
+
+type t int
+
+func (t) M() {}
+`,
+		"example_test.go": `
+package p_test
+
+func Example() {}
+
+func ExampleRun() {}
+
+func ExampleRun_second() {}
+
+func ExampleClient_Do() {}
+
+func ExampleClient() {}
+`,
+	}
+	fset := token.NewFileSet()
+	p := NewPackage(fset, "linux", "amd64", nil)
+	for name, src := range files {
+		f, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.AddFile(f, true)
+	}
+	d, err := p.docPackage("", &ModuleInfo{ModulePath: "example.com/p", ResolvedVersion: "v1.0.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := docCoverage(d)
+	want := &internal.DocCoverage{
+		HasPackageDoc:        false,
+		NumExported:          11,
+		NumDocumented:        6,
+		Undocumented:         []string{"Client.Close", "E", "Inner", "Run", "V"},
+		NumFuncs:             5,
+		NumFuncsWithExamples: 2,
+		NumExamples:          5,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+	if got, want := got.Percent(), 54; got != want {
+		t.Errorf("Percent() = %d, want %d", got, want)
+	}
+	if got, want := got.ExamplePercent(), 40; got != want {
+		t.Errorf("ExamplePercent() = %d, want %d", got, want)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"go/ast"
 	"go/printer"
 	"go/token"
+	"go/types"
 	"sort"
 	"strings"
 
@@ -43,6 +44,15 @@ type ModuleInfo struct {
 	ResolvedVersion string
 	// ModulePackages is the set of all full package paths in the module.
 	ModulePackages map[string]bool
+	// SinceVersions optionally maps the names of exported symbols, with
+	// methods and fields qualified by their type name as in "T.M", to the
+	// version of the module in which they were added. The version is shown
+	// next to the symbol's declaration.
+	SinceVersions map[string]string
+	// PackageURL optionally returns the URL of the documentation of the
+	// package with the given import path. If it is nil, packages link to
+	// "/<path>", at ResolvedVersion for packages of the module.
+	PackageURL func(importPath string) string
 }
 
 // RenderOptions are options for Render.
@@ -58,7 +68,11 @@ type RenderOptions struct {
 	// ModInfo optionally specifies information about the module the package
 	// belongs to in order to render module-related documentation.
 	ModInfo *ModuleInfo
-	Limit   int64 // If zero, a default limit of 10 megabytes is used.
+	// TypeInfo optionally holds the uses and selections of the type-checked
+	// package, for linking identifiers in declarations to the objects they
+	// denote.
+	TypeInfo *types.Info
+	Limit    int64 // If zero, a default limit of 10 megabytes is used.
 }
 
 // templateData holds the data passed to the HTML templates in this package.
@@ -182,8 +196,17 @@ func renderInfo(ctx context.Context, fset *token.FileSet, p *doc.Package, opt Re
 		delete(p.Notes, k)
 	}
 
+	sinceVersion := func(name string) string {
+		if opt.ModInfo == nil {
+			return ""
+		}
+		return opt.ModInfo.SinceVersions[name]
+	}
 	r := render.New(ctx, fset, p, &render.Options{
 		PackageURL: func(path string) string {
+			if opt.ModInfo != nil && opt.ModInfo.PackageURL != nil {
+				return opt.ModInfo.PackageURL(path)
+			}
 			// Use the same module version for imported packages that belong to
 			// the same module.
 			versionedPath := path
@@ -193,6 +216,8 @@ func renderInfo(ctx context.Context, fset *token.FileSet, p *doc.Package, opt Re
 			return "/" + versionedPath
 		},
 		DisableHotlinking: true,
+		SinceVersion:      sinceVersion,
+		TypeInfo:          opt.TypeInfo,
 	})
 
 	fileLink := func(name string) safehtml.HTML {
@@ -201,6 +226,13 @@ func renderInfo(ctx context.Context, fset *token.FileSet, p *doc.Package, opt Re
 	sourceLink := func(name string, node ast.Node) safehtml.HTML {
 		return linkHTML(name, opt.SourceLinkFunc(node), "Documentation-source")
 	}
+	sinceVersionHTML := func(name string) safehtml.HTML {
+		v := sinceVersion(name)
+		if v == "" {
+			return safehtml.HTML{}
+		}
+		return render.ExecuteToHTML(render.SinceVersionTemplate, v)
+	}
 	funcs := map[string]interface{}{
 		"render_short_synopsis":    r.ShortSynopsis,
 		"render_synopsis":          r.Synopsis,
@@ -210,6 +242,7 @@ func renderInfo(ctx context.Context, fset *token.FileSet, p *doc.Package, opt Re
 		"render_code":              r.CodeHTML,
 		"file_link":                fileLink,
 		"source_link":              sourceLink,
+		"since_version":            sinceVersionHTML,
 	}
 	data := templateData{
 		RootURL:     "/pkg",
@@ -220,6 +253,22 @@ func renderInfo(ctx context.Context, fset *token.FileSet, p *doc.Package, opt Re
 	return funcs, data, r.Links
 }
 
+// IsDeprecated reports whether doc, the text of a doc comment, has a
+// paragraph that begins with "Deprecated:", which marks the documented symbol
+// as deprecated. Deprecated symbols are collapsed in the documentation HTML.
+func IsDeprecated(doc string) bool {
+	return render.IsDeprecated(doc)
+}
+
+// deprecatedTag returns the tag shown next to a symbol with the doc comment
+// doc if the symbol is deprecated, and empty HTML otherwise.
+func deprecatedTag(doc string) safehtml.HTML {
+	if !render.IsDeprecated(doc) {
+		return safehtml.HTML{}
+	}
+	return render.ExecuteToHTML(render.DeprecatedTemplate, nil)
+}
+
 // executeToHTMLWithLimit executes tmpl on data and returns the result as a safehtml.HTML.
 // It returns an error if the size of the result exceeds limit.
 func executeToHTMLWithLimit(tmpl *template.Template, data interface{}, limit int64) (safehtml.HTML, error) {
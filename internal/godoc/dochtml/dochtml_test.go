@@ -7,6 +7,7 @@ package dochtml
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
@@ -85,6 +86,125 @@ func TestRender(t *testing.T) {
 	}
 }
 
+func TestRenderSinceVersions(t *testing.T) {
+	LoadTemplates(templateSource)
+	fset, d := mustLoadPackage("everydecl")
+
+	rawDoc, err := Render(context.Background(), fset, d, RenderOptions{
+		FileLinkFunc:   func(string) string { return "file" },
+		SourceLinkFunc: func(ast.Node) string { return "src" },
+		ModInfo: &ModuleInfo{
+			SinceVersions: map[string]string{"F": "v1.1.0", "T.M": "v1.2.0", "S1.F": "v1.3.0"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	htmlDoc, err := html.Parse(strings.NewReader(rawDoc.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		id, version string
+	}{
+		{"F", "v1.1.0"},
+		{"T.M", "v1.2.0"},
+		{"S1.F", "v1.3.0"},
+	} {
+		checker := in(fmt.Sprintf("[id=%q]", c.id), in(".Documentation-sinceVersion", hasExactText("added in "+c.version)))
+		if err := checker(htmlDoc); err != nil {
+			t.Errorf("%s: %v", c.id, err)
+		}
+	}
+}
+
+func TestRenderDeprecated(t *testing.T) {
+	LoadTemplates(templateSource)
+	fset, d := mustLoadPackage("deprecated")
+
+	parts, err := RenderParts(context.Background(), fset, d, RenderOptions{
+		FileLinkFunc:   func(string) string { return "file" },
+		SourceLinkFunc: func(ast.Node) string { return "src" },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bodyDoc, err := html.Parse(strings.NewReader(parts.Body.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sidenavDoc, err := html.Parse(strings.NewReader(parts.Outline.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Deprecated symbols are collapsed, with a tag in their header.
+	for _, id := range []string{"F", "T", "U.M"} {
+		checker := in(fmt.Sprintf(".js-deprecatedDetails > summary > [id=%q]", id),
+			in(".Documentation-deprecatedTag", hasExactText("deprecated")))
+		if err := checker(bodyDoc); err != nil {
+			t.Errorf("%s: %v", id, err)
+		}
+	}
+	checker := in(".Documentation-constants",
+		in(".js-deprecatedDetails", in("summary", hasExactText("Olddeprecated"))))
+	if err := checker(bodyDoc); err != nil {
+		t.Errorf("Old: %v", err)
+	}
+	// Deprecated fields are tagged in their declaration.
+	checker = in(`[id="U.B"]`, in(".Documentation-deprecatedTag"))
+	if err := checker(bodyDoc); err != nil {
+		t.Errorf("U.B: %v", err)
+	}
+	// Other symbols are neither collapsed nor tagged.
+	for _, id := range []string{"G", "U", "U.N", "U.A"} {
+		checker := in(fmt.Sprintf("[id=%q]", id), htmlcheck.NotIn(".Documentation-deprecatedTag"))
+		if err := checker(bodyDoc); err != nil {
+			t.Errorf("%s: %v", id, err)
+		}
+	}
+	if got, want := strings.Count(parts.Body.String(), "js-deprecatedDetails"), 4; got != want {
+		t.Errorf("got %d collapsed symbols, want %d", got, want)
+	}
+
+	// Deprecated symbols are tagged in the index and the sidebar.
+	checker = in(".Documentation-indexFunction", in(".Documentation-deprecatedTag"))
+	if err := checker(bodyDoc); err != nil {
+		t.Errorf("index: %v", err)
+	}
+	checker = in("#nav-group-functions", in(`a[href="#F"]`, in(".Documentation-deprecatedTag")))
+	if err := checker(sidenavDoc); err != nil {
+		t.Errorf("sidebar: %v", err)
+	}
+	checker = in("#nav-group-types", in(`a[href="#U.M"]`, in(".Documentation-deprecatedTag")))
+	if err := checker(sidenavDoc); err != nil {
+		t.Errorf("sidebar: %v", err)
+	}
+}
+
+func TestIsDeprecated(t *testing.T) {
+	_, d := mustLoadPackage("deprecated")
+	var got []string
+	for _, f := range d.Funcs {
+		if IsDeprecated(f.Doc) {
+			got = append(got, f.Name)
+		}
+	}
+	for _, typ := range d.Types {
+		if IsDeprecated(typ.Doc) {
+			got = append(got, typ.Name)
+		}
+		for _, m := range typ.Methods {
+			if IsDeprecated(m.Doc) {
+				got = append(got, typ.Name+"."+m.Name)
+			}
+		}
+	}
+	if want := []string{"F", "T", "U.M"}; !cmp.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
 func TestRenderParts(t *testing.T) {
 	LoadTemplates(templateSource)
 	fset, d := mustLoadPackage("everydecl")
@@ -251,6 +371,7 @@ func main() {
 </div>
 <div class="Documentation-exampleButtonsContainer">
         <p class="Documentation-exampleError" role="alert" aria-atomic="true"></p>
+        <button class="Documentation-exampleRunButton" aria-label="Run Code">Run</button>
         <button class="Documentation-examplePlayButton" aria-label="Play Code">Play</button>
       </div></details>`,
 		},
@@ -0,0 +1,83 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"go/ast"
+	"strings"
+
+	"github.com/google/safehtml/template"
+)
+
+// DeprecatedTemplate renders the tag shown next to deprecated symbols.
+var DeprecatedTemplate = template.Must(template.New("deprecated").Parse(`<span class="Documentation-deprecatedTag">deprecated</span>`))
+
+// IsDeprecated reports whether doc, the text of a doc comment, has a
+// paragraph that begins with "Deprecated:", which by convention marks the
+// documented symbol as deprecated.
+func IsDeprecated(doc string) bool {
+	for _, blk := range docToBlocks(doc) {
+		if p, ok := blk.(*paragraph); ok && strings.HasPrefix(p.lines[0], "Deprecated:") {
+			return true
+		}
+	}
+	return false
+}
+
+// isDeprecatedComment is like IsDeprecated, for a comment in the source.
+func isDeprecatedComment(cgs ...*ast.CommentGroup) bool {
+	for _, cg := range cgs {
+		if cg != nil && IsDeprecated(cg.Text()) {
+			return true
+		}
+	}
+	return false
+}
+
+// deprecatedIdents returns the identifiers of the specs in a parenthesized
+// declaration, and of the fields and methods in a type declaration, whose
+// own comments mark them as deprecated. The doc comment of decl as a whole
+// is not considered.
+func deprecatedIdents(decl ast.Decl) map[*ast.Ident]bool {
+	m := map[*ast.Ident]bool{}
+	gd, ok := decl.(*ast.GenDecl)
+	if !ok {
+		return m
+	}
+	for _, spec := range gd.Specs {
+		switch s := spec.(type) {
+		case *ast.ValueSpec:
+			if gd.Lparen.IsValid() && isDeprecatedComment(s.Doc, s.Comment) {
+				for _, n := range s.Names {
+					m[n] = true
+				}
+			}
+		case *ast.TypeSpec:
+			var fs []*ast.Field
+			switch t := s.Type.(type) {
+			case *ast.StructType:
+				fs = t.Fields.List
+			case *ast.InterfaceType:
+				fs = t.Methods.List
+			}
+			for _, f := range fs {
+				if !isDeprecatedComment(f.Doc, f.Comment) {
+					continue
+				}
+				for _, n := range f.Names {
+					m[n] = true
+				}
+				if f.Names == nil {
+					// The anchor of an embedded field is on the
+					// identifier of its type.
+					if _, id := nodeName(f.Type); id != nil {
+						m[id] = true
+					}
+				}
+			}
+		}
+	}
+	return m
+}
@@ -0,0 +1,81 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"context"
+	"go/ast"
+	"go/token"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/godoc/internal/doc"
+)
+
+func TestIsDeprecated(t *testing.T) {
+	for _, test := range []struct {
+		doc  string
+		want bool
+	}{
+		{"", false},
+		{"F does things.", false},
+		{"F does things.\n\nDeprecated: use G instead.\n", true},
+		{"Deprecated: use G instead.", true},
+		{"Deprecated:\nuse G instead.", true},
+		{"F does things.\nDeprecated: not a paragraph of its own.", false},
+		{"F does things.\n\n\tDeprecated: in code.\n", false},
+		{"F is not deprecated.\n\nThe Deprecated: prefix is a convention.", false},
+	} {
+		if got := IsDeprecated(test.doc); got != test.want {
+			t.Errorf("IsDeprecated(%q) = %t, want %t", test.doc, got, test.want)
+		}
+	}
+}
+
+func TestDeclHTMLDeprecated(t *testing.T) {
+	const src = `package p
+
+const (
+	A = 1
+	B = 2 // Deprecated: use A.
+)
+
+type T struct {
+	F int
+	G int // Deprecated: use F.
+}
+`
+	fset := token.NewFileSet()
+	file := mustParse(t, fset, "p.go", src)
+	astPkg, _ := ast.NewPackage(fset, map[string]*ast.File{"p.go": file}, nil, nil)
+	pkg := doc.New(astPkg, "p", 0)
+	r := New(context.Background(), fset, pkg, nil)
+	for _, test := range []struct {
+		symbol string
+		want   string
+	}{
+		{
+			symbol: "A",
+			want: `const (
+<span id="A" data-kind="constant">	A = 1
+</span><span id="B" data-kind="constant">	B = 2 <span class="comment">// Deprecated: use <a href="#A">A</a>.</span><span class="Documentation-deprecatedTag">deprecated</span>
+</span>)`,
+		},
+		{
+			symbol: "T",
+			want: `type T struct {
+<span id="T.F" data-kind="field">	F <a href="/builtin#int">int</a>
+</span><span id="T.G" data-kind="field">	G <a href="/builtin#int">int</a> <span class="comment">// Deprecated: use <a href="#T.F">F</a>.</span><span class="Documentation-deprecatedTag">deprecated</span>
+</span>}`,
+		},
+	} {
+		t.Run(test.symbol, func(t *testing.T) {
+			got := r.DeclHTML("", declForName(t, pkg, test.symbol)).Decl.String()
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
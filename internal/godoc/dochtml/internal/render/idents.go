@@ -7,6 +7,7 @@ package render
 import (
 	"go/ast"
 	"go/token"
+	"go/types"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -70,6 +71,11 @@ type packageIDs struct {
 	// E.g., "json"
 	name string
 
+	// path is the import path of the package being rendered.
+	//
+	// E.g., "encoding/json"
+	path string
+
 	// impPaths maps package names to their import paths.
 	//
 	// E.g., impPaths["json"] == "encoding/json"
@@ -90,6 +96,7 @@ type packageIDs struct {
 func newPackageIDs(pkg *doc.Package, related ...*doc.Package) *packageIDs {
 	pids := &packageIDs{
 		name:          pkg.Name,
+		path:          pkg.ImportPath,
 		impPaths:      make(map[string]string),
 		pkgIDs:        make(map[string]map[string]bool),
 		topLevelDecls: make(map[interface{}]bool),
@@ -195,6 +202,10 @@ type identifierResolver struct {
 	//
 	// E.g., packageURL("builtin") == "/pkg/builtin/index.html"
 	packageURL func(string) string
+
+	// typeInfo holds the uses and selections of the type-checked package,
+	// if it was type-checked.
+	typeInfo *types.Info
 }
 
 // toURL returns a URL to locate the given package, and
@@ -95,7 +95,7 @@ func TestResolveIdentifier(t *testing.T) {
 			for _, dt := range pt.tests {
 				dids := newDeclIDs(findDecl(pt.pkg, dt.name))
 				t.Run(dt.name, func(t *testing.T) {
-					idr := &identifierResolver{pids, dids, nil}
+					idr := &identifierResolver{pids, dids, nil, nil}
 					for i, rt := range dt.tests {
 						got := idr.toHTML(rt.in)
 						if got.String() != rt.want {
@@ -13,6 +13,7 @@ import (
 	"go/printer"
 	"go/scanner"
 	"go/token"
+	"go/types"
 	"regexp"
 	"strconv"
 	"strings"
@@ -31,8 +32,6 @@ into formatted HTML. This relies on identifierResolver.toHTML to do the work
 of converting words into links.
 */
 
-// TODO(golang.org/issue/17056): Support hiding deprecated declarations.
-
 const (
 	// Regexp for URLs.
 	// Match any ".,:;?!" within path, but not at end (see #18139, #16565).
@@ -77,7 +76,7 @@ type docElement struct {
 
 func (r *Renderer) declHTML(doc string, decl ast.Decl, extractLinks bool) (out struct{ Doc, Decl safehtml.HTML }) {
 	dids := newDeclIDs(decl)
-	idr := &identifierResolver{r.pids, dids, r.packageURL}
+	idr := &identifierResolver{r.pids, dids, r.packageURL, r.typeInfo}
 	if doc != "" {
 		var els []docElement
 		inLinks := false
@@ -361,17 +360,21 @@ func (r *Renderer) formatDeclHTML(decl ast.Decl, idr *identifierResolver) safeht
 	// Generate all anchor points and links for the given decl.
 	anchorPointsMap := generateAnchorPoints(decl)
 	anchorLinksMap := generateAnchorLinks(idr, decl)
+	deprecatedMap := deprecatedIdents(decl)
 
-	// Convert the maps (keyed by *ast.Ident) to slices of idKinds or URLs.
+	// Convert the maps (keyed by *ast.Ident) to slices of idKinds, URLs or
+	// deprecation marks.
 	//
 	// This relies on the ast.Inspect and scanner.Scanner both
 	// visiting *ast.Ident and token.IDENT nodes in the same order.
 	var anchorPoints []idKind
 	var anchorLinks []string
+	var deprecated []bool
 	ast.Inspect(decl, func(node ast.Node) bool {
 		if id, ok := node.(*ast.Ident); ok {
 			anchorPoints = append(anchorPoints, anchorPointsMap[id])
 			anchorLinks = append(anchorLinks, anchorLinksMap[id])
+			deprecated = append(deprecated, deprecatedMap[id])
 		}
 		return true
 	})
@@ -397,6 +400,14 @@ func (r *Renderer) formatDeclHTML(decl ast.Decl, idr *identifierResolver) safeht
 	anchorLines := make([][]idKind, numLines)
 	lineTypes := make([]lineType, numLines)
 	htmlLines := make([][]safehtml.HTML, numLines)
+	// lineEnds reports whether each line ends in a newline, which is kept
+	// out of htmlLines so that a version can be placed before it.
+	// sinceLines is the version to show at the end of each line, if any.
+	// deprecatedLines reports whether each line declares a deprecated field
+	// or constant, which is tagged at the end of the line.
+	lineEnds := make([]bool, numLines)
+	sinceLines := make([]string, numLines)
+	deprecatedLines := make([]bool, numLines)
 
 	// Scan through the source code, appropriately annotating it with HTML spans
 	// for comments, and HTML links and anchors for relevant identifiers.
@@ -418,6 +429,10 @@ scan:
 			if n < 0 { // possible at EOF
 				n = 0
 			}
+			if strings.HasSuffix(ln, "\n") {
+				ln = strings.TrimSuffix(ln, "\n")
+				lineEnds[n] = true
+			}
 			htmlLines[n] = append(htmlLines[n], safehtml.HTMLEscaped(ln))
 		}
 
@@ -435,6 +450,12 @@ scan:
 		case token.IDENT:
 			if idIdx < len(anchorPoints) && anchorPoints[idIdx].ID.String() != "" {
 				anchorLines[line] = append(anchorLines[line], anchorPoints[idIdx])
+				if sinceLines[line] == "" && r.sinceVersion != nil && emitsAnchor(decl, anchorPoints[idIdx]) {
+					sinceLines[line] = r.sinceVersion(anchorPoints[idIdx].ID.String())
+				}
+				if deprecated[idIdx] && emitsAnchor(decl, anchorPoints[idIdx]) {
+					deprecatedLines[line] = true
+				}
 			}
 			if idIdx < len(anchorLinks) && anchorLinks[idIdx] != "" {
 				htmlLines[line] = append(htmlLines[line], ExecuteToHTML(LinkTemplate, Link{Href: anchorLinks[idIdx], Text: lit}))
@@ -464,20 +485,22 @@ scan:
 	for line, iks := range anchorLines {
 		inAnchor := false
 		for _, ik := range iks {
-			// Attributes for types and functions are handled in the template
-			// that generates the full documentation HTML.
-			if ik.Kind == "function" || ik.Kind == "type" {
-				continue
-			}
-			// Top-level methods are handled in the template, but interface methods
-			// are handled here.
-			if fd, ok := decl.(*ast.FuncDecl); ok && fd.Recv != nil {
+			if !emitsAnchor(decl, ik) {
 				continue
 			}
 			htmls = append(htmls, ExecuteToHTML(anchorTemplate, ik))
 			inAnchor = true
 		}
 		htmls = append(htmls, htmlLines[line]...)
+		if v := sinceLines[line]; v != "" {
+			htmls = append(htmls, ExecuteToHTML(SinceVersionTemplate, v))
+		}
+		if deprecatedLines[line] {
+			htmls = append(htmls, ExecuteToHTML(DeprecatedTemplate, nil))
+		}
+		if lineEnds[line] {
+			htmls = append(htmls, safehtml.HTMLEscaped("\n"))
+		}
 		if inAnchor {
 			htmls = append(htmls, template.MustParseAndExecuteToHTML("</span>"))
 		}
@@ -487,6 +510,25 @@ scan:
 
 var anchorTemplate = template.Must(template.New("anchor").Parse(`<span id="{{.ID}}" data-kind="{{.Kind}}">`))
 
+// SinceVersionTemplate renders the version in which a symbol was added.
+// It expects a string.
+var SinceVersionTemplate = template.Must(template.New("since").Parse(`<span class="Documentation-sinceVersion">added in {{.}}</span>`))
+
+// emitsAnchor reports whether formatDeclHTML emits the anchor ik for decl.
+func emitsAnchor(decl ast.Decl, ik idKind) bool {
+	// Attributes for types and functions are handled in the template
+	// that generates the full documentation HTML.
+	if ik.Kind == "function" || ik.Kind == "type" {
+		return false
+	}
+	// Top-level methods are handled in the template, but interface methods
+	// are handled here.
+	if fd, ok := decl.(*ast.FuncDecl); ok && fd.Recv != nil {
+		return false
+	}
+	return true
+}
+
 // declVisitor is an ast.Visitor that trims
 // large string literals and composite literals.
 type declVisitor struct {
@@ -704,5 +746,129 @@ func generateAnchorLinks(idr *identifierResolver, decl ast.Decl) map[*ast.Ident]
 		}
 		return true
 	})
+	if idr.typeInfo != nil {
+		addTypedAnchorLinks(m, idr, decl)
+	}
 	return m
 }
+
+// addTypedAnchorLinks replaces the links in m of the identifiers in decl that
+// were resolved by type checking with links to the objects they denote.
+// Resolved identifiers whose objects are not documented are not linked.
+// The links of unresolved identifiers are left alone.
+func addTypedAnchorLinks(m map[*ast.Ident]string, idr *identifierResolver, decl ast.Decl) {
+	done := map[*ast.Ident]bool{}
+	ast.Inspect(decl, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.SelectorExpr:
+			// Field and method selectors, whose documentation depends on the
+			// type they are selected from (e.g., "T{}.Field").
+			if sel := idr.typeInfo.Selections[node]; sel != nil {
+				m[node.Sel] = idr.selectionURL(sel)
+				done[node.Sel] = true
+			}
+		case *ast.Ident:
+			if done[node] {
+				return true
+			}
+			if obj := idr.typeInfo.Uses[node]; obj != nil {
+				m[node] = idr.objectURL(obj)
+			}
+		}
+		return true
+	})
+}
+
+// objectURL returns the URL of the documentation of obj, or the empty string
+// if it has none. Fields and methods are only linked through selections; see
+// selectionURL.
+func (r identifierResolver) objectURL(obj types.Object) string {
+	if pn, ok := obj.(*types.PkgName); ok {
+		return r.toURL(pn.Imported().Path(), "")
+	}
+	if obj.Pkg() == nil {
+		if obj.Parent() == types.Universe {
+			return r.toURL("builtin", obj.Name()) // E.g., "error"
+		}
+		return "" // E.g., the Error method of error
+	}
+	if obj.Parent() != obj.Pkg().Scope() {
+		return "" // E.g., a field, a method or a parameter
+	}
+	return r.anchorURL(obj.Pkg().Path(), obj.Name())
+}
+
+// selectionURL returns the URL of the documentation of the field or method
+// selected by sel, or the empty string if it has none.
+func (r identifierResolver) selectionURL(sel *types.Selection) string {
+	var owner *types.Named
+	switch sel.Kind() {
+	case types.FieldVal:
+		owner = fieldOwner(sel.Recv(), sel.Index())
+	case types.MethodVal, types.MethodExpr:
+		recv := sel.Obj().Type().(*types.Signature).Recv()
+		if recv != nil {
+			owner = namedType(recv.Type())
+		}
+		// Methods promoted from unexported embedded types are documented
+		// with the type they are promoted to.
+		if owner == nil || !owner.Obj().Exported() {
+			owner = namedType(sel.Recv())
+		}
+	}
+	if owner == nil || owner.Obj().Pkg() == nil {
+		return ""
+	}
+	return r.anchorURL(owner.Obj().Pkg().Path(), owner.Obj().Name()+"."+sel.Obj().Name())
+}
+
+// anchorURL returns the URL of the anchor id in the documentation of the
+// package with the given path, or the empty string if there is no such
+// anchor. Only the anchors of the package being rendered are known, so
+// anchors in other packages are assumed to exist if they are exported.
+func (r identifierResolver) anchorURL(pkgPath, id string) string {
+	if pkgPath == r.path {
+		if !r.pkgIDs[r.name][id] {
+			return ""
+		}
+		return "#" + id
+	}
+	for _, s := range strings.Split(id, ".") {
+		if !isExported(s) {
+			return ""
+		}
+	}
+	return r.toURL(pkgPath, id)
+}
+
+// fieldOwner returns the named struct type that declares the field selected
+// from recv by the sequence of field indexes index, or nil if that struct is
+// not a named type.
+func fieldOwner(recv types.Type, index []int) *types.Named {
+	t := recv
+	for i, x := range index {
+		if p, ok := t.(*types.Pointer); ok {
+			t = p.Elem()
+		}
+		st, ok := t.Underlying().(*types.Struct)
+		if !ok {
+			return nil
+		}
+		if i == len(index)-1 {
+			n, _ := t.(*types.Named)
+			return n
+		}
+		t = st.Field(x).Type()
+	}
+	return nil
+}
+
+// namedType returns the named type of t or, if t is a pointer, of its
+// element type. It returns nil if that type is not named.
+func namedType(t types.Type) *types.Named {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	n, _ := t.(*types.Named)
+	return n
+}
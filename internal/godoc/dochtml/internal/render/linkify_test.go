@@ -257,6 +257,48 @@ func TestDeclHTML(t *testing.T) {
 	}
 }
 
+func TestDeclHTMLSinceVersion(t *testing.T) {
+	r := New(context.Background(), fsetTime, pkgTime, &Options{
+		SinceVersion: func(name string) string {
+			return map[string]string{
+				"Ticker":   "go1.1",
+				"Ticker.C": "go1.2",
+				"Iface.M":  "go1.3",
+			}[name]
+		},
+	})
+	for _, test := range []struct {
+		symbol string
+		want   string
+	}{
+		{
+			symbol: "Ticker",
+			want: `type Ticker struct {
+<span id="Ticker.C" data-kind="field">	C &lt;-chan <a href="#Time">Time</a> <span class="comment">// The channel on which the ticks are delivered.</span><span class="Documentation-sinceVersion">added in go1.2</span>
+</span>	<span class="comment">// contains filtered or unexported fields</span>
+
+}`,
+		},
+		{
+			symbol: "Iface",
+			want: `type Iface interface {
+<span id="Iface.M" data-kind="method">	<span class="comment">// Method comment.</span>
+</span>	M()<span class="Documentation-sinceVersion">added in go1.3</span>
+
+	<span class="comment">// contains filtered or unexported methods</span>
+
+}`,
+		},
+	} {
+		t.Run(test.symbol, func(t *testing.T) {
+			got := r.DeclHTML("", declForName(t, pkgTime, test.symbol)).Decl.String()
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
 func declForName(t *testing.T, pkg *doc.Package, symbol string) ast.Decl {
 
 	inVals := func(vals []*doc.Value) ast.Decl {
@@ -10,6 +10,7 @@ import (
 	"context"
 	"go/ast"
 	"go/token"
+	"go/types"
 	"regexp"
 	"strings"
 
@@ -36,6 +37,8 @@ type Renderer struct {
 	packageURL        func(string) string
 	disableHotlinking bool
 	disablePermalinks bool
+	sinceVersion      func(string) string
+	typeInfo          *types.Info
 	ctx               context.Context
 	docTmpl           *template.Template
 	exampleTmpl       *template.Template
@@ -65,6 +68,22 @@ type Options struct {
 	//
 	// Only relevant for HTML formatting.
 	DisablePermalinks bool
+
+	// SinceVersion optionally returns the version in which the exported
+	// symbol with the given name, qualified as in anchor IDs, was added.
+	// If it returns a non-empty version, that version is shown next to
+	// constants, variables, fields and interface methods in declarations.
+	//
+	// Only relevant for HTML formatting.
+	SinceVersion func(name string) (version string)
+
+	// TypeInfo optionally holds the uses and selections of the type-checked
+	// package. Identifiers in declarations that it resolves are linked to
+	// the documentation of the objects they denote; others are linked
+	// heuristically.
+	//
+	// Only relevant for HTML formatting.
+	TypeInfo *types.Info
 }
 
 // docDataTmpl renders documentation. It expects a docData.
@@ -99,6 +118,8 @@ func New(ctx context.Context, fset *token.FileSet, pkg *doc.Package, opts *Optio
 	var packageURL func(string) string
 	var disableHotlinking bool
 	var disablePermalinks bool
+	var sinceVersion func(string) string
+	var typeInfo *types.Info
 	if opts != nil {
 		if len(opts.RelatedPackages) > 0 {
 			others = opts.RelatedPackages
@@ -108,6 +129,8 @@ func New(ctx context.Context, fset *token.FileSet, pkg *doc.Package, opts *Optio
 		}
 		disableHotlinking = opts.DisableHotlinking
 		disablePermalinks = opts.DisablePermalinks
+		sinceVersion = opts.SinceVersion
+		typeInfo = opts.TypeInfo
 	}
 	pids := newPackageIDs(pkg, others...)
 
@@ -117,6 +140,8 @@ func New(ctx context.Context, fset *token.FileSet, pkg *doc.Package, opts *Optio
 		packageURL:        packageURL,
 		disableHotlinking: disableHotlinking,
 		disablePermalinks: disablePermalinks,
+		sinceVersion:      sinceVersion,
+		typeInfo:          typeInfo,
 		docTmpl:           docDataTmpl,
 		exampleTmpl:       exampleTmpl,
 	}
@@ -0,0 +1,41 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package deprecated has deprecated symbols.
+package deprecated
+
+// Old is an old constant.
+//
+// Deprecated: use New.
+const Old = 1
+
+// New is a new constant.
+const New = 2
+
+// F is an old function.
+//
+// Deprecated: use G.
+func F() {}
+
+// G is a function.
+func G() {}
+
+// T is an old type.
+//
+// Deprecated: use U.
+type T int
+
+// U is a type.
+type U struct {
+	A int
+	B int // Deprecated: use A.
+}
+
+// M is an old method.
+//
+// Deprecated: use N.
+func (U) M() {}
+
+// N is a method.
+func (U) N() {}
@@ -0,0 +1,335 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package doctext renders Go package documentation as plain text, for
+// display in a terminal. It shows the same declarations and examples as the
+// HTML documentation rendered by package dochtml.
+package doctext
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	stddoc "go/doc"
+	"go/format"
+	"go/printer"
+	"go/token"
+	"io"
+	"strings"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/godoc/internal/doc"
+)
+
+// Options are options for Render.
+type Options struct {
+	// Symbol optionally selects the documentation of a single symbol: the name
+	// of a constant, variable, function or type, or of a method in the form
+	// "Type.Method". The documentation of a type includes its associated
+	// constants, variables, functions and methods.
+	Symbol string
+	// Styled enables ANSI escape sequences that highlight headings and
+	// declarations.
+	Styled bool
+	// Width is the maximum width of paragraphs of doc comments. If zero, 80
+	// is used.
+	Width int
+}
+
+const (
+	indent    = "    "
+	ansiBold  = "\x1b[1m"
+	ansiReset = "\x1b[0m"
+)
+
+// Render writes the documentation of the package p, or of the symbol selected
+// by opt.Symbol, as text to w. If there is no such symbol, the error wraps
+// derrors.NotFound.
+func Render(ctx context.Context, w io.Writer, fset *token.FileSet, p *doc.Package, opt Options) (err error) {
+	defer derrors.Wrap(&err, "doctext.Render(%q, %q)", p.ImportPath, opt.Symbol)
+
+	if opt.Width == 0 {
+		opt.Width = 80
+	}
+	r := &renderer{fset: fset, opt: opt}
+	// As in the HTML documentation, commands show only their package
+	// comment and notes.
+	if p.Name == "main" {
+		if opt.Symbol != "" {
+			return fmt.Errorf("command has no symbols: %w", derrors.NotFound)
+		}
+		r.header(p)
+		r.notes(p)
+	} else if opt.Symbol == "" {
+		r.header(p)
+		r.examples(p.Examples)
+		r.values("CONSTANTS", p.Consts)
+		r.values("VARIABLES", p.Vars)
+		if len(p.Funcs) > 0 {
+			r.heading("FUNCTIONS")
+			for _, f := range p.Funcs {
+				r.function(f)
+			}
+		}
+		if len(p.Types) > 0 {
+			r.heading("TYPES")
+			for _, t := range p.Types {
+				r.typ(t)
+			}
+		}
+		r.notes(p)
+	} else if !r.symbol(p, opt.Symbol) {
+		return fmt.Errorf("no symbol %q in package %s: %w", opt.Symbol, p.ImportPath, derrors.NotFound)
+	}
+	if r.err != nil {
+		return r.err
+	}
+	_, err = w.Write(r.buf.Bytes())
+	return err
+}
+
+// A renderer accumulates the text of documentation.
+type renderer struct {
+	fset *token.FileSet
+	opt  Options
+	buf  bytes.Buffer
+	err  error // first error encountered
+}
+
+func (r *renderer) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&r.buf, format, args...)
+}
+
+// styled returns s, highlighted if styling is enabled.
+func (r *renderer) styled(s string) string {
+	if !r.opt.Styled {
+		return s
+	}
+	return ansiBold + s + ansiReset
+}
+
+func (r *renderer) header(p *doc.Package) {
+	r.printf("%s\n\n", r.styled(fmt.Sprintf("package %s // import %q", p.Name, p.ImportPath)))
+	if p.Doc != "" {
+		r.doc(p.Doc, "")
+		r.printf("\n")
+	}
+}
+
+func (r *renderer) heading(h string) {
+	r.printf("%s\n\n", r.styled(h))
+}
+
+// doc writes the doc comment text, with each line prefixed by prefix.
+func (r *renderer) doc(text, prefix string) {
+	if text == "" {
+		return
+	}
+	stddoc.ToText(&r.buf, text, prefix, prefix+"\t", r.opt.Width-len(prefix))
+}
+
+// decl writes the declaration of a symbol.
+func (r *renderer) decl(decl ast.Decl) {
+	// The doc comment of the declaration is written separately.
+	switch d := decl.(type) {
+	case *ast.GenDecl:
+		d.Doc = nil
+	case *ast.FuncDecl:
+		d.Doc = nil
+	}
+	var buf bytes.Buffer
+	conf := &printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	if err := conf.Fprint(&buf, r.fset, decl); err != nil && r.err == nil {
+		r.err = err
+	}
+	r.printf("%s\n", r.styled(buf.String()))
+}
+
+func (r *renderer) values(heading string, vals []*doc.Value) {
+	if len(vals) == 0 {
+		return
+	}
+	if heading != "" {
+		r.heading(heading)
+	}
+	for _, v := range vals {
+		r.decl(v.Decl)
+		r.doc(v.Doc, indent)
+		r.printf("\n")
+	}
+}
+
+func (r *renderer) function(f *doc.Func) {
+	r.decl(f.Decl)
+	r.doc(f.Doc, indent)
+	r.printf("\n")
+	r.examples(f.Examples)
+}
+
+func (r *renderer) typ(t *doc.Type) {
+	r.decl(t.Decl)
+	r.doc(t.Doc, indent)
+	r.printf("\n")
+	r.examples(t.Examples)
+	r.values("", t.Consts)
+	r.values("", t.Vars)
+	for _, f := range t.Funcs {
+		r.function(f)
+	}
+	for _, m := range t.Methods {
+		r.function(m)
+	}
+}
+
+// symbol writes the documentation of the symbol with the given name, and
+// reports whether it was found.
+func (r *renderer) symbol(p *doc.Package, name string) bool {
+	if typeName, method, ok := splitMethod(name); ok {
+		for _, t := range p.Types {
+			if t.Name != typeName {
+				continue
+			}
+			for _, m := range t.Methods {
+				if m.Name == method {
+					r.function(m)
+					return true
+				}
+			}
+		}
+		return false
+	}
+	for _, vals := range [][]*doc.Value{p.Consts, p.Vars} {
+		for _, v := range vals {
+			if hasName(v, name) {
+				r.values("", []*doc.Value{v})
+				return true
+			}
+		}
+	}
+	for _, f := range p.Funcs {
+		if f.Name == name {
+			r.function(f)
+			return true
+		}
+	}
+	for _, t := range p.Types {
+		if t.Name == name {
+			r.typ(t)
+			return true
+		}
+		// Constants, variables and functions associated with a type are
+		// listed with it.
+		for _, vals := range [][]*doc.Value{t.Consts, t.Vars} {
+			for _, v := range vals {
+				if hasName(v, name) {
+					r.values("", []*doc.Value{v})
+					return true
+				}
+			}
+		}
+		for _, f := range t.Funcs {
+			if f.Name == name {
+				r.function(f)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// splitMethod splits a symbol of the form "Type.Method".
+func splitMethod(name string) (typeName, method string, ok bool) {
+	i := strings.IndexByte(name, '.')
+	if i < 0 {
+		return "", "", false
+	}
+	return name[:i], name[i+1:], true
+}
+
+func hasName(v *doc.Value, name string) bool {
+	for _, n := range v.Names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// examples writes examples, with their code and expected output.
+func (r *renderer) examples(exs []*doc.Example) {
+	for _, ex := range exs {
+		title := "Example"
+		if ex.Suffix != "" {
+			title += " (" + ex.Suffix + ")"
+		}
+		r.printf("%s%s:\n", indent, r.styled(title))
+		r.doc(ex.Doc, indent)
+		code, err := exampleCode(r.fset, ex)
+		if err != nil && r.err == nil {
+			r.err = err
+		}
+		r.printf("%s\n", indentLines(code, indent+indent))
+		if ex.Output != "" || ex.EmptyOutput {
+			r.printf("\n%sOutput:\n", indent)
+			r.printf("%s\n", indentLines(strings.TrimRight(ex.Output, "\n"), indent+indent))
+		}
+		r.printf("\n")
+	}
+}
+
+// exampleCode returns the code of an example: the whole program if it is
+// playable, and otherwise the body of the example function, without its
+// output comment.
+func exampleCode(fset *token.FileSet, ex *doc.Example) (string, error) {
+	var buf bytes.Buffer
+	if ex.Play != nil {
+		if err := format.Node(&buf, fset, ex.Play); err != nil {
+			return "", err
+		}
+		return strings.TrimRight(buf.String(), "\n"), nil
+	}
+	if err := format.Node(&buf, fset, &printer.CommentedNode{Node: ex.Code, Comments: ex.Comments}); err != nil {
+		return "", err
+	}
+	code := buf.String()
+	// Remove the braces of the function body, and its indentation.
+	if strings.HasPrefix(code, "{\n") && strings.HasSuffix(code, "\n}") {
+		code = code[2 : len(code)-2]
+		code = strings.ReplaceAll(strings.TrimPrefix(code, "\t"), "\n\t", "\n")
+	}
+	lines := strings.Split(code, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if l := strings.ToLower(lines[i]); strings.HasPrefix(l, "// output:") || strings.HasPrefix(l, "// unordered output:") {
+			lines = lines[:i]
+			break
+		}
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n"), nil
+}
+
+// indentLines prefixes each non-empty line of s with prefix.
+func indentLines(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = prefix + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// notes writes the BUG notes of the package, the only ones shown in the HTML
+// documentation.
+func (r *renderer) notes(p *doc.Package) {
+	bugs := p.Notes["BUG"]
+	if len(bugs) == 0 {
+		return
+	}
+	r.heading("BUGS")
+	for _, n := range bugs {
+		r.doc(n.Body, indent)
+		r.printf("\n")
+	}
+}
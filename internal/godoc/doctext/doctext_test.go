@@ -0,0 +1,169 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package doctext
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/godoc/internal/doc"
+)
+
+const (
+	pkgSource = `// Package p is a package.
+package p
+
+// Answer is the answer.
+const Answer = 42
+
+// T is a type.
+type T int
+
+// NewT returns a T.
+func NewT() T { return 0 }
+
+// Double doubles t.
+func (t T) Double() T { return 2 * t }
+
+// F is a function.
+func F() {}
+`
+	testSource = `package p
+
+import "fmt"
+
+func ExampleT_Double() {
+	fmt.Println(T(2).Double())
+	// Output: 4
+}
+`
+)
+
+func TestRender(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		opt       Options
+		want      string
+		wantError error
+	}{
+		{
+			name: "package",
+			want: `package p // import "example.com/p"
+
+Package p is a package.
+
+CONSTANTS
+
+const Answer = 42
+    Answer is the answer.
+
+FUNCTIONS
+
+func F()
+    F is a function.
+
+TYPES
+
+type T int
+    T is a type.
+
+func NewT() T
+    NewT returns a T.
+
+func (t T) Double() T
+    Double doubles t.
+
+    Example:
+        fmt.Println(T(2).Double())
+
+    Output:
+        4
+
+`,
+		},
+		{
+			name: "method",
+			opt:  Options{Symbol: "T.Double"},
+			want: `func (t T) Double() T
+    Double doubles t.
+
+    Example:
+        fmt.Println(T(2).Double())
+
+    Output:
+        4
+
+`,
+		},
+		{
+			name: "constant",
+			opt:  Options{Symbol: "Answer"},
+			want: "const Answer = 42\n    Answer is the answer.\n\n",
+		},
+		{
+			name: "associated function",
+			opt:  Options{Symbol: "NewT"},
+			want: "func NewT() T\n    NewT returns a T.\n\n",
+		},
+		{
+			name: "styled",
+			opt:  Options{Symbol: "F", Styled: true},
+			want: "\x1b[1mfunc F()\x1b[0m\n    F is a function.\n\n",
+		},
+		{
+			name:      "missing symbol",
+			opt:       Options{Symbol: "T.Triple"},
+			wantError: derrors.NotFound,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			fset, p := newPackage(t)
+			var buf bytes.Buffer
+			err := Render(context.Background(), &buf, fset, p, test.opt)
+			if !errors.Is(err, test.wantError) {
+				t.Fatalf("got error %v, want %v", err, test.wantError)
+			}
+			if got := buf.String(); got != test.want {
+				t.Errorf("got\n%s\nwant\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func newPackage(t *testing.T) (*token.FileSet, *doc.Package) {
+	t.Helper()
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, src := range map[string]string{"p.go": pkgSource, "p_test.go": testSource} {
+		f, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, f)
+	}
+	p, err := doc.NewFromFiles(fset, files, "example.com/p")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fset, p
+}
+
+func TestExampleCodeStripsOutput(t *testing.T) {
+	fset, p := newPackage(t)
+	code, err := exampleCode(fset, p.Types[0].Methods[0].Examples[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(code, "Output") {
+		t.Errorf("code contains output comment:\n%s", code)
+	}
+}
@@ -8,8 +8,10 @@ package godoc
 import (
 	"go/ast"
 	"go/token"
+	"go/types"
 	"strings"
 
+	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/godoc/dochtml"
 )
 
@@ -22,6 +24,8 @@ type Package struct {
 	Fset *token.FileSet
 	encPackage
 	renderCalled bool
+	coverage     *internal.DocCoverage // set by Render
+	typeInfo     *types.Info           // set by TypeCheck
 }
 
 // encPackage holds the fields of Package that can be directly encoded.
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
+	"io"
 	"path"
 	"sort"
 
@@ -17,6 +18,7 @@ import (
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/godoc/dochtml"
+	"golang.org/x/pkgsite/internal/godoc/doctext"
 	"golang.org/x/pkgsite/internal/godoc/internal/doc"
 	"golang.org/x/pkgsite/internal/source"
 	"golang.org/x/pkgsite/internal/stdlib"
@@ -40,11 +42,18 @@ var MaxDocumentationHTML = 20 * megabyte
 
 var noDocTemplate = template.Must(template.New("").Parse(`<p>No documentation for GOOS/GOARCH {{.}}</p>`))
 
+// NoDocumentationHTML returns HTML stating that there is no documentation for
+// the given GOOS and GOARCH.
+func NoDocumentationHTML(goos, goarch string) (safehtml.HTML, error) {
+	return noDocTemplate.ExecuteToHTML(goos + "/" + goarch)
+}
+
 // A Renderer renders documentation for a Package.
 type Renderer struct {
 }
 
-// Render renders the documentation for the package.
+// Render renders the documentation for the package, and records its
+// documentation coverage; see Coverage.
 // Rendering destroys p's AST; do not call any methods of p after it returns.
 func (p *Package) Render(ctx context.Context, innerPath string, sourceInfo *source.Info, modInfo *ModuleInfo, goos, goarch string) (synopsis string, imports []string, html safehtml.HTML, err error) {
 	// This is mostly copied from internal/fetch/fetch.go.
@@ -54,7 +63,7 @@ func (p *Package) Render(ctx context.Context, innerPath string, sourceInfo *sour
 
 	// Empty goos/goarch means we don't care.
 	if (goos != "" && goos != p.GOOS) || (goarch != "" && goarch != p.GOARCH) {
-		html, err := noDocTemplate.ExecuteToHTML(goos + "/" + goarch)
+		html, err := NoDocumentationHTML(goos, goarch)
 		if err != nil {
 			return "", nil, safehtml.HTML{}, err
 		}
@@ -64,6 +73,7 @@ func (p *Package) Render(ctx context.Context, innerPath string, sourceInfo *sour
 	if err != nil {
 		return "", nil, safehtml.HTML{}, err
 	}
+	p.coverage = docCoverage(d)
 
 	// Render documentation HTML.
 	opts := p.renderOptions(innerPath, sourceInfo, modInfo)
@@ -153,6 +163,7 @@ func (p *Package) renderOptions(innerPath string, sourceInfo *source.Info, modIn
 		FileLinkFunc:   fileLinkFunc,
 		SourceLinkFunc: sourceLinkFunc,
 		ModInfo:        modInfo,
+		TypeInfo:       p.typeInfo,
 		Limit:          int64(MaxDocumentationHTML),
 	}
 }
@@ -177,10 +188,28 @@ func (p *Package) RenderParts(ctx context.Context, innerPath string, sourceInfo
 	return parts, nil
 }
 
+// RenderText writes the documentation for the package, or for one of its
+// symbols, as text to w; see doctext.Render.
+// Rendering destroys p's AST; do not call any methods of p after it returns.
+func (p *Package) RenderText(ctx context.Context, w io.Writer, innerPath string, modInfo *ModuleInfo, opt doctext.Options) error {
+	p.renderCalled = true
+
+	d, err := p.docPackage(innerPath, modInfo)
+	if err != nil {
+		return err
+	}
+	return doctext.Render(ctx, w, p.Fset, d, opt)
+}
+
 // RenderPartsFromUnit is a convenience function that first decodes the source
-// in the unit, which must exist, and then calls RenderParts.
+// in the unit's documentation for the first build context, which must exist,
+// and then calls RenderParts.
 func RenderPartsFromUnit(ctx context.Context, u *internal.Unit) (_ *dochtml.Parts, err error) {
-	docPkg, err := DecodePackage(u.Documentation.Source)
+	doc := internal.DocumentationForBuildContext(u.Documentation, internal.BuildContextAll)
+	if doc == nil {
+		return nil, fmt.Errorf("RenderPartsFromUnit(%q): no documentation", u.Path)
+	}
+	docPkg, err := DecodePackage(doc.Source)
 	if err != nil {
 		return nil, err
 	}
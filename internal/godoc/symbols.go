@@ -0,0 +1,306 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/godoc/dochtml"
+	"golang.org/x/pkgsite/internal/godoc/internal/doc"
+)
+
+// Symbols returns the exported identifiers of p, sorted by name, along with
+// the first sentence of their doc comments and whether they are deprecated.
+// Commands have no symbols.
+//
+// Symbols reads p's AST, so it must be called before rendering.
+func (p *Package) Symbols() []*internal.Symbol {
+	var symbols []*internal.Symbol
+	for _, es := range p.exportedSymbols() {
+		s := &internal.Symbol{Name: es.name, Kind: es.kind, Deprecated: es.groupDeprecated}
+		for _, d := range es.docs {
+			if d == nil {
+				continue
+			}
+			if s.Synopsis == "" {
+				s.Synopsis = doc.Synopsis(d.Text())
+			}
+			if dochtml.IsDeprecated(d.Text()) {
+				s.Deprecated = true
+			}
+		}
+		symbols = append(symbols, s)
+	}
+	return symbols
+}
+
+// Declarations returns a map from the name of each symbol returned by Symbols
+// to a summary of its declaration, such as "func (*T) M(int) error" or
+// "field F string", for comparing the APIs of two versions of p. Parameter
+// and receiver names are left out. The interfaces embedded in an interface
+// type, which are not symbols of their own, are part of the declaration of
+// the type.
+//
+// Like Symbols, Declarations must be called before rendering.
+func (p *Package) Declarations() map[string]string {
+	decls := map[string]string{}
+	for _, es := range p.exportedSymbols() {
+		decls[es.name] = es.decl
+	}
+	return decls
+}
+
+// An exportedSymbol is an exported identifier of a package, as found by
+// Package.exportedSymbols.
+type exportedSymbol struct {
+	name string // qualified by the type name for methods and fields, as in "T.M"
+	kind internal.SymbolKind
+	decl string // see Package.Declarations
+	// docs are the comments that document the symbol, most specific first.
+	docs []*ast.CommentGroup
+	// groupDeprecated reports whether the symbol belongs to a parenthesized
+	// group of values whose doc comment marks it as deprecated.
+	groupDeprecated bool
+}
+
+// exportedSymbols returns the exported identifiers of p, sorted by name. It is
+// the single place that decides what the symbols of a package are, for
+// symbol search, symbol history and API differences alike.
+func (p *Package) exportedSymbols() []*exportedSymbol {
+	if p.renderCalled {
+		panic("godoc.Package.Symbols called after Render")
+	}
+	byName := map[string]*exportedSymbol{}
+	add := func(s *exportedSymbol) {
+		if _, ok := byName[s.name]; !ok {
+			byName[s.name] = s
+		}
+	}
+	for _, f := range p.Files {
+		if f.AST == nil || f.AST.Name.Name == "main" ||
+			strings.HasSuffix(f.Name, "_test.go") || strings.HasSuffix(f.AST.Name.Name, "_test") {
+			continue
+		}
+		for _, d := range f.AST.Decls {
+			switch d := d.(type) {
+			case *ast.FuncDecl:
+				addFunc(d, add)
+			case *ast.GenDecl:
+				addGenDecl(d, add)
+			}
+		}
+	}
+	var symbols []*exportedSymbol
+	for _, s := range byName {
+		symbols = append(symbols, s)
+	}
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i].name < symbols[j].name })
+	return symbols
+}
+
+// addFunc calls add for d if it is an exported function, or an exported
+// method of an exported type.
+func addFunc(d *ast.FuncDecl, add func(*exportedSymbol)) {
+	if !ast.IsExported(d.Name.Name) {
+		return
+	}
+	if d.Recv == nil || len(d.Recv.List) == 0 {
+		add(&exportedSymbol{
+			name: d.Name.Name,
+			kind: internal.SymbolKindFunction,
+			decl: "func " + d.Name.Name + signature(d.Type),
+			docs: []*ast.CommentGroup{d.Doc},
+		})
+		return
+	}
+	recv := d.Recv.List[0].Type
+	if base := typeName(recv); ast.IsExported(base) {
+		add(&exportedSymbol{
+			name: base + "." + d.Name.Name,
+			kind: internal.SymbolKindMethod,
+			decl: "func (" + types.ExprString(recv) + ") " + d.Name.Name + signature(d.Type),
+			docs: []*ast.CommentGroup{d.Doc},
+		})
+	}
+}
+
+// addGenDecl calls add for the exported constants, variables and types
+// declared by d, and for the exported fields and methods of those types.
+func addGenDecl(d *ast.GenDecl, add func(*exportedSymbol)) {
+	// A doc comment on an unparenthesized declaration is attached to the
+	// GenDecl rather than to its spec. A deprecated group of values is
+	// deprecated as a whole.
+	var declDoc *ast.CommentGroup
+	if !d.Lparen.IsValid() {
+		declDoc = d.Doc
+	}
+	groupDeprecated := d.Lparen.IsValid() && d.Doc != nil && dochtml.IsDeprecated(d.Doc.Text())
+	for _, spec := range d.Specs {
+		switch s := spec.(type) {
+		case *ast.ValueSpec:
+			kind := internal.SymbolKindVariable
+			if d.Tok == token.CONST {
+				kind = internal.SymbolKindConstant
+			}
+			for _, n := range s.Names {
+				if !ast.IsExported(n.Name) {
+					continue
+				}
+				decl := d.Tok.String() + " " + n.Name
+				if s.Type != nil {
+					decl += " " + types.ExprString(s.Type)
+				}
+				add(&exportedSymbol{
+					name:            n.Name,
+					kind:            kind,
+					decl:            decl,
+					docs:            []*ast.CommentGroup{s.Doc, declDoc, s.Comment},
+					groupDeprecated: groupDeprecated,
+				})
+			}
+		case *ast.TypeSpec:
+			if ast.IsExported(s.Name.Name) {
+				add(&exportedSymbol{
+					name: s.Name.Name,
+					kind: internal.SymbolKindType,
+					decl: typeDecl(s),
+					docs: []*ast.CommentGroup{s.Doc, declDoc, s.Comment},
+				})
+				addMembers(s, add)
+			}
+		}
+	}
+}
+
+// typeDecl returns the declaration of the type s, without its fields and
+// methods, which are symbols of their own.
+func typeDecl(s *ast.TypeSpec) string {
+	decl := "type " + s.Name.Name + " "
+	if s.Assign.IsValid() {
+		decl += "= "
+	}
+	switch t := s.Type.(type) {
+	case *ast.StructType:
+		return decl + "struct"
+	case *ast.InterfaceType:
+		decl += "interface"
+		var embeds []string
+		for _, m := range t.Methods.List {
+			if len(m.Names) == 0 {
+				embeds = append(embeds, types.ExprString(m.Type))
+			}
+		}
+		if len(embeds) > 0 {
+			decl += " embedding " + strings.Join(embeds, ", ")
+		}
+		return decl
+	default:
+		return decl + types.ExprString(s.Type)
+	}
+}
+
+// addMembers calls add for the exported fields of a struct type or the
+// exported methods of an interface type.
+func addMembers(s *ast.TypeSpec, add func(*exportedSymbol)) {
+	switch t := s.Type.(type) {
+	case *ast.StructType:
+		for _, f := range t.Fields.List {
+			typ := types.ExprString(f.Type)
+			if len(f.Names) == 0 {
+				// The name of an embedded field is the type name.
+				if n := typeName(f.Type); ast.IsExported(n) {
+					add(&exportedSymbol{
+						name: s.Name.Name + "." + n,
+						kind: internal.SymbolKindField,
+						decl: "embedded " + typ,
+						docs: []*ast.CommentGroup{f.Doc, f.Comment},
+					})
+				}
+				continue
+			}
+			for _, n := range f.Names {
+				if ast.IsExported(n.Name) {
+					add(&exportedSymbol{
+						name: s.Name.Name + "." + n.Name,
+						kind: internal.SymbolKindField,
+						decl: "field " + n.Name + " " + typ,
+						docs: []*ast.CommentGroup{f.Doc, f.Comment},
+					})
+				}
+			}
+		}
+	case *ast.InterfaceType:
+		for _, m := range t.Methods.List {
+			// An embedded interface adds no name of its own; it is part of
+			// the declaration of the type.
+			ft, ok := m.Type.(*ast.FuncType)
+			if len(m.Names) == 0 || !ok {
+				continue
+			}
+			for _, n := range m.Names {
+				if ast.IsExported(n.Name) {
+					add(&exportedSymbol{
+						name: s.Name.Name + "." + n.Name,
+						kind: internal.SymbolKindMethod,
+						decl: "method " + n.Name + signature(ft),
+						docs: []*ast.CommentGroup{m.Doc, m.Comment},
+					})
+				}
+			}
+		}
+	}
+}
+
+// signature returns the parameters and results of ft, without names.
+func signature(ft *ast.FuncType) string {
+	s := types.ExprString(&ast.FuncType{
+		Func:    token.NoPos,
+		Params:  withoutNames(ft.Params),
+		Results: withoutNames(ft.Results),
+	})
+	return strings.TrimPrefix(s, "func")
+}
+
+// withoutNames returns a copy of fl with one unnamed field per name.
+func withoutNames(fl *ast.FieldList) *ast.FieldList {
+	if fl == nil {
+		return nil
+	}
+	res := &ast.FieldList{}
+	for _, f := range fl.List {
+		n := len(f.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			res.List = append(res.List, &ast.Field{Type: f.Type})
+		}
+	}
+	return res
+}
+
+// typeName returns the name of the type in a receiver or embedded field
+// expression, such as "T" for "*T" or "pkg.T".
+func typeName(e ast.Expr) string {
+	for {
+		switch t := e.(type) {
+		case *ast.StarExpr:
+			e = t.X
+		case *ast.ParenExpr:
+			e = t.X
+		case *ast.SelectorExpr:
+			return t.Sel.Name
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
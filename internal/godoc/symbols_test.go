@@ -0,0 +1,117 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+)
+
+func TestSymbols(t *testing.T) {
+	const file = `
+package p
+
+import "io"
+
+// C is a constant. It is one.
+const C = 1
+
+const (
+	// D is another constant.
+	D = 2
+	E = 3 // E is a third.
+	f = 4
+)
+
+// Deprecated: use C.
+const (
+	G = 5
+	H = 6
+)
+
+// NewClient returns a new Client.
+func NewClient() *Client { return nil }
+
+func unexported() {}
+
+// Old is old.
+//
+// Deprecated: use NewClient.
+func Old() {}
+
+// A Client is a client.
+type Client struct {
+	// Name is the name.
+	Name string
+	Addr string // Deprecated: use Name.
+	io.Reader
+	secret int
+}
+
+// Do does it.
+func (c *Client) Do() {}
+
+func (t) M() {}
+
+type t int
+
+// Doer does.
+type Doer interface {
+	io.Closer
+	// Do does.
+	Do()
+}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", file, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewPackage(fset, "linux", "amd64", nil)
+	p.AddFile(f, true)
+	want := []*internal.Symbol{
+		{Name: "C", Kind: internal.SymbolKindConstant, Synopsis: "C is a constant."},
+		{Name: "Client", Kind: internal.SymbolKindType, Synopsis: "A Client is a client."},
+		{Name: "Client.Addr", Kind: internal.SymbolKindField, Synopsis: "Deprecated: use Name.", Deprecated: true},
+		{Name: "Client.Do", Kind: internal.SymbolKindMethod, Synopsis: "Do does it."},
+		{Name: "Client.Name", Kind: internal.SymbolKindField, Synopsis: "Name is the name."},
+		{Name: "Client.Reader", Kind: internal.SymbolKindField},
+		{Name: "D", Kind: internal.SymbolKindConstant, Synopsis: "D is another constant."},
+		{Name: "Doer", Kind: internal.SymbolKindType, Synopsis: "Doer does."},
+		{Name: "Doer.Do", Kind: internal.SymbolKindMethod, Synopsis: "Do does."},
+		{Name: "E", Kind: internal.SymbolKindConstant, Synopsis: "E is a third."},
+		{Name: "G", Kind: internal.SymbolKindConstant, Deprecated: true},
+		{Name: "H", Kind: internal.SymbolKindConstant, Deprecated: true},
+		{Name: "NewClient", Kind: internal.SymbolKindFunction, Synopsis: "NewClient returns a new Client."},
+		{Name: "Old", Kind: internal.SymbolKindFunction, Synopsis: "Old is old.", Deprecated: true},
+	}
+	if diff := cmp.Diff(want, p.Symbols()); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	wantDecls := map[string]string{
+		"C":             "const C",
+		"Client":        "type Client struct",
+		"Client.Addr":   "field Addr string",
+		"Client.Do":     "func (*Client) Do()",
+		"Client.Name":   "field Name string",
+		"Client.Reader": "embedded io.Reader",
+		"D":             "const D",
+		"Doer":          "type Doer interface embedding io.Closer",
+		"Doer.Do":       "method Do()",
+		"E":             "const E",
+		"G":             "const G",
+		"H":             "const H",
+		"NewClient":     "func NewClient() *Client",
+		"Old":           "func Old()",
+	}
+	if diff := cmp.Diff(wantDecls, p.Declarations()); diff != "" {
+		t.Errorf("Declarations mismatch (-want, +got):\n%s", diff)
+	}
+}
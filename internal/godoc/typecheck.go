@@ -0,0 +1,226 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// A SourceGetter returns the encoded source of the package with the given
+// import path, as produced by Package.Encode.
+type SourceGetter func(ctx context.Context, importPath string) ([]byte, error)
+
+// maxTypeCheckedImports is the maximum number of packages, imported directly
+// or indirectly, that TypeCheck loads. Imports beyond it fail to load.
+const maxTypeCheckedImports = 200
+
+// TypeCheck type-checks the non-test files of the package, whose import path
+// is importPath. It loads the packages they import, directly or indirectly,
+// by decoding the sources returned by getSource.
+//
+// If cache is non-nil, the packages it holds under the key that cacheKey
+// returns for an import path are used instead of loading them, and the
+// packages that are loaded are added to it. Packages with an empty key are
+// not cached.
+//
+// Render and RenderParts use the resulting type information to link the
+// identifiers in declarations to the exact objects they denote, including
+// dot-imported names, shadowed predeclared names, fields and promoted
+// methods. Identifiers that type checking could not resolve, because of type
+// errors or imports that failed to load, are linked as without type
+// information. Imports fail to load once ctx is done, so a deadline on ctx
+// bounds the time spent loading them.
+//
+// TypeCheck must be called before Render or RenderParts. It returns the first
+// error it encountered, but records the type information even if there were
+// errors.
+func (p *Package) TypeCheck(ctx context.Context, importPath string, getSource SourceGetter, cache *TypeCache, cacheKey func(importPath string) string) (err error) {
+	defer derrors.Wrap(&err, "godoc.Package.TypeCheck(%q)", importPath)
+
+	if p.renderCalled {
+		return errors.New("called after Render")
+	}
+	imp := &sourceImporter{
+		ctx:       ctx,
+		getSource: getSource,
+		cache:     cache,
+		cacheKey:  cacheKey,
+		pkgs:      map[string]*types.Package{importPath: nil},
+	}
+	info := &types.Info{
+		Uses:       map[*ast.Ident]types.Object{},
+		Selections: map[*ast.SelectorExpr]*types.Selection{},
+	}
+	_, err = imp.check(importPath, p, info)
+	p.typeInfo = info
+	return err
+}
+
+// sourceImporter is a types.Importer that type-checks the packages it imports
+// from their encoded sources. Each package is checked at most once.
+type sourceImporter struct {
+	ctx       context.Context
+	getSource SourceGetter
+	cache     *TypeCache
+	cacheKey  func(string) string
+	// pkgs maps import paths to checked packages. The value is nil for
+	// packages that are being checked, and for packages that failed to load.
+	pkgs map[string]*types.Package
+}
+
+// Import implements types.Importer.
+func (si *sourceImporter) Import(path string) (_ *types.Package, err error) {
+	if path == "unsafe" {
+		return types.Unsafe, nil
+	}
+	if pkg, ok := si.pkgs[path]; ok {
+		if pkg == nil {
+			return nil, fmt.Errorf("%s: import cycle or failed import", path)
+		}
+		return pkg, nil
+	}
+	if err := si.ctx.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	var key string
+	if si.cache != nil {
+		key = si.cacheKey(path)
+		if pkg := si.cache.get(key); pkg != nil {
+			si.pkgs[path] = pkg
+			return pkg, nil
+		}
+	}
+	if len(si.pkgs) >= maxTypeCheckedImports {
+		return nil, fmt.Errorf("%s: more than %d imports", path, maxTypeCheckedImports)
+	}
+	si.pkgs[path] = nil
+	src, err := si.getSource(si.ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	p, err := DecodePackage(src)
+	if err != nil {
+		return nil, err
+	}
+	// Errors in imported packages only make some of their declarations
+	// unusable, so the package is still worth using.
+	pkg, _ := si.check(path, p, nil)
+	si.pkgs[path] = pkg
+	// A package checked after ctx was done may lack some of its imports,
+	// so it is not kept for later calls.
+	if key != "" && pkg != nil && si.ctx.Err() == nil {
+		si.cache.put(key, pkg)
+	}
+	return pkg, nil
+}
+
+// check type-checks the non-test files of p, recording type information in
+// info if it is non-nil. It returns the first error encountered, along with
+// the package.
+func (si *sourceImporter) check(path string, p *Package, info *types.Info) (*types.Package, error) {
+	var files []*ast.File
+	for _, f := range p.Files {
+		if !strings.HasSuffix(f.Name, "_test.go") {
+			files = append(files, f.AST)
+		}
+	}
+	var firstErr error
+	conf := &types.Config{
+		Importer:         si,
+		FakeImportC:      true,
+		IgnoreFuncBodies: true,
+		Sizes:            types.SizesFor("gc", p.GOARCH),
+		// Setting Error makes the checker report all errors instead of
+		// stopping at the first one. Function bodies and unexported functions
+		// have been removed from the source, so errors are expected.
+		Error: func(err error) {
+			if firstErr == nil {
+				firstErr = err
+			}
+		},
+	}
+	pkg, _ := conf.Check(path, p.Fset, files, info)
+	return pkg, firstErr
+}
+
+// A TypeCache holds packages loaded by TypeCheck, so that later calls can use
+// them instead of loading them again. Each package is kept for a limited
+// time, so that packages loaded at the latest version of their module are
+// eventually reloaded. A TypeCache is safe for concurrent use.
+type TypeCache struct {
+	maxSize int
+	ttl     time.Duration
+
+	mu      sync.Mutex
+	entries map[string]typeCacheEntry
+}
+
+type typeCacheEntry struct {
+	pkg   *types.Package
+	added time.Time
+}
+
+// NewTypeCache returns a TypeCache that holds at most maxSize packages, each
+// for at most ttl.
+func NewTypeCache(maxSize int, ttl time.Duration) *TypeCache {
+	return &TypeCache{
+		maxSize: maxSize,
+		ttl:     ttl,
+		entries: map[string]typeCacheEntry{},
+	}
+}
+
+// get returns the package stored under key, or nil if there is none or it
+// has expired.
+func (c *TypeCache) get(key string) *types.Package {
+	if key == "" {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	if time.Since(e.added) > c.ttl {
+		delete(c.entries, key)
+		return nil
+	}
+	return e.pkg
+}
+
+// put stores pkg under key. If the cache is full, expired packages are
+// removed, and then the oldest package if that was not enough.
+func (c *TypeCache) put(key string, pkg *types.Package) {
+	if c.maxSize <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxSize {
+		var oldestKey string
+		var oldest time.Time
+		for k, e := range c.entries {
+			if time.Since(e.added) > c.ttl {
+				delete(c.entries, k)
+			} else if oldestKey == "" || e.added.Before(oldest) {
+				oldestKey, oldest = k, e.added
+			}
+		}
+		if len(c.entries) >= c.maxSize {
+			delete(c.entries, oldestKey)
+		}
+	}
+	c.entries[key] = typeCacheEntry{pkg: pkg, added: time.Now()}
+}
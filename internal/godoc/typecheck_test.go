@@ -0,0 +1,217 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import (
+	"context"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/godoc/dochtml"
+)
+
+func TestTypeCheck(t *testing.T) {
+	dochtml.LoadTemplates(templateSource)
+	ctx := context.Background()
+
+	newPackage := func(files map[string]string) *Package {
+		t.Helper()
+		fset := token.NewFileSet()
+		p := NewPackage(fset, "linux", "amd64", nil)
+		for name, src := range files {
+			f, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.AddFile(f, true)
+		}
+		return p
+	}
+	q := newPackage(map[string]string{
+		"q.go": `
+package q
+
+type Reader interface{ Read() }
+
+type inner struct{}
+
+func (inner) Promoted() {}
+
+type Outer struct{ inner }
+`,
+	})
+	qSource, err := q.Encode(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	getSource := func(_ context.Context, importPath string) ([]byte, error) {
+		if importPath == "example.com/q" {
+			return qSource, nil
+		}
+		return nil, fmt.Errorf("%s: %w", importPath, derrors.NotFound)
+	}
+	files := map[string]string{
+		"a.go": `
+package p
+
+import . "example.com/q"
+
+// NewReader returns a Reader.
+func NewReader() Reader { return nil }
+
+// P is promoted.
+var P = Outer.Promoted
+
+type string struct{}
+
+// T is a type.
+type T int
+`,
+		"b.go": `
+package p
+
+// F returns a T.
+func F() T { return 0 }
+
+// S shadows a predeclared type.
+func S() string { return string{} }
+`,
+	}
+	mi := &ModuleInfo{ModulePath: "example.com/p", ResolvedVersion: "v1.0.0"}
+
+	noSource := func(_ context.Context, importPath string) ([]byte, error) {
+		return nil, fmt.Errorf("%s: %w", importPath, derrors.NotFound)
+	}
+
+	for _, test := range []struct {
+		name          string
+		getSource     SourceGetter
+		wantErr       bool
+		want, wantNot []string
+	}{
+		{
+			name:      "heuristic",
+			getSource: nil,
+			want:      []string{`href="#T"`},
+			wantNot:   []string{`href="/example.com/q#Reader"`, `href="/example.com/q#Outer.Promoted"`},
+		},
+		{
+			name:      "type-checked",
+			getSource: getSource,
+			want: []string{
+				`href="/example.com/q#Reader"`,
+				`href="/example.com/q#Outer.Promoted"`,
+				`href="#T"`,
+			},
+			wantNot: []string{`href="/builtin#string"`},
+		},
+		{
+			// Identifiers that depend on the failed import fall back to
+			// heuristics.
+			name:      "failed import",
+			getSource: noSource,
+			wantErr:   true,
+			want:      []string{`href="#T"`},
+			wantNot:   []string{`href="/example.com/q#Reader"`},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			p := newPackage(files)
+			if test.getSource != nil {
+				err := p.TypeCheck(ctx, "example.com/p", test.getSource, nil, nil)
+				if (err != nil) != test.wantErr {
+					t.Errorf("TypeCheck: got error %v, want error: %t", err, test.wantErr)
+				}
+			}
+			_, _, html, err := p.Render(ctx, "", nil, mi, "", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := html.String()
+			for _, w := range test.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("doc does not contain %q", w)
+				}
+			}
+			for _, w := range test.wantNot {
+				if strings.Contains(got, w) {
+					t.Errorf("doc contains %q", w)
+				}
+			}
+		})
+	}
+}
+
+func TestTypeCheckCache(t *testing.T) {
+	ctx := context.Background()
+	fset := token.NewFileSet()
+	q := NewPackage(fset, "linux", "amd64", nil)
+	f, err := parser.ParseFile(fset, "q.go", "package q\ntype Reader interface{ Read() }\n", parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.AddFile(f, true)
+	qSource, err := q.Encode(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loads := 0
+	getSource := func(_ context.Context, importPath string) ([]byte, error) {
+		loads++
+		return qSource, nil
+	}
+	cacheKey := func(importPath string) string { return importPath + "@v1.0.0" }
+
+	cache := NewTypeCache(10, time.Hour)
+	for i := 0; i < 2; i++ {
+		fset := token.NewFileSet()
+		p := NewPackage(fset, "linux", "amd64", nil)
+		f, err := parser.ParseFile(fset, "p.go", "package p\nimport \"example.com/q\"\nfunc F() q.Reader { return nil }\n", parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.AddFile(f, true)
+		if err := p.TypeCheck(ctx, "example.com/p", getSource, cache, cacheKey); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("got %d loads of example.com/q, want 1", loads)
+	}
+
+	// A done context makes imports fail without loading them.
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	si := &sourceImporter{ctx: cctx, getSource: getSource, pkgs: map[string]*types.Package{}}
+	if _, err := si.Import("example.com/q"); err == nil {
+		t.Error("Import with a done context succeeded, want error")
+	}
+	if loads != 1 {
+		t.Errorf("got %d loads of example.com/q after cancellation, want 1", loads)
+	}
+}
+
+func TestTypeCacheEviction(t *testing.T) {
+	c := NewTypeCache(2, time.Hour)
+	a, b, d := types.NewPackage("a", "a"), types.NewPackage("b", "b"), types.NewPackage("d", "d")
+	c.put("a", a)
+	c.put("b", b)
+	c.put("d", d)
+	if c.get("a") != nil {
+		t.Error("oldest package was not evicted")
+	}
+	if c.get("b") != b || c.get("d") != d {
+		t.Error("newer packages were evicted")
+	}
+	if c.get("") != nil {
+		t.Error("got a package for the empty key")
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+// GoMod holds the directives of the go.mod file of a module version.
+type GoMod struct {
+	// GoVersion is the version of the go directive, like "1.16", or empty
+	// if there is none.
+	GoVersion   string
+	Requires    []*GoModRequire
+	Replaces    []*GoModReplace
+	Excludes    []*GoModExclude
+	Retractions []*GoModRetraction
+
+	// Deprecated reports whether the module directive has a "Deprecated:"
+	// comment, whose text is DeprecationComment.
+	Deprecated         bool
+	DeprecationComment string
+}
+
+// GoModRequire is a require directive of a go.mod file.
+type GoModRequire struct {
+	ModulePath string
+	Version    string
+	// Indirect reports whether the requirement has an "// indirect"
+	// comment.
+	Indirect bool
+}
+
+// GoModReplace is a replace directive of a go.mod file.
+type GoModReplace struct {
+	OldPath string
+	// OldVersion is empty if all versions of OldPath are replaced.
+	OldVersion string
+	// NewPath is a module path, or a file path if NewVersion is empty.
+	NewPath    string
+	NewVersion string
+}
+
+// GoModExclude is an exclude directive of a go.mod file.
+type GoModExclude struct {
+	ModulePath string
+	Version    string
+}
+
+// GoModRetraction is a retract directive of a go.mod file. It retracts the
+// versions from Low to High, inclusive; Low and High are equal for a single
+// version.
+type GoModRetraction struct {
+	Low  string
+	High string
+	// Rationale is the comment of the directive, if any.
+	Rationale string
+}
+
+// ModuleDependent is a module version whose go.mod file requires a given
+// module.
+type ModuleDependent struct {
+	ModulePath string
+	Version    string
+	// RequiredVersion is the version of the given module that it requires.
+	RequiredVersion string
+}
+
+// ModuleDependency is a module version in the build list of a module
+// version, as computed by minimal version selection.
+type ModuleDependency struct {
+	ModulePath string
+	Version    string
+	// Direct reports whether the go.mod file of the main module requires the
+	// module.
+	Direct bool
+	// RequirementsUnknown reports whether the requirements of this module
+	// version are unknown, so that the build list may be missing some of its
+	// dependencies, or have lower versions of them.
+	RequirementsUnknown bool
+}
@@ -30,6 +30,17 @@ type DataSource struct {
 
 	mu            sync.Mutex
 	loadedModules map[string]*internal.Module
+	// localModules holds the arguments to fetch.FetchLocalModule for each
+	// loaded module, keyed by module path, so that it can be reloaded.
+	localModules map[string]localModule
+}
+
+// A localModule is a module loaded from a local directory.
+type localModule struct {
+	// requestedPath is the module path passed to fetch.FetchLocalModule, or
+	// empty if it was taken from the go.mod file.
+	requestedPath string
+	dir           string
 }
 
 // New creates and returns a new local datasource that bypasses license
@@ -38,6 +49,7 @@ func New() *DataSource {
 	return &DataSource{
 		sourceClient:  source.NewClient(1 * time.Minute),
 		loadedModules: make(map[string]*internal.Module),
+		localModules:  make(map[string]localModule),
 	}
 }
 
@@ -79,6 +91,7 @@ func (ds *DataSource) fetch(ctx context.Context, modulePath, localPath string) e
 	ds.mu.Lock()
 	defer ds.mu.Unlock()
 	ds.loadedModules[fr.ModulePath] = fr.Module
+	ds.localModules[fr.ModulePath] = localModule{requestedPath: modulePath, dir: localPath}
 	return nil
 }
 
@@ -99,8 +112,8 @@ func getFullPath(modulePath string) string {
 
 // GetUnit returns information about a unit. Both the module path and package
 // path must both be known.
-func (ds *DataSource) GetUnit(ctx context.Context, pathInfo *internal.UnitMeta, fields internal.FieldSet) (_ *internal.Unit, err error) {
-	defer derrors.Wrap(&err, "GetUnit(%q, %q)", pathInfo.Path, pathInfo.ModulePath)
+func (ds *DataSource) GetUnit(ctx context.Context, pathInfo *internal.UnitMeta, fields internal.FieldSet, bc internal.BuildContext) (_ *internal.Unit, err error) {
+	defer derrors.Wrap(&err, "GetUnit(%q, %q, %q)", pathInfo.Path, pathInfo.ModulePath, bc)
 
 	modulepath := pathInfo.ModulePath
 	path := pathInfo.Path
@@ -114,7 +127,7 @@ func (ds *DataSource) GetUnit(ctx context.Context, pathInfo *internal.UnitMeta,
 	module := ds.loadedModules[modulepath]
 	for _, unit := range module.Units {
 		if unit.Path == path {
-			return unit, nil
+			return unit.ForBuildContext(bc, fields), nil
 		}
 	}
 
@@ -182,6 +195,40 @@ func (ds *DataSource) GetNestedModules(ctx context.Context, modulePath string) (
 	return nil, nil
 }
 
+// GetSourceFile returns the contents of a .go file of a loaded module.
+func (ds *DataSource) GetSourceFile(ctx context.Context, modulePath, resolvedVersion, filePath string) (_ []byte, err error) {
+	defer derrors.Wrap(&err, "GetSourceFile(%q, %q, %q)", modulePath, resolvedVersion, filePath)
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	module := ds.loadedModules[modulePath]
+	if module == nil {
+		return nil, fmt.Errorf("%s not loaded: %w", modulePath, derrors.NotFound)
+	}
+	for _, f := range module.SourceFiles {
+		if f.Path == filePath {
+			return f.Contents, nil
+		}
+	}
+	return nil, fmt.Errorf("%s not found: %w", filePath, derrors.NotFound)
+}
+
+// GetGoMod returns the directives of the go.mod file of a loaded module.
+func (ds *DataSource) GetGoMod(ctx context.Context, modulePath, resolvedVersion string) (_ *internal.GoMod, err error) {
+	defer derrors.Wrap(&err, "GetGoMod(%q, %q)", modulePath, resolvedVersion)
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	module := ds.loadedModules[modulePath]
+	if module == nil {
+		return nil, fmt.Errorf("%s not loaded: %w", modulePath, derrors.NotFound)
+	}
+	if module.GoMod == nil {
+		return nil, fmt.Errorf("%s has no go.mod file: %w", modulePath, derrors.NotFound)
+	}
+	return module.GoMod, nil
+}
+
 // GetModuleReadme is not implemented.
 func (*DataSource) GetModuleReadme(ctx context.Context, modulePath, resolvedVersion string) (*internal.Readme, error) {
 	return nil, nil
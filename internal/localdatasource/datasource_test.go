@@ -221,7 +221,7 @@ func TestGetUnit(t *testing.T) {
 				Path:       test.path,
 				ModulePath: test.modulePath,
 			}
-			got, err := ds.GetUnit(ctx, um, 0)
+			got, err := ds.GetUnit(ctx, um, 0, internal.BuildContextAll)
 			if !test.wantLoaded {
 				if err == nil {
 					t.Fatalf("returned not loaded module %q", test.path)
@@ -0,0 +1,118 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localdatasource
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/pkgsite/internal/log"
+)
+
+// Watch checks the directories of the loaded modules for changes every
+// interval, until ctx is done. When a Go file, go.mod, README or LICENSE file
+// in a module's directory is added, changed or removed, Watch reloads the
+// module and then calls onReload with its path. If reloading fails, the
+// previously loaded version of the module is kept.
+func (ds *DataSource) Watch(ctx context.Context, interval time.Duration, onReload func(modulePath string)) {
+	stamps := map[string]map[string]fileStamp{}
+	for modulePath, lm := range ds.modulesToWatch() {
+		stamps[modulePath] = snapshot(lm.dir)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for modulePath, lm := range ds.modulesToWatch() {
+			s := snapshot(lm.dir)
+			if old, ok := stamps[modulePath]; ok && sameSnapshot(old, s) {
+				continue
+			}
+			stamps[modulePath] = s
+			if err := ds.fetch(ctx, lm.requestedPath, lm.dir); err != nil {
+				log.Errorf(ctx, "reloading %s: %v", modulePath, err)
+				continue
+			}
+			log.Infof(ctx, "reloaded %s from %s", modulePath, lm.dir)
+			if onReload != nil {
+				onReload(modulePath)
+			}
+		}
+	}
+}
+
+// modulesToWatch returns a copy of ds.localModules.
+func (ds *DataSource) modulesToWatch() map[string]localModule {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	m := make(map[string]localModule, len(ds.localModules))
+	for k, v := range ds.localModules {
+		m[k] = v
+	}
+	return m
+}
+
+// A fileStamp identifies the contents of a file without reading it.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// snapshot returns the stamps of the watched files under dir, keyed by path.
+// Errors are ignored: files that cannot be read are omitted.
+func snapshot(dir string) map[string]fileStamp {
+	s := map[string]fileStamp{}
+	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if path != dir && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isWatched(info.Name()) {
+			s[path] = fileStamp{info.ModTime(), info.Size()}
+		}
+		return nil
+	})
+	return s
+}
+
+// isWatched reports whether changes to the file with the given name affect
+// the documentation of a module.
+func isWatched(name string) bool {
+	if strings.HasSuffix(name, ".go") || name == "go.mod" {
+		return true
+	}
+	upper := strings.ToUpper(name)
+	for _, prefix := range []string{"README", "LICENSE", "LICENCE", "COPYING"} {
+		if strings.HasPrefix(upper, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameSnapshot(s1, s2 map[string]fileStamp) bool {
+	if len(s1) != len(s2) {
+		return false
+	}
+	for path, st1 := range s1 {
+		st2, ok := s2[path]
+		if !ok || !st1.modTime.Equal(st2.modTime) || st1.size != st2.size {
+			return false
+		}
+	}
+	return true
+}
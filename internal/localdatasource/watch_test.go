@@ -0,0 +1,89 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localdatasource
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/safehtml/template"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/fetch"
+	"golang.org/x/pkgsite/internal/godoc/dochtml"
+	"golang.org/x/pkgsite/internal/testing/testhelper"
+)
+
+func TestWatch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	dochtml.LoadTemplates(template.TrustedSourceFromConstant("../../content/static/html/doc"))
+	const modulePath = "example.com/watched"
+	dir, err := testhelper.CreateTestDirectory(map[string]string{
+		"go.mod":     "module " + modulePath + "\n\ngo 1.12",
+		"LICENSE":    testhelper.BSD0License,
+		"foo/foo.go": "// Package foo is the original.\npackage foo",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ds := New()
+	if err := ds.Load(ctx, dir); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := make(chan string, 1)
+	go ds.Watch(ctx, 10*time.Millisecond, func(modulePath string) { reloaded <- modulePath })
+
+	// Let Watch take its first snapshot, then change a Go file.
+	time.Sleep(50 * time.Millisecond)
+	src := "// Package foo has changed.\npackage foo\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "foo", "foo.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-reloaded:
+		if got != modulePath {
+			t.Errorf("reloaded %q, want %q", got, modulePath)
+		}
+	case <-ctx.Done():
+		t.Fatal("module was not reloaded")
+	}
+
+	um := &internal.UnitMeta{Path: modulePath + "/foo", ModulePath: modulePath, Version: fetch.LocalVersion}
+	u, err := ds.GetUnit(ctx, um, internal.WithMain, internal.BuildContextAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := u.Documentation[0].Synopsis, "Package foo has changed."; got != want {
+		t.Errorf("synopsis = %q, want %q", got, want)
+	}
+}
+
+func TestIsWatched(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		want bool
+	}{
+		{"foo.go", true},
+		{"foo_test.go", true},
+		{"go.mod", true},
+		{"README.md", true},
+		{"readme", true},
+		{"LICENSE.txt", true},
+		{"COPYING", true},
+		{"go.sum", false},
+		{"notes.txt", false},
+	} {
+		if got := isWatched(test.name); got != test.want {
+			t.Errorf("isWatched(%q) = %t, want %t", test.name, got, test.want)
+		}
+	}
+}
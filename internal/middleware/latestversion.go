@@ -7,6 +7,7 @@ package middleware
 import (
 	"bytes"
 	"context"
+	"html"
 	"net/http"
 	"regexp"
 	"strings"
@@ -23,6 +24,8 @@ const (
 	latestMajorClassPlaceholder   = "$$GODISCOVERY_LATESTMAJORCLASS$$"
 	LatestMajorVersionPlaceholder = "$$GODISCOVERY_LATESTMAJORVERSION$$"
 	LatestMajorVersionURL         = "$$GODISCOVERY_LATESTMAJORVERSIONURL$$"
+	deprecatedClassPlaceholder    = "$$GODISCOVERY_DEPRECATEDCLASS$$"
+	deprecationCommentPlaceholder = "$$GODISCOVERY_DEPRECATIONCOMMENT$$"
 )
 
 // latestInfoRegexp extracts values needed to determine the latest-version badge from a page's HTML.
@@ -32,7 +35,8 @@ type latestFunc func(ctx context.Context, unitPath, modulePath string) internal.
 
 // LatestVersions replaces the HTML placeholder values for the badge and banner
 // that displays whether the version of the package or module being served is
-// the latest minor version (badge) and the latest major version (banner).
+// the latest minor version (badge) and the latest major version (banner), and
+// for the banner that displays whether the module is deprecated.
 func LatestVersions(getLatest latestFunc) Middleware {
 	return func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -78,6 +82,13 @@ func LatestVersions(getLatest latestFunc) Middleware {
 				body = bytes.ReplaceAll(body, []byte(latestMajorClassPlaceholder), []byte(latestMajorClass))
 				body = bytes.ReplaceAll(body, []byte(LatestMajorVersionPlaceholder), []byte(latestMajorVersionText))
 				body = bytes.ReplaceAll(body, []byte(LatestMajorVersionURL), []byte(latest.MajorUnitPath))
+
+				deprecatedClass := ""
+				if !latest.Deprecated {
+					deprecatedClass = " UnitHeader-deprecatedBanner--hidden"
+				}
+				body = bytes.ReplaceAll(body, []byte(deprecatedClassPlaceholder), []byte(deprecatedClass))
+				body = bytes.ReplaceAll(body, []byte(deprecationCommentPlaceholder), []byte(html.EscapeString(latest.DeprecationComment)))
 			}
 			if _, err := w.Write(body); err != nil {
 				log.Errorf(r.Context(), "LatestVersions, writing: %v", err)
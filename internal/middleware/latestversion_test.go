@@ -277,3 +277,56 @@ func TestLatestMajorVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestDeprecation(t *testing.T) {
+	const in = `
+				<div class="UnitHeader-deprecatedBanner$$GODISCOVERY_DEPRECATEDCLASS$$"
+					 data-version="v1.0.0" data-mpath="foo.com/bar" data-ppath="foo.com/bar" data-pagetype="module">
+					<p>This module is deprecated. $$GODISCOVERY_DEPRECATIONCOMMENT$$</p>
+				</div>`
+	for _, test := range []struct {
+		name   string
+		latest internal.LatestInfo
+		want   string
+	}{
+		{
+			name:   "deprecated",
+			latest: internal.LatestInfo{Deprecated: true, DeprecationComment: "Use <foo.com/baz>."},
+			want: `
+				<div class="UnitHeader-deprecatedBanner"
+					 data-version="v1.0.0" data-mpath="foo.com/bar" data-ppath="foo.com/bar" data-pagetype="module">
+					<p>This module is deprecated. Use &lt;foo.com/baz&gt;.</p>
+				</div>`,
+		},
+		{
+			name:   "not deprecated",
+			latest: internal.LatestInfo{},
+			want: `
+				<div class="UnitHeader-deprecatedBanner UnitHeader-deprecatedBanner--hidden"
+					 data-version="v1.0.0" data-mpath="foo.com/bar" data-ppath="foo.com/bar" data-pagetype="module">
+					<p>This module is deprecated. </p>
+				</div>`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, in)
+			})
+			lfunc := func(context.Context, string, string) internal.LatestInfo { return test.latest }
+			ts := httptest.NewServer(LatestVersions(lfunc)(handler))
+			defer ts.Close()
+			resp, err := ts.Client().Get(ts.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_ = resp.Body.Close()
+			if string(got) != test.want {
+				t.Errorf("\ngot  %s\nwant %s", got, test.want)
+			}
+		})
+	}
+}
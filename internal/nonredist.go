@@ -7,6 +7,8 @@
 
 package internal
 
+import "path"
+
 func (m *Module) RemoveNonRedistributableData() {
 	for _, l := range m.Licenses {
 		l.RemoveNonRedistributableData()
@@ -14,6 +16,33 @@ func (m *Module) RemoveNonRedistributableData() {
 	for _, d := range m.Units {
 		d.RemoveNonRedistributableData()
 	}
+	m.removeNonRedistributableSourceFiles()
+}
+
+// removeNonRedistributableSourceFiles removes the source files in directories
+// that are not redistributable. A directory is redistributable if the unit
+// for it is. Directories without a unit, such as testdata directories, are
+// redistributable if the module is.
+func (m *Module) removeNonRedistributableSourceFiles() {
+	redist := map[string]bool{}
+	for _, u := range m.Units {
+		redist[Suffix(u.Path, m.ModulePath)] = u.IsRedistributable
+	}
+	var files []*SourceFile
+	for _, f := range m.SourceFiles {
+		dir := path.Dir(f.Path)
+		if dir == "." {
+			dir = ""
+		}
+		r, ok := redist[dir]
+		if !ok {
+			r = m.IsRedistributable
+		}
+		if r {
+			files = append(files, f)
+		}
+	}
+	m.SourceFiles = files
 }
 
 func (u *Unit) RemoveNonRedistributableData() {
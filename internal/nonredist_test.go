@@ -0,0 +1,43 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRemoveNonRedistributableSourceFiles(t *testing.T) {
+	m := &Module{
+		ModuleInfo: ModuleInfo{ModulePath: "example.com/m", IsRedistributable: true},
+		Units: []*Unit{
+			{UnitMeta: UnitMeta{Path: "example.com/m", IsRedistributable: true}},
+			{UnitMeta: UnitMeta{Path: "example.com/m/open", IsRedistributable: true}},
+			{UnitMeta: UnitMeta{Path: "example.com/m/closed", IsRedistributable: false}},
+		},
+		SourceFiles: []*SourceFile{
+			{Path: "m.go"},
+			{Path: "open/open.go"},
+			{Path: "closed/closed.go"},
+			{Path: "open/testdata/t.go"},
+		},
+	}
+	m.RemoveNonRedistributableData()
+	var got []string
+	for _, f := range m.SourceFiles {
+		got = append(got, f.Path)
+	}
+	want := []string{"m.go", "open/open.go", "open/testdata/t.go"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	m.IsRedistributable = false
+	m.RemoveNonRedistributableData()
+	if len(m.SourceFiles) != 2 {
+		t.Errorf("got %d files, want 2 (the files of redistributable units)", len(m.SourceFiles))
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/middleware"
+)
+
+// Autocomplete returns up to limit packages in search_documents whose path or
+// name starts with prefix, ignoring case, ordered by the number of packages
+// that import them. Only the Name, PackagePath, ModulePath, Version, Synopsis
+// and NumImportedBy fields of the results are set.
+//
+// Excluded packages are left out. Each of the two lookups of the query, by
+// path and by name, is answered by the prefix index on lower(package_path) or
+// lower(name).
+func (db *DB) Autocomplete(ctx context.Context, prefix string, limit int) (_ []*internal.SearchResult, err error) {
+	defer derrors.Wrap(&err, "DB.Autocomplete(ctx, %q, %d)", prefix, limit)
+	defer middleware.ElapsedStat(ctx, "Autocomplete")()
+
+	lookup := func(col string) string {
+		return `
+			(SELECT
+				package_path,
+				module_path,
+				version,
+				name,
+				synopsis,
+				imported_by_count,
+				redistributable
+			FROM search_documents
+			WHERE
+				lower(` + col + `) LIKE $1
+				AND ` + notExcludedExpr("package_path") + `
+			ORDER BY
+				imported_by_count DESC,
+				package_path
+			LIMIT $2)`
+	}
+	query := `
+		SELECT
+			package_path,
+			module_path,
+			version,
+			name,
+			synopsis,
+			imported_by_count,
+			redistributable
+		FROM (` + lookup("package_path") + `
+			UNION` + lookup("name") + `
+		) s
+		ORDER BY
+			imported_by_count DESC,
+			package_path
+		LIMIT $2`
+	var results []*internal.SearchResult
+	collect := func(rows *sql.Rows) error {
+		var (
+			r        internal.SearchResult
+			synopsis sql.NullString
+			redist   bool
+		)
+		if err := rows.Scan(&r.PackagePath, &r.ModulePath, &r.Version, &r.Name,
+			&synopsis, &r.NumImportedBy, &redist); err != nil {
+			return fmt.Errorf("rows.Scan(): %v", err)
+		}
+		if redist || db.bypassLicenseCheck {
+			r.Synopsis = synopsis.String
+		}
+		results = append(results, &r)
+		return nil
+	}
+	pattern := escapeLike(strings.ToLower(prefix)) + "%"
+	if err := db.db.RunQuery(ctx, query, collect, pattern, limit); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// escapeLike escapes the characters of s that are special in the pattern of a
+// LIKE expression.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
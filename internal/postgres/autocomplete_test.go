@@ -0,0 +1,74 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestAutocomplete(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer ResetTestDB(testDB, t)
+
+	for _, m := range []*internal.Module{
+		sample.Module("github.com/valid/module_name", sample.VersionString, "foo", "bar"),
+		sample.Module("example.com/foolish", sample.VersionString, "a"),
+		sample.Module("github.com/excluded/module", sample.VersionString, "foobar"),
+	} {
+		if err := testDB.InsertModule(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := testDB.InsertExcludedPrefix(ctx, "github.com/excluded", "someone", "because"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		prefix string
+		want   []string
+	}{
+		{"github.com/valid/", []string{"github.com/valid/module_name/bar", "github.com/valid/module_name/foo"}},
+		{"GitHub.com/Valid/module_name/f", []string{"github.com/valid/module_name/foo"}},
+		// Package names are matched too.
+		{"fo", []string{"github.com/valid/module_name/foo"}},
+		{"example", []string{"example.com/foolish/a"}},
+		// Special characters of LIKE patterns match only themselves.
+		{"github.com/valid/module%", nil},
+		{"github.com/valid/module_name_", nil},
+		{"missing", nil},
+		// Excluded packages are left out, whether their path or name matches.
+		{"github.com/excluded", nil},
+	} {
+		results, err := testDB.Autocomplete(ctx, test.prefix, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, r := range results {
+			got = append(got, r.PackagePath)
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("Autocomplete(%q) mismatch (-want, +got):\n%s", test.prefix, diff)
+		}
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	for _, test := range []struct{ in, want string }{
+		{"net/http", "net/http"},
+		{"a_b%c", `a\_b\%c`},
+		{`a\b`, `a\\b`},
+	} {
+		if got := escapeLike(test.in); got != test.want {
+			t.Errorf("escapeLike(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
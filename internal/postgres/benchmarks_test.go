@@ -43,14 +43,14 @@ func BenchmarkSearch(b *testing.B) {
 		b.Fatal(err)
 	}
 	db := New(ddb)
-	searchers := map[string]func(context.Context, string, int, int, int) ([]*internal.SearchResult, error){
+	searchers := map[string]func(context.Context, string, []internal.SearchFilter, int, int, int) ([]*internal.SearchResult, error){
 		"db.Search": db.Search,
 	}
 	for name, search := range searchers {
 		for _, query := range testQueries {
 			b.Run(name+":"+query, func(b *testing.B) {
 				for i := 0; i < b.N; i++ {
-					if _, err := search(ctx, query, 10, 0, 100); err != nil {
+					if _, err := search(ctx, query, nil, 10, 0, 100); err != nil {
 						b.Fatal(err)
 					}
 				}
@@ -0,0 +1,56 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// GetDocCoverage returns the documentation coverage of the package at pkgPath
+// in the given module version. It returns an error wrapping derrors.NotFound
+// if there is none.
+func (db *DB) GetDocCoverage(ctx context.Context, pkgPath, modulePath, resolvedVersion string) (_ *internal.DocCoverage, err error) {
+	defer derrors.Wrap(&err, "GetDocCoverage(ctx, %q, %q, %q)", pkgPath, modulePath, resolvedVersion)
+
+	query := `
+		SELECT
+			c.has_package_doc,
+			c.num_exported,
+			c.num_documented,
+			c.undocumented,
+			c.num_funcs,
+			c.num_funcs_with_examples,
+			c.num_examples
+		FROM documentation_coverage c
+		INNER JOIN units u ON u.id = c.unit_id
+		INNER JOIN paths p ON p.id = u.path_id
+		INNER JOIN modules m ON m.id = u.module_id
+		WHERE
+			p.path = $1
+			AND m.module_path = $2
+			AND m.version = $3`
+	var c internal.DocCoverage
+	err = db.db.QueryRow(ctx, query, pkgPath, modulePath, resolvedVersion).Scan(
+		&c.HasPackageDoc,
+		&c.NumExported,
+		&c.NumDocumented,
+		pq.Array(&c.Undocumented),
+		&c.NumFuncs,
+		&c.NumFuncsWithExamples,
+		&c.NumExamples)
+	switch err {
+	case sql.ErrNoRows:
+		return nil, derrors.NotFound
+	case nil:
+		return &c, nil
+	default:
+		return nil, err
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// insertDeprecation records whether the module of m is deprecated by the go.mod
+// file of m. It must only be called if m is the highest version of the
+// module: as for the go command, a module is deprecated only if the go.mod
+// file of its highest version says so.
+func insertDeprecation(ctx context.Context, db *database.DB, m *internal.Module, moduleID int) (err error) {
+	defer derrors.Wrap(&err, "insertDeprecation(ctx, %q, %q)", m.ModulePath, m.Version)
+
+	if _, err := db.Exec(ctx, `DELETE FROM module_deprecations WHERE module_path = $1`, m.ModulePath); err != nil {
+		return err
+	}
+	if m.GoMod == nil || !m.GoMod.Deprecated {
+		return nil
+	}
+	_, err = db.Exec(ctx, `
+		INSERT INTO module_deprecations (module_path, comment, module_id)
+		VALUES ($1, $2, $3)`,
+		m.ModulePath, m.GoMod.DeprecationComment, moduleID)
+	return err
+}
+
+// getModuleDeprecation reports whether the module with the given path is
+// deprecated, and returns its deprecation comment.
+func (db *DB) getModuleDeprecation(ctx context.Context, modulePath string) (_ bool, _ string, err error) {
+	defer derrors.Wrap(&err, "DB.getModuleDeprecation(ctx, %q)", modulePath)
+
+	var comment string
+	err = db.db.QueryRow(ctx, `
+		SELECT comment
+		FROM module_deprecations
+		WHERE module_path = $1`, modulePath).Scan(&comment)
+	switch err {
+	case sql.ErrNoRows:
+		return false, "", nil
+	case nil:
+		return true, comment, nil
+	default:
+		return false, "", err
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestModuleDeprecation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer ResetTestDB(testDB, t)
+
+	const modulePath = "example.com/deprecated"
+	insert := func(version string, gm *internal.GoMod) {
+		t.Helper()
+		m := sample.Module(modulePath, version, "")
+		m.GoMod = gm
+		if err := testDB.InsertModule(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check := func(wantDeprecated bool, wantComment string) {
+		t.Helper()
+		latest, err := testDB.GetLatestInfo(ctx, modulePath, modulePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if latest.Deprecated != wantDeprecated || latest.DeprecationComment != wantComment {
+			t.Errorf("got Deprecated=%t, DeprecationComment=%q; want %t, %q",
+				latest.Deprecated, latest.DeprecationComment, wantDeprecated, wantComment)
+		}
+	}
+
+	insert("v1.0.0", &internal.GoMod{})
+	check(false, "")
+	insert("v1.1.0", &internal.GoMod{Deprecated: true, DeprecationComment: "Use example.com/new."})
+	check(true, "Use example.com/new.")
+	// Only the highest version counts.
+	insert("v0.9.0", &internal.GoMod{})
+	check(true, "Use example.com/new.")
+	insert("v1.2.0", nil)
+	check(false, "")
+}
@@ -7,6 +7,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"strings"
 
 	"golang.org/x/pkgsite/internal/database"
@@ -36,6 +37,18 @@ func (db *DB) IsExcluded(ctx context.Context, path string) (_ bool, err error) {
 	return false, nil
 }
 
+// notExcludedExpr returns a SQL condition that is true if the path in column
+// col does not match the excluded list, in the same way as IsExcluded. It lets
+// queries leave out excluded paths before applying a limit.
+func notExcludedExpr(col string) string {
+	return fmt.Sprintf(`NOT EXISTS (
+			SELECT 1
+			FROM excluded_prefixes e
+			WHERE
+				%[1]s = e.prefix
+				OR starts_with(%[1]s, CASE WHEN e.prefix LIKE '%%/' THEN e.prefix ELSE e.prefix || '/' END))`, col)
+}
+
 // InsertExcludedPrefix inserts prefix into the excluded_prefixes table.
 //
 // For real-time administration (e.g. DOS prevention), use the dbadmin tool.
@@ -0,0 +1,132 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// insertGoMod replaces the go.mod directives of the module with the given ID
+// with those of m.
+func insertGoMod(ctx context.Context, db *database.DB, m *internal.Module, moduleID int) (err error) {
+	defer derrors.Wrap(&err, "insertGoMod(ctx, %q, %q)", m.ModulePath, m.Version)
+
+	if _, err := db.Exec(ctx, `DELETE FROM go_mods WHERE module_id = $1`, moduleID); err != nil {
+		return err
+	}
+	if _, err := db.Exec(ctx, `DELETE FROM module_requires WHERE module_id = $1`, moduleID); err != nil {
+		return err
+	}
+	gm := m.GoMod
+	if gm == nil {
+		return nil
+	}
+	// Store empty lists as empty JSON arrays rather than null.
+	replaces, excludes, retractions := gm.Replaces, gm.Excludes, gm.Retractions
+	if replaces == nil {
+		replaces = []*internal.GoModReplace{}
+	}
+	if excludes == nil {
+		excludes = []*internal.GoModExclude{}
+	}
+	if retractions == nil {
+		retractions = []*internal.GoModRetraction{}
+	}
+	replacesJSON, err := json.Marshal(replaces)
+	if err != nil {
+		return err
+	}
+	excludesJSON, err := json.Marshal(excludes)
+	if err != nil {
+		return err
+	}
+	retractionsJSON, err := json.Marshal(retractions)
+	if err != nil {
+		return err
+	}
+	var deprecationComment sql.NullString
+	if gm.Deprecated {
+		deprecationComment = sql.NullString{String: gm.DeprecationComment, Valid: true}
+	}
+	if _, err := db.Exec(ctx, `
+		INSERT INTO go_mods (module_id, go_version, replaces, excludes, retractions, deprecation_comment)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		moduleID, gm.GoVersion, replacesJSON, excludesJSON, retractionsJSON, deprecationComment); err != nil {
+		return err
+	}
+
+	var values []interface{}
+	seen := map[internal.GoModRequire]bool{}
+	for _, r := range gm.Requires {
+		// The go command rejects duplicate requirements, but they can
+		// still appear in go.mod files that are never built.
+		key := internal.GoModRequire{ModulePath: r.ModulePath, Version: r.Version}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		values = append(values, moduleID, r.ModulePath, r.Version, r.Indirect)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	cols := []string{"module_id", "required_module_path", "required_version", "indirect"}
+	return db.BulkInsert(ctx, "module_requires", cols, values, "")
+}
+
+// GetGoMod returns the go.mod directives of the given module version. It
+// returns an error wrapping derrors.NotFound if the module version has no
+// go.mod file, or was inserted before go.mod files were recorded.
+func (db *DB) GetGoMod(ctx context.Context, modulePath, resolvedVersion string) (_ *internal.GoMod, err error) {
+	defer derrors.Wrap(&err, "GetGoMod(ctx, %q, %q)", modulePath, resolvedVersion)
+
+	var (
+		moduleID           int
+		gm                 internal.GoMod
+		deprecationComment sql.NullString
+	)
+	err = db.db.QueryRow(ctx, `
+		SELECT g.module_id, g.go_version, g.replaces, g.excludes, g.retractions, g.deprecation_comment
+		FROM go_mods g
+		INNER JOIN modules m ON m.id = g.module_id
+		WHERE m.module_path = $1 AND m.version = $2`,
+		modulePath, resolvedVersion).Scan(&moduleID, &gm.GoVersion,
+		jsonbScanner{&gm.Replaces}, jsonbScanner{&gm.Excludes}, jsonbScanner{&gm.Retractions},
+		&deprecationComment)
+	switch err {
+	case sql.ErrNoRows:
+		return nil, derrors.NotFound
+	case nil:
+	default:
+		return nil, err
+	}
+	gm.Deprecated = deprecationComment.Valid
+	gm.DeprecationComment = deprecationComment.String
+
+	collect := func(rows *sql.Rows) error {
+		var r internal.GoModRequire
+		if err := rows.Scan(&r.ModulePath, &r.Version, &r.Indirect); err != nil {
+			return fmt.Errorf("rows.Scan(): %v", err)
+		}
+		gm.Requires = append(gm.Requires, &r)
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, `
+		SELECT required_module_path, required_version, indirect
+		FROM module_requires
+		WHERE module_id = $1
+		ORDER BY indirect, required_module_path, required_version`,
+		collect, moduleID); err != nil {
+		return nil, err
+	}
+	return &gm, nil
+}
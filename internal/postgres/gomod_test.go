@@ -0,0 +1,71 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestGetGoMod(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer ResetTestDB(testDB, t)
+
+	m := sample.DefaultModule()
+	m.GoMod = &internal.GoMod{
+		GoVersion: "1.16",
+		Requires: []*internal.GoModRequire{
+			{ModulePath: "example.com/b", Version: "v0.1.0", Indirect: true},
+			{ModulePath: "example.com/a", Version: "v1.2.3"},
+		},
+		Replaces: []*internal.GoModReplace{
+			{OldPath: "example.com/a", NewPath: "../a"},
+		},
+		Retractions: []*internal.GoModRetraction{
+			{Low: "v0.9.0", High: "v0.9.0", Rationale: "Broken."},
+		},
+		Deprecated:         true,
+		DeprecationComment: "Use example.com/new.",
+	}
+	if err := testDB.InsertModule(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+	got, err := testDB.GetGoMod(ctx, m.ModulePath, m.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Direct requirements come first.
+	want := &internal.GoMod{
+		GoVersion: "1.16",
+		Requires: []*internal.GoModRequire{
+			{ModulePath: "example.com/a", Version: "v1.2.3"},
+			{ModulePath: "example.com/b", Version: "v0.1.0", Indirect: true},
+		},
+		Replaces:           m.GoMod.Replaces,
+		Retractions:        m.GoMod.Retractions,
+		Deprecated:         true,
+		DeprecationComment: "Use example.com/new.",
+	}
+	if diff := cmp.Diff(want, got, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	// Reinserting the module without a go.mod file removes it.
+	m.GoMod = nil
+	if err := testDB.InsertModule(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testDB.GetGoMod(ctx, m.ModulePath, m.Version); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("got error %v, want NotFound", err)
+	}
+}
@@ -93,6 +93,12 @@ func (db *DB) saveModule(ctx context.Context, m *internal.Module) (err error) {
 		if err := db.insertUnits(ctx, tx, m, moduleID); err != nil {
 			return err
 		}
+		if err := insertSourceFiles(ctx, tx, m, moduleID); err != nil {
+			return err
+		}
+		if err := insertGoMod(ctx, tx, m, moduleID); err != nil {
+			return err
+		}
 
 		// Obtain a transaction-scoped exclusive advisory lock on the module
 		// path. The transaction that holds the lock is the only one that can
@@ -104,6 +110,23 @@ func (db *DB) saveModule(ctx context.Context, m *internal.Module) (err error) {
 			return err
 		}
 
+		// The retractions and deprecation of a module come from the go.mod
+		// file of its highest version. Retractions must be up to date before
+		// finding out whether this is the latest version, since retracted
+		// versions are never the latest.
+		isHighest, err := isHighestVersion(ctx, tx, m.ModulePath, m.Version)
+		if err != nil {
+			return err
+		}
+		if isHighest {
+			if err := insertRetractions(ctx, tx, m, moduleID); err != nil {
+				return err
+			}
+			if err := insertDeprecation(ctx, tx, m, moduleID); err != nil {
+				return err
+			}
+		}
+
 		// We only insert into imports_unique and search_documents if this is
 		// the latest version of the module.
 		isLatest, err := isLatestVersion(ctx, tx, m.ModulePath, m.Version)
@@ -292,7 +315,7 @@ func (pdb *DB) insertUnits(ctx context.Context, db *database.DB, m *internal.Mod
 		paths         []string
 		unitValues    []interface{}
 		pathToReadme  = map[string]*internal.Readme{}
-		pathToDoc     = map[string]*internal.Documentation{}
+		pathToDoc     = map[string][]*internal.Documentation{}
 		pathToImports = map[string][]string{}
 		pathIDToPath  = map[int]string{}
 	)
@@ -329,8 +352,10 @@ func (pdb *DB) insertUnits(ctx context.Context, db *database.DB, m *internal.Mod
 		if u.Readme != nil {
 			pathToReadme[u.Path] = u.Readme
 		}
-		if u.Documentation != nil && u.Documentation.Source == nil {
-			return fmt.Errorf("insertUnits: unit %q missing source files", u.Path)
+		for _, d := range u.Documentation {
+			if d.Source == nil {
+				return fmt.Errorf("insertUnits: unit %q missing source files for %s/%s", u.Path, d.GOOS, d.GOARCH)
+			}
 		}
 		pathToDoc[u.Path] = u.Documentation
 		if len(u.Imports) > 0 {
@@ -352,6 +377,12 @@ func (pdb *DB) insertUnits(ctx context.Context, db *database.DB, m *internal.Mod
 	if err := insertDoc(ctx, db, paths, pathToUnitID, pathToDoc); err != nil {
 		return err
 	}
+	if err := insertSymbols(ctx, db, paths, pathToUnitID, pathToDoc); err != nil {
+		return err
+	}
+	if err := insertDocCoverage(ctx, db, paths, pathToUnitID, pathToDoc); err != nil {
+		return err
+	}
 	return insertImports(ctx, db, paths, pathToUnitID, pathToImports)
 }
 
@@ -442,23 +473,103 @@ func insertUnits(ctx context.Context, db *database.DB, unitValues []interface{})
 func insertDoc(ctx context.Context, db *database.DB,
 	paths []string,
 	pathToUnitID map[string]int,
-	pathToDoc map[string]*internal.Documentation) (err error) {
+	pathToDoc map[string][]*internal.Documentation) (err error) {
 	defer derrors.Wrap(&err, "insertDoc")
 
+	// Remove documentation for build contexts that are no longer processed,
+	// so that a unit only has rows for the build contexts it was last
+	// fetched with.
+	var unitIDs []int
+	for _, path := range paths {
+		unitIDs = append(unitIDs, pathToUnitID[path])
+	}
+	if _, err := db.Exec(ctx, `DELETE FROM documentation WHERE unit_id = ANY($1)`, pq.Array(unitIDs)); err != nil {
+		return err
+	}
+
 	var docValues []interface{}
 	for _, path := range paths {
-		doc := pathToDoc[path]
-		if doc == nil {
-			continue
-		}
 		unitID := pathToUnitID[path]
-		docValues = append(docValues, unitID, doc.GOOS, doc.GOARCH, doc.Synopsis, doc.Source)
+		for _, doc := range pathToDoc[path] {
+			docValues = append(docValues, unitID, doc.GOOS, doc.GOARCH, doc.Synopsis, doc.Source)
+		}
 	}
 	uniqueCols := []string{"unit_id", "goos", "goarch"}
 	docCols := append(uniqueCols, "synopsis", "source")
 	return db.BulkUpsert(ctx, "documentation", docCols, docValues, uniqueCols)
 }
 
+// insertSymbols replaces the symbols of each unit with the union of the
+// symbols in its documentation for all build contexts.
+func insertSymbols(ctx context.Context, db *database.DB,
+	paths []string,
+	pathToUnitID map[string]int,
+	pathToDoc map[string][]*internal.Documentation) (err error) {
+	defer derrors.Wrap(&err, "insertSymbols")
+
+	var unitIDs []int
+	for _, path := range paths {
+		unitIDs = append(unitIDs, pathToUnitID[path])
+	}
+	if _, err := db.Exec(ctx, `DELETE FROM symbols WHERE unit_id = ANY($1)`, pq.Array(unitIDs)); err != nil {
+		return err
+	}
+
+	var symbolValues []interface{}
+	for _, path := range paths {
+		unitID := pathToUnitID[path]
+		seen := map[string]bool{}
+		for _, doc := range pathToDoc[path] {
+			for _, s := range doc.Symbols {
+				if seen[s.Name] {
+					continue
+				}
+				seen[s.Name] = true
+				symbolValues = append(symbolValues, unitID, s.Name, s.Identifier(), s.Kind, s.Synopsis, s.Deprecated)
+			}
+		}
+	}
+	if len(symbolValues) == 0 {
+		return nil
+	}
+	symbolCols := []string{"unit_id", "name", "identifier", "kind", "synopsis", "deprecated"}
+	return db.BulkInsert(ctx, "symbols", symbolCols, symbolValues, "")
+}
+
+// insertDocCoverage replaces the documentation coverage of each unit with the
+// coverage of its documentation for the preferred build context.
+func insertDocCoverage(ctx context.Context, db *database.DB,
+	paths []string,
+	pathToUnitID map[string]int,
+	pathToDoc map[string][]*internal.Documentation) (err error) {
+	defer derrors.Wrap(&err, "insertDocCoverage")
+
+	var unitIDs []int
+	for _, path := range paths {
+		unitIDs = append(unitIDs, pathToUnitID[path])
+	}
+	if _, err := db.Exec(ctx, `DELETE FROM documentation_coverage WHERE unit_id = ANY($1)`, pq.Array(unitIDs)); err != nil {
+		return err
+	}
+
+	var values []interface{}
+	for _, path := range paths {
+		doc := internal.DocumentationForBuildContext(pathToDoc[path], internal.BuildContextAll)
+		if doc == nil || doc.Coverage == nil {
+			continue
+		}
+		c := doc.Coverage
+		values = append(values, pathToUnitID[path], c.HasPackageDoc, c.NumExported, c.NumDocumented,
+			pq.Array(c.Undocumented), c.NumFuncs, c.NumFuncsWithExamples, c.NumExamples)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	cols := []string{"unit_id", "has_package_doc", "num_exported", "num_documented",
+		"undocumented", "num_funcs", "num_funcs_with_examples", "num_examples"}
+	return db.BulkInsert(ctx, "documentation_coverage", cols, values, "")
+}
+
 func insertImports(ctx context.Context, db *database.DB,
 	paths []string,
 	pathToUnitID map[string]int,
@@ -546,8 +657,21 @@ func isIncompatible(version string) bool {
 // isLatestVersion reports whether version is the latest version of the module.
 func isLatestVersion(ctx context.Context, ddb *database.DB, modulePath, resolvedVersion string) (_ bool, err error) {
 	defer derrors.Wrap(&err, "isLatestVersion(ctx, tx, %q)", modulePath)
+	return isFirstVersion(ctx, ddb, modulePath, resolvedVersion, orderByLatest)
+}
+
+// isHighestVersion reports whether version is the highest version of the
+// module, ignoring retractions.
+func isHighestVersion(ctx context.Context, ddb *database.DB, modulePath, resolvedVersion string) (_ bool, err error) {
+	defer derrors.Wrap(&err, "isHighestVersion(ctx, tx, %q)", modulePath)
+	return isFirstVersion(ctx, ddb, modulePath, resolvedVersion, orderByHighest)
+}
 
-	q, args, err := orderByLatest(squirrel.Select("m.version").
+// isFirstVersion reports whether version comes first among the versions of
+// the module in the given order.
+func isFirstVersion(ctx context.Context, ddb *database.DB, modulePath, resolvedVersion string,
+	order func(squirrel.SelectBuilder) squirrel.SelectBuilder) (bool, error) {
+	q, args, err := order(squirrel.Select("m.version").
 		From("modules m").
 		Where(squirrel.Eq{"m.module_path": modulePath})).
 		Limit(1).
@@ -559,7 +683,7 @@ func isLatestVersion(ctx context.Context, ddb *database.DB, modulePath, resolved
 	var v string
 	if err := row.Scan(&v); err != nil {
 		if err == sql.ErrNoRows {
-			return true, nil // It's the only version, so it's also the latest.
+			return true, nil // It's the only version, so it's also the first.
 		}
 		return false, err
 	}
@@ -83,7 +83,7 @@ func checkModule(ctx context.Context, t *testing.T, want *internal.Module) {
 	}
 
 	for _, wantu := range want.Units {
-		got, err := testDB.GetUnit(ctx, &wantu.UnitMeta, internal.AllFields)
+		got, err := testDB.GetUnit(ctx, &wantu.UnitMeta, internal.AllFields, internal.BuildContextAll)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -129,7 +129,7 @@ func TestInsertModuleLicenseCheck(t *testing.T) {
 			}
 
 			mod := sample.Module(sample.ModulePath, sample.VersionString, "")
-			checkHasRedistData(mod.Units[0].Readme.Contents, mod.Units[0].Documentation.Source, true)
+			checkHasRedistData(mod.Units[0].Readme.Contents, mod.Units[0].Documentation[0].Source, true)
 			mod.IsRedistributable = false
 			mod.Units[0].IsRedistributable = false
 
@@ -143,7 +143,7 @@ func TestInsertModuleLicenseCheck(t *testing.T) {
 				ModulePath: mod.ModulePath,
 				Version:    mod.Version,
 			}
-			u, err := db.GetUnit(ctx, pathInfo, internal.AllFields)
+			u, err := db.GetUnit(ctx, pathInfo, internal.AllFields, internal.BuildContextAll)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -155,7 +155,7 @@ func TestInsertModuleLicenseCheck(t *testing.T) {
 				readme = u.Readme.Contents
 			}
 			if u.Documentation != nil {
-				source = u.Documentation.Source
+				source = u.Documentation[0].Source
 			}
 			checkHasRedistData(readme, source, bypass)
 		})
@@ -283,7 +283,7 @@ func TestInsertModuleNewCoverage(t *testing.T) {
 	if err := testDB.InsertModule(ctx, m); err != nil {
 		t.Fatal(err)
 	}
-	u, err := testDB.GetUnit(ctx, &internal.UnitMeta{Path: m.ModulePath, ModulePath: m.ModulePath, Version: m.Version}, internal.AllFields)
+	u, err := testDB.GetUnit(ctx, &internal.UnitMeta{Path: m.ModulePath, ModulePath: m.ModulePath, Version: m.Version}, internal.AllFields, internal.BuildContextAll)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,241 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/lib/pq"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/middleware"
+)
+
+// GetModuleDependents returns up to limit modules whose go.mod files require
+// the module with the given path, ordered by module path. Only the highest
+// version of each dependent module that requires the module is considered.
+//
+// Instead of supporting pagination, this query runs with a limit.
+func (db *DB) GetModuleDependents(ctx context.Context, modulePath string, limit int) (_ []*internal.ModuleDependent, err error) {
+	defer derrors.Wrap(&err, "GetModuleDependents(ctx, %q, %d)", modulePath, limit)
+	defer middleware.ElapsedStat(ctx, "GetModuleDependents")()
+
+	query := `
+		SELECT module_path, version, required_version
+		FROM (
+			SELECT DISTINCT ON (m.module_path)
+				m.module_path,
+				m.version,
+				r.required_version
+			FROM module_requires r
+			INNER JOIN modules m ON m.id = r.module_id
+			WHERE
+				r.required_module_path = $1
+				AND m.module_path <> $1
+			ORDER BY
+				m.module_path,
+				m.sort_version DESC
+		) d
+		ORDER BY module_path
+		LIMIT $2`
+	var dependents []*internal.ModuleDependent
+	collect := func(rows *sql.Rows) error {
+		var d internal.ModuleDependent
+		if err := rows.Scan(&d.ModulePath, &d.Version, &d.RequiredVersion); err != nil {
+			return fmt.Errorf("rows.Scan(): %v", err)
+		}
+		dependents = append(dependents, &d)
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, query, collect, modulePath, limit); err != nil {
+		return nil, err
+	}
+	return dependents, nil
+}
+
+// GetModuleDependencies returns the build list of the given module version:
+// the module versions that it transitively requires, as selected by minimal
+// version selection over the go.mod files in the database, ordered by module
+// path. The module itself is not included.
+//
+// Module versions that are not in the database, or that were inserted before
+// go.mod files were recorded, have unknown requirements and are marked as
+// such. It returns an error wrapping derrors.NotFound if the go.mod file of
+// the module version is not known.
+func (db *DB) GetModuleDependencies(ctx context.Context, modulePath, resolvedVersion string) (_ []*internal.ModuleDependency, err error) {
+	defer derrors.Wrap(&err, "GetModuleDependencies(ctx, %q, %q)", modulePath, resolvedVersion)
+	defer middleware.ElapsedStat(ctx, "GetModuleDependencies")()
+
+	gm, err := db.GetGoMod(ctx, modulePath, resolvedVersion)
+	if err != nil {
+		return nil, err
+	}
+	main := module.Version{Path: modulePath, Version: resolvedVersion}
+	return buildList(ctx, main, gm, db.getRequirements)
+}
+
+// getRequirements returns the requirements of each of the module versions
+// whose requirements are known.
+func (db *DB) getRequirements(ctx context.Context, mvs []module.Version) (_ map[module.Version][]module.Version, err error) {
+	defer derrors.Wrap(&err, "getRequirements(ctx, %d module versions)", len(mvs))
+
+	var paths, versions []string
+	for _, mv := range mvs {
+		paths = append(paths, mv.Path)
+		versions = append(versions, mv.Version)
+	}
+	// A module without a go.mod file has no requirements. A module with one
+	// that has no row in go_mods was inserted before go.mod files were
+	// recorded, so its requirements are unknown.
+	query := `
+		SELECT m.module_path, m.version, r.required_module_path, r.required_version
+		FROM modules m
+		LEFT JOIN module_requires r ON r.module_id = m.id
+		WHERE
+			(m.module_path, m.version) IN (SELECT * FROM unnest($1::text[], $2::text[]))
+			AND (NOT COALESCE(m.has_go_mod, false)
+				OR EXISTS (SELECT 1 FROM go_mods g WHERE g.module_id = m.id))`
+	reqs := map[module.Version][]module.Version{}
+	collect := func(rows *sql.Rows) error {
+		var (
+			mv               module.Version
+			reqPath, reqVers sql.NullString
+		)
+		if err := rows.Scan(&mv.Path, &mv.Version, &reqPath, &reqVers); err != nil {
+			return fmt.Errorf("rows.Scan(): %v", err)
+		}
+		if !reqPath.Valid {
+			reqs[mv] = nil
+			return nil
+		}
+		reqs[mv] = append(reqs[mv], module.Version{Path: reqPath.String, Version: reqVers.String})
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, query, collect, pq.Array(paths), pq.Array(versions)); err != nil {
+		return nil, err
+	}
+	return reqs, nil
+}
+
+// buildList returns the build list of main, whose go.mod file is gm, by
+// minimal version selection: the highest version of each module that is
+// reachable from main in the requirement graph. load returns the
+// requirements of the module versions whose requirements are known. It is
+// called once for each level of the graph.
+//
+// As for the go command, the replace and exclude directives of the main
+// module apply to the whole graph: the requirements of a replaced module
+// version are those of its replacement, or unknown if it is replaced by a
+// directory, and requirements on excluded versions are ignored.
+func buildList(ctx context.Context, main module.Version, gm *internal.GoMod,
+	load func(context.Context, []module.Version) (map[module.Version][]module.Version, error)) ([]*internal.ModuleDependency, error) {
+
+	excluded := map[module.Version]bool{}
+	for _, e := range gm.Excludes {
+		excluded[module.Version{Path: e.ModulePath, Version: e.Version}] = true
+	}
+	// replacement returns the module version whose requirements are those of
+	// mv, and false if they cannot be known.
+	replacement := func(mv module.Version) (module.Version, bool) {
+		var r *internal.GoModReplace
+		for _, rep := range gm.Replaces {
+			if rep.OldPath != mv.Path {
+				continue
+			}
+			if rep.OldVersion == mv.Version {
+				r = rep
+				break
+			}
+			if rep.OldVersion == "" {
+				r = rep
+			}
+		}
+		if r == nil {
+			return mv, true
+		}
+		if r.NewVersion == "" {
+			// Replaced by a directory.
+			return module.Version{}, false
+		}
+		return module.Version{Path: r.NewPath, Version: r.NewVersion}, true
+	}
+
+	var (
+		reached = map[module.Version]bool{}
+		unknown = map[module.Version]bool{}
+		direct  = map[string]bool{}
+		next    []module.Version
+	)
+	add := func(mv module.Version) {
+		if mv.Path == main.Path || excluded[mv] || reached[mv] {
+			return
+		}
+		reached[mv] = true
+		next = append(next, mv)
+	}
+	for _, r := range gm.Requires {
+		direct[r.ModulePath] = true
+		add(module.Version{Path: r.ModulePath, Version: r.Version})
+	}
+	for len(next) > 0 {
+		level := next
+		next = nil
+		sources := map[module.Version]module.Version{}
+		var toLoad []module.Version
+		for _, mv := range level {
+			src, ok := replacement(mv)
+			if !ok {
+				unknown[mv] = true
+				continue
+			}
+			sources[mv] = src
+			toLoad = append(toLoad, src)
+		}
+		if len(toLoad) == 0 {
+			break
+		}
+		reqs, err := load(ctx, toLoad)
+		if err != nil {
+			return nil, err
+		}
+		for _, mv := range level {
+			src, ok := sources[mv]
+			if !ok {
+				continue
+			}
+			rs, ok := reqs[src]
+			if !ok {
+				unknown[mv] = true
+				continue
+			}
+			for _, r := range rs {
+				add(r)
+			}
+		}
+	}
+
+	selected := map[string]string{}
+	for mv := range reached {
+		if v, ok := selected[mv.Path]; !ok || semver.Compare(mv.Version, v) > 0 {
+			selected[mv.Path] = mv.Version
+		}
+	}
+	var deps []*internal.ModuleDependency
+	for path, version := range selected {
+		deps = append(deps, &internal.ModuleDependency{
+			ModulePath:          path,
+			Version:             version,
+			Direct:              direct[path],
+			RequirementsUnknown: unknown[module.Version{Path: path, Version: version}],
+		})
+	}
+	sort.Slice(deps, func(i, j int) bool { return deps[i].ModulePath < deps[j].ModulePath })
+	return deps, nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/mod/module"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+// insertModuleWithRequires inserts a module at the given version whose
+// go.mod file has the given requirements, each of the form "path@version".
+func insertModuleWithRequires(ctx context.Context, t *testing.T, modulePath, version string, requires ...string) {
+	t.Helper()
+	m := sample.Module(modulePath, version, "")
+	m.GoMod = &internal.GoMod{GoVersion: "1.16"}
+	for _, r := range requires {
+		mv := parseModuleVersion(t, r)
+		m.GoMod.Requires = append(m.GoMod.Requires, &internal.GoModRequire{ModulePath: mv.Path, Version: mv.Version})
+	}
+	if err := testDB.InsertModule(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func parseModuleVersion(t *testing.T, s string) module.Version {
+	t.Helper()
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] == '@' {
+			return module.Version{Path: s[:i], Version: s[i+1:]}
+		}
+	}
+	t.Fatalf("bad module version %q", s)
+	return module.Version{}
+}
+
+func TestGetModuleDependents(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer ResetTestDB(testDB, t)
+
+	insertModuleWithRequires(ctx, t, "example.com/lib", "v1.2.0")
+	insertModuleWithRequires(ctx, t, "example.com/b", "v1.0.0", "example.com/lib@v1.0.0")
+	// Only the highest version of a dependent counts.
+	insertModuleWithRequires(ctx, t, "example.com/b", "v1.1.0", "example.com/lib@v1.2.0")
+	insertModuleWithRequires(ctx, t, "example.com/a", "v0.1.0", "example.com/lib@v1.0.0")
+	// The module itself is not a dependent.
+	insertModuleWithRequires(ctx, t, "example.com/lib", "v1.1.0", "example.com/lib@v1.0.0")
+	insertModuleWithRequires(ctx, t, "example.com/other", "v1.0.0", "example.com/unrelated@v1.0.0")
+
+	got, err := testDB.GetModuleDependents(ctx, "example.com/lib", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*internal.ModuleDependent{
+		{ModulePath: "example.com/a", Version: "v0.1.0", RequiredVersion: "v1.0.0"},
+		{ModulePath: "example.com/b", Version: "v1.1.0", RequiredVersion: "v1.2.0"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	got, err = testDB.GetModuleDependents(ctx, "example.com/lib", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want[:1], got); diff != "" {
+		t.Errorf("with limit: mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestGetModuleDependencies(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer ResetTestDB(testDB, t)
+
+	insertModuleWithRequires(ctx, t, "example.com/main", "v1.0.0", "example.com/a@v1.0.0", "example.com/b@v1.0.0")
+	insertModuleWithRequires(ctx, t, "example.com/a", "v1.0.0", "example.com/c@v1.1.0")
+	insertModuleWithRequires(ctx, t, "example.com/b", "v1.0.0", "example.com/c@v1.2.0", "example.com/d@v0.1.0")
+	insertModuleWithRequires(ctx, t, "example.com/c", "v1.1.0")
+	insertModuleWithRequires(ctx, t, "example.com/c", "v1.2.0")
+	// example.com/d is not in the database.
+
+	got, err := testDB.GetModuleDependencies(ctx, "example.com/main", "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*internal.ModuleDependency{
+		{ModulePath: "example.com/a", Version: "v1.0.0", Direct: true},
+		{ModulePath: "example.com/b", Version: "v1.0.0", Direct: true},
+		{ModulePath: "example.com/c", Version: "v1.2.0"},
+		{ModulePath: "example.com/d", Version: "v0.1.0", RequirementsUnknown: true},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestBuildList(t *testing.T) {
+	graph := map[string][]string{
+		"example.com/a@v1.0.0":    {"example.com/c@v1.1.0", "example.com/main@v0.9.0"},
+		"example.com/b@v1.0.0":    {"example.com/c@v1.2.0", "example.com/x@v1.0.0"},
+		"example.com/c@v1.1.0":    nil,
+		"example.com/c@v1.2.0":    {"example.com/e@v1.0.0"},
+		"example.com/e@v1.0.0":    nil,
+		"example.com/fork@v2.0.0": {"example.com/f@v1.0.0"},
+		"example.com/f@v1.0.0":    nil,
+		"example.com/x@v1.1.0":    nil,
+	}
+	load := func(_ context.Context, mvs []module.Version) (map[module.Version][]module.Version, error) {
+		reqs := map[module.Version][]module.Version{}
+		for _, mv := range mvs {
+			rs, ok := graph[mv.Path+"@"+mv.Version]
+			if !ok {
+				continue
+			}
+			reqs[mv] = nil
+			for _, r := range rs {
+				reqs[mv] = append(reqs[mv], parseModuleVersion(t, r))
+			}
+		}
+		return reqs, nil
+	}
+	gm := &internal.GoMod{
+		Requires: []*internal.GoModRequire{
+			{ModulePath: "example.com/a", Version: "v1.0.0"},
+			{ModulePath: "example.com/b", Version: "v1.0.0"},
+			{ModulePath: "example.com/d", Version: "v1.0.0"},
+			{ModulePath: "example.com/g", Version: "v1.0.0"},
+			{ModulePath: "example.com/r", Version: "v1.0.0"},
+		},
+		Replaces: []*internal.GoModReplace{
+			{OldPath: "example.com/d", NewPath: "../d"},
+			{OldPath: "example.com/r", NewPath: "example.com/none", NewVersion: "v1.0.0"},
+			{OldPath: "example.com/r", OldVersion: "v1.0.0", NewPath: "example.com/fork", NewVersion: "v2.0.0"},
+		},
+		Excludes: []*internal.GoModExclude{
+			{ModulePath: "example.com/x", Version: "v1.0.0"},
+		},
+	}
+	main := module.Version{Path: "example.com/main", Version: "v1.0.0"}
+	got, err := buildList(context.Background(), main, gm, load)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*internal.ModuleDependency{
+		{ModulePath: "example.com/a", Version: "v1.0.0", Direct: true},
+		{ModulePath: "example.com/b", Version: "v1.0.0", Direct: true},
+		{ModulePath: "example.com/c", Version: "v1.2.0"},
+		// Replaced by a directory.
+		{ModulePath: "example.com/d", Version: "v1.0.0", Direct: true, RequirementsUnknown: true},
+		{ModulePath: "example.com/e", Version: "v1.0.0"},
+		// Required by the replacement of example.com/r@v1.0.0.
+		{ModulePath: "example.com/f", Version: "v1.0.0"},
+		// Not known.
+		{ModulePath: "example.com/g", Version: "v1.0.0", Direct: true, RequirementsUnknown: true},
+		{ModulePath: "example.com/r", Version: "v1.0.0", Direct: true},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
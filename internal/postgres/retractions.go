@@ -0,0 +1,61 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+
+	"golang.org/x/mod/semver"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/version"
+)
+
+// retractedExpr is a boolean SQL expression that reports whether the module
+// version of the row aliased m is retracted.
+const retractedExpr = `EXISTS (
+			SELECT 1
+			FROM module_retractions r
+			WHERE
+				r.module_path = m.module_path
+				AND m.sort_version BETWEEN r.low_sort_version AND r.high_sort_version)`
+
+// insertRetractions replaces the retractions of the module of m with the
+// retract directives of its go.mod file. It must only be called if m is the
+// highest version of the module: as for the go command, retract directives
+// only apply if they are in the go.mod file of the highest version, even if
+// that version retracts itself.
+func insertRetractions(ctx context.Context, db *database.DB, m *internal.Module, moduleID int) (err error) {
+	defer derrors.Wrap(&err, "insertRetractions(ctx, %q, %q)", m.ModulePath, m.Version)
+
+	if _, err := db.Exec(ctx, `DELETE FROM module_retractions WHERE module_path = $1`, m.ModulePath); err != nil {
+		return err
+	}
+	if m.GoMod == nil {
+		return nil
+	}
+	var values []interface{}
+	seen := map[internal.GoModRetraction]bool{}
+	for _, r := range m.GoMod.Retractions {
+		if !semver.IsValid(r.Low) || !semver.IsValid(r.High) {
+			continue
+		}
+		// Versions may be retracted more than once. The first rationale
+		// wins.
+		key := internal.GoModRetraction{Low: r.Low, High: r.High}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		values = append(values, m.ModulePath, r.Low, r.High,
+			version.ForSorting(r.Low), version.ForSorting(r.High), r.Rationale, moduleID)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	cols := []string{"module_path", "low", "high", "low_sort_version", "high_sort_version", "rationale", "module_id"}
+	return db.BulkInsert(ctx, "module_retractions", cols, values, "")
+}
@@ -0,0 +1,74 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestRetractions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer ResetTestDB(testDB, t)
+
+	const modulePath = "example.com/retract"
+	insert := func(version string, retractions ...*internal.GoModRetraction) {
+		t.Helper()
+		m := sample.Module(modulePath, version, "")
+		m.GoMod = &internal.GoMod{Retractions: retractions}
+		if err := testDB.InsertModule(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkLatest := func(want string) {
+		t.Helper()
+		um, err := testDB.GetUnitMeta(ctx, modulePath, internal.UnknownModulePath, internal.LatestVersion)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if um.Version != want {
+			t.Errorf("latest version: got %q, want %q", um.Version, want)
+		}
+	}
+	checkRetracted := func(want map[string]string) {
+		t.Helper()
+		versions, err := testDB.GetVersionsForPath(ctx, modulePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := map[string]string{}
+		for _, v := range versions {
+			if v.Retracted {
+				got[v.Version] = v.RetractionRationale
+			}
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("retracted versions mismatch (-want, +got):\n%s", diff)
+		}
+	}
+
+	insert("v1.0.0")
+	insert("v1.1.0")
+	// The highest version retracts itself along with the previous one.
+	insert("v1.2.0",
+		&internal.GoModRetraction{Low: "v1.1.0", High: "v1.1.0", Rationale: "Data race."},
+		&internal.GoModRetraction{Low: "v1.2.0", High: "v1.2.0"})
+	checkLatest("v1.0.0")
+	checkRetracted(map[string]string{"v1.1.0": "Data race.", "v1.2.0": ""})
+
+	// Retractions in lower versions are ignored.
+	insert("v0.9.0", &internal.GoModRetraction{Low: "v1.0.0", High: "v1.0.0"})
+	checkLatest("v1.0.0")
+
+	// A new highest version replaces the retractions.
+	insert("v1.3.0", &internal.GoModRetraction{Low: "v1.0.0", High: "v1.1.0", Rationale: "Insecure."})
+	checkLatest("v1.3.0")
+	checkRetracted(map[string]string{"v1.0.0": "Insecure.", "v1.1.0": "Insecure."})
+}
@@ -81,7 +81,7 @@ type searchEvent struct {
 }
 
 // A searcher is used to execute a single search request.
-type searcher func(db *DB, ctx context.Context, q string, limit, offset, maxResultCount int) searchResponse
+type searcher func(db *DB, ctx context.Context, q string, filters []internal.SearchFilter, limit, offset, maxResultCount int) searchResponse
 
 // The searchers used by Search.
 var searchers = map[string]searcher{
@@ -112,9 +112,13 @@ var searchers = map[string]searcher{
 // The gap in this optimization is search terms that are very frequent, but
 // rarely relevant: "int" or "package", for example. In these cases we'll pay
 // the penalty of a deep search that scans nearly every package.
-func (db *DB) Search(ctx context.Context, q string, limit, offset, maxResultCount int) (_ []*internal.SearchResult, err error) {
-	defer derrors.Wrap(&err, "DB.Search(ctx, %q, %d, %d)", q, limit, offset)
-	resp, err := db.hedgedSearch(ctx, q, limit, offset, maxResultCount, searchers, nil)
+//
+// Only packages that match all of the filters are returned. If there are
+// filters, q may be empty; then all packages that match the filters are
+// returned, most popular first.
+func (db *DB) Search(ctx context.Context, q string, filters []internal.SearchFilter, limit, offset, maxResultCount int) (_ []*internal.SearchResult, err error) {
+	defer derrors.Wrap(&err, "DB.Search(ctx, %q, %v, %d, %d)", q, filters, limit, offset)
+	resp, err := db.hedgedSearch(ctx, q, filters, limit, offset, maxResultCount, searchers, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -140,6 +144,8 @@ const (
 	// Start this off gently (close to 1), but consider lowering
 	// it as time goes by and more of the ecosystem converts to modules.
 	noGoModPenalty = 0.8
+	// Module is deprecated by its author.
+	deprecatedPenalty = 0.5
 )
 
 // scoreExpr is the expression that computes the search score.
@@ -151,21 +157,30 @@ const (
 //   dramatic: being 2x as popular only has an additive effect.
 // - A penalty factor for non-redistributable modules, since a lot of
 //   details cannot be displayed.
+// - A penalty factor for deprecated modules, whose authors recommend using
+//   something else.
 // The first argument to ts_rank is an array of weights for the four tsvector sections,
 // in the order D, C, B, A.
 // The weights below match the defaults except for B.
+// An empty query, which is only searched for with filters, ranks every
+// document the same.
 var scoreExpr = fmt.Sprintf(`
-		ts_rank('{0.1, 0.2, 1.0, 1.0}', tsv_search_tokens, websearch_to_tsquery($1)) *
+		CASE WHEN $1 = '' THEN 1
+		ELSE ts_rank('{0.1, 0.2, 1.0, 1.0}', tsv_search_tokens, websearch_to_tsquery($1)) END *
 		ln(exp(1)+imported_by_count) *
 		CASE WHEN redistributable THEN 1 ELSE %f END *
-		CASE WHEN COALESCE(has_go_mod, true) THEN 1 ELSE %f END
-	`, nonRedistributablePenalty, noGoModPenalty)
+		CASE WHEN COALESCE(has_go_mod, true) THEN 1 ELSE %f END *
+		CASE WHEN EXISTS (
+			SELECT 1 FROM module_deprecations md
+			WHERE md.module_path = search_documents.module_path
+		) THEN %f ELSE 1 END
+	`, nonRedistributablePenalty, noGoModPenalty, deprecatedPenalty)
 
 // hedgedSearch executes multiple search methods and returns the first
 // available result.
 // The optional guardTestResult func may be used to allow tests to control the
 // order in which search results are returned.
-func (db *DB) hedgedSearch(ctx context.Context, q string, limit, offset, maxResultCount int, searchers map[string]searcher, guardTestResult func(string) func()) (*searchResponse, error) {
+func (db *DB) hedgedSearch(ctx context.Context, q string, filters []internal.SearchFilter, limit, offset, maxResultCount int, searchers map[string]searcher, guardTestResult func(string) func()) (*searchResponse, error) {
 	searchStart := time.Now()
 	responses := make(chan searchResponse, len(searchers))
 	// cancel all unfinished searches when a result (or error) is returned. The
@@ -178,7 +193,7 @@ func (db *DB) hedgedSearch(ctx context.Context, q string, limit, offset, maxResu
 		s := s
 		go func() {
 			start := time.Now()
-			resp := s(db, searchCtx, q, limit, offset, maxResultCount)
+			resp := s(db, searchCtx, q, filters, limit, offset, maxResultCount)
 			log.Debug(ctx, searchEvent{
 				Type:    resp.source,
 				Latency: time.Since(start),
@@ -221,7 +236,11 @@ const hllRegisterCount = 128
 
 // deepSearch searches all packages for the query. It is slower, but results
 // are always valid.
-func (db *DB) deepSearch(ctx context.Context, q string, limit, offset, maxResultCount int) searchResponse {
+func (db *DB) deepSearch(ctx context.Context, q string, filters []internal.SearchFilter, limit, offset, maxResultCount int) searchResponse {
+	filter, err := searchFilterExpr(filters, "search_documents")
+	if err != nil {
+		return searchResponse{source: "deep", err: err}
+	}
 	query := fmt.Sprintf(`
 		SELECT *, COUNT(*) OVER() AS total
 		FROM (
@@ -234,7 +253,8 @@ func (db *DB) deepSearch(ctx context.Context, q string, limit, offset, maxResult
 				(%s) AS score
 				FROM
 					search_documents
-				WHERE tsv_search_tokens @@ websearch_to_tsquery($1)
+				WHERE ($1 = '' OR tsv_search_tokens @@ websearch_to_tsquery($1))
+				AND (%s)
 				ORDER BY
 					score DESC,
 					commit_time DESC,
@@ -242,7 +262,7 @@ func (db *DB) deepSearch(ctx context.Context, q string, limit, offset, maxResult
 		) r
 		WHERE r.score > 0.1
 		LIMIT $2
-		OFFSET $3`, scoreExpr)
+		OFFSET $3`, scoreExpr, filter)
 	var results []*internal.SearchResult
 	collect := func(rows *sql.Rows) error {
 		var r internal.SearchResult
@@ -253,7 +273,7 @@ func (db *DB) deepSearch(ctx context.Context, q string, limit, offset, maxResult
 		results = append(results, &r)
 		return nil
 	}
-	err := db.db.RunQuery(ctx, query, collect, q, limit, offset)
+	err = db.db.RunQuery(ctx, query, collect, q, limit, offset)
 	if err != nil {
 		results = nil
 	}
@@ -269,7 +289,11 @@ func (db *DB) deepSearch(ctx context.Context, q string, limit, offset, maxResult
 	}
 }
 
-func (db *DB) popularSearch(ctx context.Context, searchQuery string, limit, offset, maxResultCount int) searchResponse {
+func (db *DB) popularSearch(ctx context.Context, searchQuery string, filters []internal.SearchFilter, limit, offset, maxResultCount int) searchResponse {
+	filter, err := searchFilterExpr(filters, "search_documents")
+	if err != nil {
+		return searchResponse{source: "popular", err: err}
+	}
 	query := `
 		SELECT
 			package_path,
@@ -278,7 +302,7 @@ func (db *DB) popularSearch(ctx context.Context, searchQuery string, limit, offs
 			commit_time,
 			imported_by_count,
 			score
-		FROM popular_search($1, $2, $3, $4, $5)`
+		FROM popular_search($1, $2, $3, $4, $5, $6, $7)`
 	var results []*internal.SearchResult
 	collect := func(rows *sql.Rows) error {
 		var r internal.SearchResult
@@ -289,7 +313,7 @@ func (db *DB) popularSearch(ctx context.Context, searchQuery string, limit, offs
 		results = append(results, &r)
 		return nil
 	}
-	err := db.db.RunQuery(ctx, query, collect, searchQuery, limit, offset, nonRedistributablePenalty, noGoModPenalty)
+	err = db.db.RunQuery(ctx, query, collect, searchQuery, limit, offset, nonRedistributablePenalty, noGoModPenalty, filter, deprecatedPenalty)
 	if err != nil {
 		results = nil
 	}
@@ -331,7 +355,7 @@ func (db *DB) addPackageDataToSearchResults(ctx context.Context, results []*inte
 		keys = append(keys, key)
 	}
 	query := fmt.Sprintf(`
-		SELECT
+		SELECT DISTINCT ON (u.id)
 			p.path,
 			u.name,
 			d.synopsis,
@@ -349,7 +373,10 @@ func (db *DB) addPackageDataToSearchResults(ctx context.Context, results []*inte
 			documentation d
 		ON u.id = d.unit_id
 		WHERE
-			(p.path, m.version, m.module_path) IN (%s)`, strings.Join(keys, ","))
+			(p.path, m.version, m.module_path) IN (%s)
+		-- A package has a row for each build context it has documentation
+		-- for. Use the synopsis of the one that sorts first.
+		ORDER BY u.id, %s`, strings.Join(keys, ","), orderByBuildContextExpr)
 	collect := func(rows *sql.Rows) error {
 		var (
 			path, name, synopsis string
@@ -428,7 +455,8 @@ var upsertSearchStatement = fmt.Sprintf(`
 		u.id = d.unit_id
 	WHERE
 		p.path = $1
-	%s
+	%s,
+		%s
 	LIMIT 1
 	ON CONFLICT (package_path)
 	DO UPDATE SET
@@ -448,7 +476,7 @@ var upsertSearchStatement = fmt.Sprintf(`
 			THEN search_documents.version_updated_at
 			ELSE CURRENT_TIMESTAMP
 			END)
-	;`, hllRegisterCount, orderByLatestStmt)
+	;`, hllRegisterCount, orderByLatestStmt, orderByBuildContextExpr)
 
 // upsertSearchDocuments adds search information for mod ot the search_documents table.
 // It assumes that all non-redistributable data has been removed from mod.
@@ -464,8 +492,8 @@ func (db *DB) upsertSearchDocuments(ctx context.Context, ddb *database.DB, mod *
 			PackagePath: pkg.Path,
 			ModulePath:  mod.ModulePath,
 		}
-		if pkg.Documentation != nil {
-			args.Synopsis = pkg.Documentation.Synopsis
+		if doc := internal.DocumentationForBuildContext(pkg.Documentation, internal.BuildContextAll); doc != nil {
+			args.Synopsis = doc.Synopsis
 		}
 		if pkg.Readme != nil {
 			args.ReadmeFilePath = pkg.Readme.Filepath
@@ -156,7 +156,7 @@ func importGraph(popularPath, importerModule string, importerCount int) []*inter
 	m := sample.Module(popularPath, "v1.2.3", "")
 	m.Packages()[0].Imports = nil
 	// Try to improve the ts_rank of the 'foo' search term.
-	m.Packages()[0].Documentation.Synopsis = "foo"
+	m.Packages()[0].Documentation[0].Synopsis = "foo"
 	m.Units[0].Readme.Contents = "foo"
 	mods := []*internal.Module{m}
 
@@ -167,12 +167,12 @@ func importGraph(popularPath, importerModule string, importerCount int) []*inter
 			fullPath := importerModule + "/" + name
 			u := &internal.Unit{
 				UnitMeta: *sample.UnitMeta(fullPath, importerModule, m.Version, name, true),
-				Documentation: &internal.Documentation{
+				Documentation: []*internal.Documentation{{
 					Synopsis: sample.Synopsis,
 					GOOS:     sample.GOOS,
 					GOARCH:   sample.GOARCH,
 					Source:   []byte{},
-				},
+				}},
 				Imports: []string{popularPath},
 			}
 			sample.AddUnit(m, u)
@@ -306,7 +306,7 @@ func TestSearch(t *testing.T) {
 				t.Fatal(err)
 			}
 			guardTestResult := resultGuard(test.resultOrder)
-			resp, err := testDB.hedgedSearch(ctx, "foo", 2, 0, 100, searchers, guardTestResult)
+			resp, err := testDB.hedgedSearch(ctx, "foo", nil, 2, 0, 100, searchers, guardTestResult)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -341,7 +341,7 @@ func TestSearchErrors(t *testing.T) {
 		for name, search := range searchers {
 			if name == searcherName {
 				name := name
-				newSearchers[name] = func(*DB, context.Context, string, int, int, int) searchResponse {
+				newSearchers[name] = func(*DB, context.Context, string, []internal.SearchFilter, int, int, int) searchResponse {
 					return searchResponse{
 						source: name,
 						err:    errors.New("bad"),
@@ -395,7 +395,7 @@ func TestSearchErrors(t *testing.T) {
 				t.Fatal(err)
 			}
 			guardTestResult := resultGuard(test.resultOrder)
-			resp, err := testDB.hedgedSearch(ctx, "foo", 2, 0, 100, test.searchers, guardTestResult)
+			resp, err := testDB.hedgedSearch(ctx, "foo", nil, 2, 0, 100, test.searchers, guardTestResult)
 			if (err != nil) != test.wantErr {
 				t.Fatalf("hedgedSearch(): got error %v, want error: %t", err, test.wantErr)
 			}
@@ -418,10 +418,10 @@ func TestInsertSearchDocumentAndSearch(t *testing.T) {
 				Path:              "gocloud.dev/cloud",
 				IsRedistributable: true, // required because some test cases depend on the README contents
 			},
-			Documentation: &internal.Documentation{
+			Documentation: []*internal.Documentation{{
 				Synopsis: "Package cloud contains a library and tools for open cloud development in Go. The Go Cloud Development Kit (Go CDK)",
 				Source:   []byte{},
-			},
+			}},
 		}
 
 		modKube = "k8s.io"
@@ -431,17 +431,17 @@ func TestInsertSearchDocumentAndSearch(t *testing.T) {
 				Path:              "k8s.io/client-go",
 				IsRedistributable: true, // required because some test cases depend on the README contents
 			},
-			Documentation: &internal.Documentation{
+			Documentation: []*internal.Documentation{{
 				Synopsis: "Package client-go implements a Go client for Kubernetes.",
 				Source:   []byte{},
-			},
+			}},
 		}
 
 		kubeResult = func(score float64, numResults uint64) *internal.SearchResult {
 			return &internal.SearchResult{
 				Name:        pkgKube.Name,
 				PackagePath: pkgKube.Path,
-				Synopsis:    pkgKube.Documentation.Synopsis,
+				Synopsis:    pkgKube.Documentation[0].Synopsis,
 				Licenses:    []string{"MIT"},
 				CommitTime:  sample.CommitTime,
 				Version:     sample.VersionString,
@@ -455,7 +455,7 @@ func TestInsertSearchDocumentAndSearch(t *testing.T) {
 			return &internal.SearchResult{
 				Name:        pkgGoCDK.Name,
 				PackagePath: pkgGoCDK.Path,
-				Synopsis:    pkgGoCDK.Documentation.Synopsis,
+				Synopsis:    pkgGoCDK.Documentation[0].Synopsis,
 				Licenses:    []string{"MIT"},
 				CommitTime:  sample.CommitTime,
 				Version:     sample.VersionString,
@@ -559,7 +559,7 @@ func TestInsertSearchDocumentAndSearch(t *testing.T) {
 					test.limit = 10
 				}
 
-				got := searcher(testDB, ctx, test.searchQuery, test.limit, test.offset, 100)
+				got := searcher(testDB, ctx, test.searchQuery, nil, test.limit, test.offset, 100)
 				if got.err != nil {
 					t.Fatal(got.err)
 				}
@@ -582,8 +582,8 @@ func TestInsertSearchDocumentAndSearch(t *testing.T) {
 }
 
 func TestSearchPenalties(t *testing.T) {
-	// Verify that the penalties for non-redistributable modules and modules without
-	// go.mod files are applied correctly.
+	// Verify that the penalties for non-redistributable modules, modules without
+	// go.mod files and deprecated modules are applied correctly.
 	defer ResetTestDB(testDB, t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
@@ -594,12 +594,14 @@ func TestSearchPenalties(t *testing.T) {
 	modules := map[string]struct {
 		redist     bool
 		hasGoMod   bool
+		deprecated bool
 		multiplier float64 // applied to base score
 	}{
-		"both.com/foo":      {true, true, 1},
-		"nogomod.com/foo":   {true, false, noGoModPenalty},
-		"nonredist.com/foo": {false, true, nonRedistributablePenalty},
-		"neither.com/foo":   {false, false, noGoModPenalty * nonRedistributablePenalty},
+		"both.com/foo":       {true, true, false, 1},
+		"nogomod.com/foo":    {true, false, false, noGoModPenalty},
+		"nonredist.com/foo":  {false, true, false, nonRedistributablePenalty},
+		"neither.com/foo":    {false, false, false, noGoModPenalty * nonRedistributablePenalty},
+		"deprecated.com/foo": {true, true, true, deprecatedPenalty},
 	}
 
 	for path, m := range modules {
@@ -607,6 +609,9 @@ func TestSearchPenalties(t *testing.T) {
 		v.Packages()[0].IsRedistributable = m.redist
 		v.IsRedistributable = m.redist
 		v.HasGoMod = m.hasGoMod
+		if m.deprecated {
+			v.GoMod = &internal.GoMod{Deprecated: true}
+		}
 		if err := testDB.InsertModule(ctx, v); err != nil {
 			t.Fatal(err)
 		}
@@ -614,7 +619,7 @@ func TestSearchPenalties(t *testing.T) {
 
 	for method, searcher := range searchers {
 		t.Run(method, func(t *testing.T) {
-			res := searcher(testDB, ctx, "foo", 10, 0, 100)
+			res := searcher(testDB, ctx, "foo", nil, 10, 0, 100)
 			if res.err != nil {
 				t.Fatal(res.err)
 			}
@@ -649,7 +654,7 @@ func TestExcludedFromSearch(t *testing.T) {
 		t.Fatal(err)
 	}
 	// Search for both packages.
-	gotResults, err := testDB.Search(ctx, domain, 10, 0, 100)
+	gotResults, err := testDB.Search(ctx, domain, nil, 10, 0, 100)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -681,7 +686,7 @@ func TestSearchBypass(t *testing.T) {
 		{testDB, true},
 		{bypassDB, false},
 	} {
-		rs, err := test.db.Search(ctx, m.ModulePath, 10, 0, 100)
+		rs, err := test.db.Search(ctx, m.ModulePath, nil, 10, 0, 100)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -763,7 +768,7 @@ func TestUpsertSearchDocument(t *testing.T) {
 	insertModule := func(version string, gomod bool) {
 		m := sample.Module(sample.ModulePath, version, "A")
 		m.HasGoMod = gomod
-		m.Packages()[0].Documentation.Synopsis = "syn-" + version
+		m.Packages()[0].Documentation[0].Synopsis = "syn-" + version
 		if err := testDB.InsertModule(ctx, m); err != nil {
 			t.Fatal(err)
 		}
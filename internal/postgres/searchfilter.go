@@ -0,0 +1,67 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// searchFilterExpr returns a boolean SQL expression that is true for the rows
+// of search_documents that match all of the filters. The table is the name or
+// alias by which search_documents is referred to in the enclosing query.
+//
+// Filter values are quoted in the expression rather than passed as query
+// arguments, because the expression is also passed to the popular_search
+// function, which interpolates it into a query of its own.
+func searchFilterExpr(filters []internal.SearchFilter, table string) (_ string, err error) {
+	defer derrors.Wrap(&err, "searchFilterExpr(%v, %q)", filters, table)
+
+	if len(filters) == 0 {
+		return "TRUE", nil
+	}
+	var conds []string
+	for _, f := range filters {
+		value := pq.QuoteLiteral(f.Value)
+		var cond string
+		switch f.Kind {
+		case internal.SearchFilterLicense:
+			cond = fmt.Sprintf("EXISTS (SELECT 1 FROM unnest(%s.license_types) l WHERE lower(l) = lower(%s))", table, value)
+		case internal.SearchFilterModule:
+			cond = fmt.Sprintf("%s.module_path = %s", table, value)
+		case internal.SearchFilterIs:
+			switch f.Value {
+			case internal.SearchFilterIsCommand:
+				cond = fmt.Sprintf("%s.name = 'main'", table)
+			case internal.SearchFilterIsInternal:
+				cond = fmt.Sprintf("%s.package_path ~ '(^|/)internal(/|$)'", table)
+			default:
+				return "", fmt.Errorf("%q: %w", f, derrors.InvalidArgument)
+			}
+		case internal.SearchFilterGOOS:
+			cond = fmt.Sprintf(`EXISTS (
+				SELECT 1
+				FROM units u
+				INNER JOIN paths p ON p.id = u.path_id
+				INNER JOIN modules m ON m.id = u.module_id
+				INNER JOIN documentation d ON d.unit_id = u.id
+				WHERE p.path = %[1]s.package_path
+				AND m.module_path = %[1]s.module_path
+				AND m.version = %[1]s.version
+				AND d.goos = %[2]s)`, table, value)
+		default:
+			return "", fmt.Errorf("%q: %w", f, derrors.InvalidArgument)
+		}
+		if f.Negate {
+			cond = "NOT (" + cond + ")"
+		}
+		conds = append(conds, "("+cond+")")
+	}
+	return strings.Join(conds, " AND "), nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestSearchFilters(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer ResetTestDB(testDB, t)
+
+	m1 := sample.Module("example.com/tools", sample.VersionString, "cmd/foo", "internal/foo", "foo")
+	for _, u := range m1.Units {
+		switch u.Path {
+		case "example.com/tools/cmd/foo":
+			u.Name = "main"
+		case "example.com/tools/foo":
+			u.Documentation[0].GOOS = "windows"
+			u.BuildContexts = []internal.BuildContext{{GOOS: "windows", GOARCH: sample.GOARCH}}
+		}
+	}
+	m2 := sample.Module("example.com/lib", sample.VersionString, "foo")
+	for _, m := range []*internal.Module{m1, m2} {
+		if err := testDB.InsertModule(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, test := range []struct {
+		name    string
+		q       string
+		filters []internal.SearchFilter
+		want    []string
+	}{
+		{
+			name:    "module",
+			q:       "foo",
+			filters: []internal.SearchFilter{{Kind: internal.SearchFilterModule, Value: "example.com/lib"}},
+			want:    []string{"example.com/lib/foo"},
+		},
+		{
+			name:    "command",
+			q:       "foo",
+			filters: []internal.SearchFilter{{Kind: internal.SearchFilterIs, Value: internal.SearchFilterIsCommand}},
+			want:    []string{"example.com/tools/cmd/foo"},
+		},
+		{
+			name: "not internal",
+			q:    "foo",
+			filters: []internal.SearchFilter{
+				{Kind: internal.SearchFilterModule, Value: "example.com/tools"},
+				{Kind: internal.SearchFilterIs, Value: internal.SearchFilterIsInternal, Negate: true},
+			},
+			want: []string{"example.com/tools/cmd/foo", "example.com/tools/foo"},
+		},
+		{
+			name:    "goos",
+			q:       "foo",
+			filters: []internal.SearchFilter{{Kind: internal.SearchFilterGOOS, Value: "windows"}},
+			want:    []string{"example.com/tools/foo"},
+		},
+		{
+			name:    "license ignores case",
+			q:       "foo",
+			filters: []internal.SearchFilter{{Kind: internal.SearchFilterLicense, Value: "mit"}},
+			want:    []string{"example.com/lib/foo", "example.com/tools/cmd/foo", "example.com/tools/foo", "example.com/tools/internal/foo"},
+		},
+		{
+			name:    "negated license",
+			q:       "foo",
+			filters: []internal.SearchFilter{{Kind: internal.SearchFilterLicense, Value: sample.LicenseType, Negate: true}},
+			want:    nil,
+		},
+		{
+			name:    "filters only",
+			filters: []internal.SearchFilter{{Kind: internal.SearchFilterModule, Value: "example.com/lib"}},
+			want:    []string{"example.com/lib/foo"},
+		},
+	} {
+		for method, searcher := range searchers {
+			t.Run(test.name+":"+method, func(t *testing.T) {
+				resp := searcher(testDB, ctx, test.q, test.filters, 10, 0, 100)
+				if resp.err != nil {
+					t.Fatal(resp.err)
+				}
+				var got []string
+				for _, r := range resp.results {
+					got = append(got, r.PackagePath)
+				}
+				sort.Strings(got)
+				if diff := cmp.Diff(test.want, got); diff != "" {
+					t.Errorf("%s(%q, %v) mismatch (-want, +got):\n%s", method, test.q, test.filters, diff)
+				}
+			})
+		}
+	}
+}
+
+func TestSearchFilterExpr(t *testing.T) {
+	got, err := searchFilterExpr(nil, "sd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "TRUE"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	got, err = searchFilterExpr([]internal.SearchFilter{
+		{Kind: internal.SearchFilterModule, Value: "example.com/it's"},
+		{Kind: internal.SearchFilterIs, Value: internal.SearchFilterIsCommand, Negate: true},
+	}, "sd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `(sd.module_path = 'example.com/it''s') AND (NOT (sd.name = 'main'))`
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	for _, f := range []internal.SearchFilter{
+		{Kind: internal.SearchFilterIs, Value: "library"},
+		{Kind: "author", Value: "gopher"},
+	} {
+		if _, err := searchFilterExpr([]internal.SearchFilter{f}, "sd"); !errors.Is(err, derrors.InvalidArgument) {
+			t.Errorf("%v: got error %v, want InvalidArgument", f, err)
+		}
+	}
+}
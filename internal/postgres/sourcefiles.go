@@ -0,0 +1,58 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// insertSourceFiles replaces the source files of the module with the given
+// ID with those of m.
+func insertSourceFiles(ctx context.Context, db *database.DB, m *internal.Module, moduleID int) (err error) {
+	defer derrors.Wrap(&err, "insertSourceFiles(ctx, %q, %q)", m.ModulePath, m.Version)
+
+	if _, err := db.Exec(ctx, `DELETE FROM source_files WHERE module_id = $1`, moduleID); err != nil {
+		return err
+	}
+	var values []interface{}
+	for _, f := range m.SourceFiles {
+		values = append(values, moduleID, f.Path, f.Contents)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return db.BulkInsert(ctx, "source_files", []string{"module_id", "path", "contents"}, values, "")
+}
+
+// GetSourceFile returns the contents of the file at filePath, relative to the
+// module root, in the given module version. It returns an error wrapping
+// derrors.NotFound if the file is not stored.
+func (db *DB) GetSourceFile(ctx context.Context, modulePath, resolvedVersion, filePath string) (_ []byte, err error) {
+	defer derrors.Wrap(&err, "GetSourceFile(ctx, %q, %q, %q)", modulePath, resolvedVersion, filePath)
+
+	query := `
+		SELECT f.contents
+		FROM source_files f
+		INNER JOIN modules m ON m.id = f.module_id
+		WHERE
+			m.module_path = $1
+			AND m.version = $2
+			AND f.path = $3`
+	var contents []byte
+	err = db.db.QueryRow(ctx, query, modulePath, resolvedVersion, filePath).Scan(&contents)
+	switch err {
+	case sql.ErrNoRows:
+		return nil, derrors.NotFound
+	case nil:
+		return contents, nil
+	default:
+		return nil, err
+	}
+}
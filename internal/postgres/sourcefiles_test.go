@@ -0,0 +1,40 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestGetSourceFile(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer ResetTestDB(testDB, t)
+
+	m := sample.DefaultModule()
+	m.SourceFiles = []*internal.SourceFile{
+		{Path: sample.Suffix + "/foo.go", Contents: []byte("package foo\n")},
+	}
+	if err := testDB.InsertModule(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+	got, err := testDB.GetSourceFile(ctx, m.ModulePath, m.Version, sample.Suffix+"/foo.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "package foo\n" {
+		t.Errorf("got %q, want %q", got, "package foo\n")
+	}
+	_, err = testDB.GetSourceFile(ctx, m.ModulePath, m.Version, "missing.go")
+	if !errors.Is(err, derrors.NotFound) {
+		t.Errorf("got error %v, want NotFound", err)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/middleware"
+)
+
+// SimilarPaths returns up to limit paths of units that are similar to path,
+// most similar first. It is used to suggest alternatives to a path that was
+// not found, such as the path with different case, with its elements in a
+// different order or with a major version suffix. Path itself and excluded
+// paths are never returned.
+//
+// Similarity is measured by the trigrams the paths have in common, ignoring
+// case, using the trigram index on lower(paths.path).
+func (db *DB) SimilarPaths(ctx context.Context, path string, limit int) (_ []string, err error) {
+	defer derrors.Wrap(&err, "DB.SimilarPaths(ctx, %q, %d)", path, limit)
+	defer middleware.ElapsedStat(ctx, "SimilarPaths")()
+
+	query := `
+		SELECT p.path
+		FROM paths p
+		WHERE
+			lower(p.path) % lower($1)
+			AND p.path <> $1
+			AND EXISTS (SELECT 1 FROM units u WHERE u.path_id = p.id)
+			AND ` + notExcludedExpr("p.path") + `
+		ORDER BY
+			similarity(lower(p.path), lower($1)) DESC,
+			p.path
+		LIMIT $2`
+	var paths []string
+	collect := func(rows *sql.Rows) error {
+		var p string
+		if err := rows.Scan(&p); err != nil {
+			return fmt.Errorf("rows.Scan(): %v", err)
+		}
+		paths = append(paths, p)
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, query, collect, path, limit); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// SuggestQuery returns a correction of the search query q, or the empty
+// string if it has none. Each word of q that is not the name of a package is
+// replaced by the most similar package name, if there is one. Only words of
+// letters, digits and underscores are corrected; other words, such as paths,
+// quoted phrases and search operators, are left as they are.
+func (db *DB) SuggestQuery(ctx context.Context, q string) (_ string, err error) {
+	defer derrors.Wrap(&err, "DB.SuggestQuery(ctx, %q)", q)
+	defer middleware.ElapsedStat(ctx, "SuggestQuery")()
+
+	words := strings.Fields(q)
+	changed := false
+	for i, w := range words {
+		if w == "OR" || !isSuggestableWord(w) {
+			continue
+		}
+		s, err := similarPackageName(ctx, db.db, w)
+		if err != nil {
+			return "", err
+		}
+		if s != "" && s != strings.ToLower(w) {
+			words[i] = s
+			changed = true
+		}
+	}
+	if !changed {
+		return "", nil
+	}
+	return strings.Join(words, " "), nil
+}
+
+// similarPackageName returns the lower-case package name in search_documents
+// that is most similar to word, preferring the names of more imported
+// packages. It returns the empty string if no name is similar enough.
+func similarPackageName(ctx context.Context, ddb *database.DB, word string) (_ string, err error) {
+	defer derrors.Wrap(&err, "similarPackageName(ctx, ddb, %q)", word)
+
+	query := `
+		SELECT lower(name)
+		FROM search_documents
+		WHERE lower(name) % lower($1)
+		GROUP BY lower(name)
+		ORDER BY
+			similarity(lower(name), lower($1)) DESC,
+			SUM(imported_by_count) DESC,
+			lower(name)
+		LIMIT 1`
+	var name string
+	err = ddb.QueryRow(ctx, query, word).Scan(&name)
+	switch err {
+	case sql.ErrNoRows:
+		return "", nil
+	case nil:
+		return name, nil
+	default:
+		return "", err
+	}
+}
+
+// isSuggestableWord reports whether w is a word that SuggestQuery may
+// correct: a non-empty word of letters, digits and underscores.
+func isSuggestableWord(w string) bool {
+	for _, r := range w {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return false
+		}
+	}
+	return w != ""
+}
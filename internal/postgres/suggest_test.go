@@ -0,0 +1,103 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestSimilarPaths(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer ResetTestDB(testDB, t)
+
+	for _, m := range []*internal.Module{
+		sample.Module("github.com/gopher/yaml/v2", sample.VersionString, "parser"),
+		sample.Module("example.com/unrelated", sample.VersionString, "a"),
+		sample.Module("github.com/gopher/yaml/v3", sample.VersionString, "parser"),
+	} {
+		if err := testDB.InsertModule(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := testDB.InsertExcludedPrefix(ctx, "github.com/gopher/yaml/v3", "someone", "because"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		path string
+		want string // the most similar path, or empty for none
+	}{
+		{"github.com/Gopher/YAML/v2/parser", "github.com/gopher/yaml/v2/parser"},
+		{"github.com/gopher/yaml/parser", "github.com/gopher/yaml/v2/parser"},
+		{"github.com/yaml/gopher/v2/parser", "github.com/gopher/yaml/v2/parser"},
+		{"zzzzzzzz", ""},
+		// Excluded paths are never suggested.
+		{"github.com/gopher/yaml/v3/parsers", "github.com/gopher/yaml/v2/parser"},
+	} {
+		paths, err := testDB.SimilarPaths(ctx, test.path, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got string
+		if len(paths) > 0 {
+			got = paths[0]
+		}
+		if got != test.want {
+			t.Errorf("SimilarPaths(%q): got %q first, want %q", test.path, got, test.want)
+		}
+	}
+}
+
+func TestSuggestQuery(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer ResetTestDB(testDB, t)
+
+	m := sample.Module("example.com/formats", sample.VersionString, "yaml", "json")
+	if err := testDB.InsertModule(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		q, want string
+	}{
+		{"yamml", "yaml"},
+		{"yamml OR jsonn", "yaml OR json"},
+		{"yaml", ""},
+		{"example.com/yamml", ""},
+		{"zzzzzzzz", ""},
+	} {
+		got, err := testDB.SuggestQuery(ctx, test.q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("SuggestQuery(%q) = %q, want %q", test.q, got, test.want)
+		}
+	}
+}
+
+func TestIsSuggestableWord(t *testing.T) {
+	for _, test := range []struct {
+		word string
+		want bool
+	}{
+		{"yaml", true},
+		{"http2_conn", true},
+		{"", false},
+		{"github.com/a", false},
+		{`"go`, false},
+		{"-is:internal", false},
+	} {
+		if got := isSuggestableWord(test.word); got != test.want {
+			t.Errorf("isSuggestableWord(%q) = %t, want %t", test.word, got, test.want)
+		}
+	}
+}
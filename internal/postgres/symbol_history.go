@@ -0,0 +1,110 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/version"
+)
+
+// UpsertSymbolHistory replaces the symbol history of the package at pkgPath
+// in the module at modulePath. history maps the name of each exported symbol
+// to the earliest version of the module in which it appeared.
+func (db *DB) UpsertSymbolHistory(ctx context.Context, pkgPath, modulePath string, history map[string]string) (err error) {
+	defer derrors.Wrap(&err, "UpsertSymbolHistory(ctx, %q, %q)", pkgPath, modulePath)
+
+	var symbols []string
+	for s := range history {
+		symbols = append(symbols, s)
+	}
+	sort.Strings(symbols)
+	var values []interface{}
+	for _, s := range symbols {
+		values = append(values, pkgPath, modulePath, s, history[s])
+	}
+	return db.db.Transact(ctx, sql.LevelDefault, func(tx *database.DB) error {
+		if _, err := tx.Exec(ctx, `
+			DELETE FROM symbol_history
+			WHERE package_path = $1 AND module_path = $2`,
+			pkgPath, modulePath); err != nil {
+			return err
+		}
+		if len(values) == 0 {
+			return nil
+		}
+		cols := []string{"package_path", "module_path", "symbol", "since_version"}
+		return tx.BulkInsert(ctx, "symbol_history", cols, values, "")
+	})
+}
+
+// GetSymbolHistory returns a map from the name of each exported symbol of the
+// package at pkgPath in the module at modulePath to the earliest version of
+// the module in which it appeared.
+func (db *DB) GetSymbolHistory(ctx context.Context, pkgPath, modulePath string) (_ map[string]string, err error) {
+	defer derrors.Wrap(&err, "GetSymbolHistory(ctx, %q, %q)", pkgPath, modulePath)
+
+	history := map[string]string{}
+	query := `
+		SELECT symbol, since_version
+		FROM symbol_history
+		WHERE package_path = $1 AND module_path = $2`
+	err = db.db.RunQuery(ctx, query, func(rows *sql.Rows) error {
+		var symbol, version string
+		if err := rows.Scan(&symbol, &version); err != nil {
+			return err
+		}
+		history[symbol] = version
+		return nil
+	}, pkgPath, modulePath)
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// GetPreviousSymbols returns the names of the symbols of the package at
+// pkgPath in the latest stored version of the module at modulePath that
+// precedes version and is not a pseudo-version. It returns nil if there is no
+// such version.
+func (db *DB) GetPreviousSymbols(ctx context.Context, pkgPath, modulePath, vers string) (_ []string, err error) {
+	defer derrors.Wrap(&err, "GetPreviousSymbols(ctx, %q, %q, %q)", pkgPath, modulePath, vers)
+
+	query := `
+		SELECT s.name
+		FROM symbols s
+		WHERE s.unit_id = (
+			SELECT u.id
+			FROM units u
+			INNER JOIN paths p
+			ON p.id = u.path_id
+			INNER JOIN modules m
+			ON m.id = u.module_id
+			WHERE
+				p.path = $1
+				AND m.module_path = $2
+				AND m.version_type != 'pseudo'
+				AND m.sort_version < $3
+			ORDER BY m.sort_version DESC
+			LIMIT 1
+		)`
+	var names []string
+	err = db.db.RunQuery(ctx, query, func(rows *sql.Rows) error {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		names = append(names, name)
+		return nil
+	}, pkgPath, modulePath, version.ForSorting(vers))
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
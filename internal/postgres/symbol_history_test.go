@@ -0,0 +1,85 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestSymbolHistory(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer ResetTestDB(testDB, t)
+
+	const (
+		pkgPath    = "example.com/mod/pkg"
+		modulePath = "example.com/mod"
+	)
+	for _, want := range []map[string]string{
+		{"F": "v1.0.0", "T": "v1.0.0", "T.M": "v1.1.0"},
+		{"F": "v1.0.0", "G": "v1.2.0"},
+		{},
+	} {
+		if err := testDB.UpsertSymbolHistory(ctx, pkgPath, modulePath, want); err != nil {
+			t.Fatal(err)
+		}
+		got, err := testDB.GetSymbolHistory(ctx, pkgPath, modulePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("mismatch (-want, +got):\n%s", diff)
+		}
+	}
+}
+
+func TestGetPreviousSymbols(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer ResetTestDB(testDB, t)
+
+	for _, v := range []struct {
+		version string
+		symbols []string
+	}{
+		{"v1.0.0", []string{"F"}},
+		{"v1.1.0", []string{"F", "G"}},
+		{"v1.1.1-0.20190311183353-d8887717615a", []string{"F", "G", "H"}},
+	} {
+		m := sample.Module(sample.ModulePath, v.version, sample.Suffix)
+		doc := m.Units[1].Documentation[0]
+		for _, name := range v.symbols {
+			doc.Symbols = append(doc.Symbols, &internal.Symbol{Name: name, Kind: internal.SymbolKindFunction})
+		}
+		if err := testDB.InsertModule(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, test := range []struct {
+		version string
+		want    []string
+	}{
+		{"v1.0.0", nil},
+		{"v1.1.0", []string{"F"}},
+		// Pseudo-versions are skipped.
+		{"v1.2.0", []string{"F", "G"}},
+	} {
+		got, err := testDB.GetPreviousSymbols(ctx, sample.PackagePath, sample.ModulePath, test.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(got)
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("%s: mismatch (-want, +got):\n%s", test.version, diff)
+		}
+	}
+}
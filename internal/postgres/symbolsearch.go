@@ -0,0 +1,97 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// SearchSymbols returns the exported symbols whose unqualified name is
+// identifier, ignoring case, in the packages that are in search_documents. If
+// kind is non-empty, only symbols of that kind are returned, and only symbols
+// of packages that match all of the filters are returned. Results are
+// ordered by the number of packages that import the symbol's package, with
+// deprecated symbols last, and each has its Symbol field set.
+func (db *DB) SearchSymbols(ctx context.Context, identifier string, kind internal.SymbolKind, filters []internal.SearchFilter, limit, offset, maxResultCount int) (_ []*internal.SearchResult, err error) {
+	defer derrors.Wrap(&err, "DB.SearchSymbols(ctx, %q, %q, %v, %d, %d)", identifier, kind, filters, limit, offset)
+
+	filter, err := searchFilterExpr(filters, "sd")
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`
+		SELECT
+			p.path,
+			m.module_path,
+			m.version,
+			m.commit_time,
+			sd.imported_by_count,
+			u.name,
+			u.license_types,
+			u.redistributable,
+			s.name,
+			s.kind,
+			s.synopsis,
+			s.deprecated,
+			COUNT(*) OVER() AS total
+		FROM symbols s
+		INNER JOIN units u ON u.id = s.unit_id
+		INNER JOIN paths p ON p.id = u.path_id
+		INNER JOIN modules m ON m.id = u.module_id
+		INNER JOIN search_documents sd
+			ON sd.package_path = p.path
+			AND sd.module_path = m.module_path
+			AND sd.version = m.version
+		WHERE
+			lower(s.identifier) = lower($1)
+			AND ($2 = '' OR s.kind = $2)
+			AND (%s)
+			AND %s
+		ORDER BY
+			s.deprecated,
+			sd.imported_by_count DESC,
+			p.path,
+			s.name
+		LIMIT $3
+		OFFSET $4`, filter, notExcludedExpr("p.path"))
+	var results []*internal.SearchResult
+	collect := func(rows *sql.Rows) error {
+		var (
+			r            internal.SearchResult
+			sym          internal.Symbol
+			licenseTypes []string
+			redist       bool
+		)
+		if err := rows.Scan(&r.PackagePath, &r.ModulePath, &r.Version, &r.CommitTime,
+			&r.NumImportedBy, &r.Name, pq.Array(&licenseTypes), &redist,
+			&sym.Name, &sym.Kind, &sym.Synopsis, &sym.Deprecated, &r.NumResults); err != nil {
+			return fmt.Errorf("rows.Scan(): %v", err)
+		}
+		if !redist && !db.bypassLicenseCheck {
+			sym.Synopsis = ""
+		}
+		for _, l := range licenseTypes {
+			if l != "" {
+				r.Licenses = append(r.Licenses, l)
+			}
+		}
+		if r.NumResults > uint64(maxResultCount) {
+			r.NumResults = uint64(maxResultCount)
+		}
+		r.Symbol = &sym
+		results = append(results, &r)
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, query, collect, identifier, string(kind), limit, offset); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
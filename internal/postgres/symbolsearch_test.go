@@ -0,0 +1,75 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestSearchSymbols(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer ResetTestDB(testDB, t)
+
+	m := sample.DefaultModule()
+	m.Units[1].Documentation[0].Symbols = []*internal.Symbol{
+		{Name: "Agent.Do", Kind: internal.SymbolKindMethod, Synopsis: "Deprecated: use Client.Do.", Deprecated: true},
+		{Name: "Client", Kind: internal.SymbolKindType, Synopsis: "A Client is a client."},
+		{Name: "Client.Do", Kind: internal.SymbolKindMethod, Synopsis: "Do does it."},
+		{Name: "NewClient", Kind: internal.SymbolKindFunction, Synopsis: "NewClient returns a Client."},
+	}
+	// An excluded package with a symbol of the same name.
+	excluded := sample.Module("github.com/excluded/module", sample.VersionString, "foo")
+	excluded.Units[1].Documentation[0].Symbols = []*internal.Symbol{
+		{Name: "NewClient", Kind: internal.SymbolKindFunction},
+	}
+	for _, m := range []*internal.Module{m, excluded} {
+		if err := testDB.InsertModule(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := testDB.InsertExcludedPrefix(ctx, "github.com/excluded", "someone", "because"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		identifier string
+		kind       internal.SymbolKind
+		want       []string
+	}{
+		{"NewClient", "", []string{"NewClient"}},
+		{"newclient", "", []string{"NewClient"}},
+		// Deprecated symbols come last.
+		{"Do", internal.SymbolKindMethod, []string{"Client.Do", "Agent.Do"}},
+		{"Do", internal.SymbolKindFunction, nil},
+		{"Missing", "", nil},
+	} {
+		results, err := testDB.SearchSymbols(ctx, test.identifier, test.kind, nil, 10, 0, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, r := range results {
+			if r.NumResults != uint64(len(test.want)) {
+				t.Errorf("%s: got NumResults %d, want %d", test.identifier, r.NumResults, len(test.want))
+			}
+			if r.PackagePath != sample.PackagePath {
+				t.Errorf("%s: got package %q, want %q", test.identifier, r.PackagePath, sample.PackagePath)
+			}
+			got = append(got, r.Symbol.Name)
+			if want := r.Symbol.Name == "Agent.Do"; r.Symbol.Deprecated != want {
+				t.Errorf("%s: got Deprecated %t, want %t", r.Symbol.Name, r.Symbol.Deprecated, want)
+			}
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("SearchSymbols(%q, %q) mismatch (-want, +got):\n%s", test.identifier, test.kind, diff)
+		}
+	}
+}
@@ -110,7 +110,8 @@ func ResetTestDB(db *DB, t *testing.T) {
 			TRUNCATE search_documents;
 			TRUNCATE version_map;
 			TRUNCATE paths;
-			TRUNCATE imports_unique;`); err != nil {
+			TRUNCATE imports_unique;
+			TRUNCATE symbol_history;`); err != nil {
 			return err
 		}
 		if _, err := tx.Exec(ctx, `TRUNCATE module_version_states CASCADE;`); err != nil {
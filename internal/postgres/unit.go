@@ -126,6 +126,13 @@ func getUnitMetaQuery(fullPath, requestedModulePath, requestedVersion string) sq
 }
 
 // orderByLatest orders paths according to the go command.
+// Retracted versions come last; the others are ordered as by orderByHighest.
+func orderByLatest(q squirrel.SelectBuilder) squirrel.SelectBuilder {
+	return orderByHighest(q.OrderBy(retractedExpr))
+}
+
+// orderByHighest orders paths according to the go command, ignoring
+// retractions.
 // Versions are ordered by:
 // (1) release (non-incompatible)
 // (2) prerelease (non-incompatible)
@@ -134,7 +141,7 @@ func getUnitMetaQuery(fullPath, requestedModulePath, requestedVersion string) sq
 // (5) pseudo
 // They are then sorted based on semver, then decreasing module path length (so
 // that nested modules are preferred).
-func orderByLatest(q squirrel.SelectBuilder) squirrel.SelectBuilder {
+func orderByHighest(q squirrel.SelectBuilder) squirrel.SelectBuilder {
 	return q.OrderBy(
 		`CASE
 			WHEN m.version_type = 'release' AND NOT m.incompatible THEN 1
@@ -150,6 +157,7 @@ func orderByLatest(q squirrel.SelectBuilder) squirrel.SelectBuilder {
 
 const orderByLatestStmt = `
 			ORDER BY
+				` + retractedExpr + `,
 				CASE
 					WHEN m.version_type = 'release' AND NOT m.incompatible THEN 1
 					WHEN m.version_type = 'prerelease' AND NOT m.incompatible THEN 2
@@ -160,14 +168,29 @@ const orderByLatestStmt = `
 				m.sort_version DESC,
 				m.module_path DESC`
 
+// orderByBuildContextExpr orders the rows of the documentation table d the
+// way internal.CompareBuildContexts orders build contexts: the defaults come
+// first, in order, followed by all others sorted by GOOS and then GOARCH.
+var orderByBuildContextExpr = func() string {
+	var b strings.Builder
+	b.WriteString("CASE")
+	for i, bc := range internal.DefaultBuildContexts {
+		fmt.Fprintf(&b, " WHEN d.goos = '%s' AND d.goarch = '%s' THEN %d", bc.GOOS, bc.GOARCH, i)
+	}
+	fmt.Fprintf(&b, " ELSE %d END, d.goos, d.goarch", len(internal.DefaultBuildContexts))
+	return b.String()
+}()
+
 // GetUnit returns a unit from the database, along with all of the data
-// associated with that unit.
-func (db *DB) GetUnit(ctx context.Context, um *internal.UnitMeta, fields internal.FieldSet) (_ *internal.Unit, err error) {
-	defer derrors.Wrap(&err, "GetUnit(ctx, %q, %q, %q)", um.Path, um.ModulePath, um.Version)
+// associated with that unit. If fields includes internal.WithMain, the
+// documentation returned is the one for bc, or, if fields also includes
+// internal.WithAllDocumentation, all of those matching bc.
+func (db *DB) GetUnit(ctx context.Context, um *internal.UnitMeta, fields internal.FieldSet, bc internal.BuildContext) (_ *internal.Unit, err error) {
+	defer derrors.Wrap(&err, "GetUnit(ctx, %q, %q, %q, %q)", um.Path, um.ModulePath, um.Version, bc)
 
 	u := &internal.Unit{UnitMeta: *um}
 	if fields&internal.WithMain != 0 {
-		u, err = db.getUnitWithAllFields(ctx, um)
+		u, err = db.getUnitWithAllFields(ctx, um, bc, fields&internal.WithAllDocumentation != 0)
 		if err != nil {
 			return nil, err
 		}
@@ -207,6 +230,45 @@ func (db *DB) GetUnit(ctx context.Context, um *internal.UnitMeta, fields interna
 	return u, nil
 }
 
+// GetDocumentation returns the documentation of the unit at fullPath in the
+// given module version for bc, including its source, without reading the rest
+// of the unit. If allDocs is true, the documentation for every build context
+// matching bc is returned. It returns no documentation if the unit is not
+// redistributable.
+func (db *DB) GetDocumentation(ctx context.Context, fullPath, modulePath, resolvedVersion string, bc internal.BuildContext, allDocs bool) (_ []*internal.Documentation, err error) {
+	defer derrors.Wrap(&err, "GetDocumentation(ctx, %q, %q, %q, %q, %t)", fullPath, modulePath, resolvedVersion, bc, allDocs)
+
+	var (
+		unitID          int
+		redistributable bool
+	)
+	err = db.db.QueryRow(ctx, `
+		SELECT u.id, u.redistributable
+		FROM units u
+		INNER JOIN paths p ON (p.id = u.path_id)
+		INNER JOIN modules m ON (u.module_id = m.id)
+		WHERE
+			p.path = $1
+			AND m.module_path = $2
+			AND m.version = $3`,
+		fullPath, modulePath, resolvedVersion).Scan(&unitID, &redistributable)
+	switch err {
+	case sql.ErrNoRows:
+		return nil, derrors.NotFound
+	case nil:
+	default:
+		return nil, err
+	}
+	if !redistributable && !db.bypassLicenseCheck {
+		return nil, nil
+	}
+	_, docs, err := db.getDocumentation(ctx, unitID, bc, allDocs)
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
 func (db *DB) getUnitID(ctx context.Context, fullPath, modulePath, resolvedVersion string) (_ int, err error) {
 	defer derrors.Wrap(&err, "getPathID(ctx, %q, %q, %q)", fullPath, modulePath, resolvedVersion)
 	defer middleware.ElapsedStat(ctx, "getPathID")()
@@ -264,6 +326,8 @@ func (db *DB) getPackagesInUnit(ctx context.Context, fullPath, modulePath, resol
 			p.path,
 			u.name,
 			u.redistributable,
+			d.goos,
+			d.goarch,
 			d.synopsis,
 			u.license_types,
 			u.license_paths
@@ -279,10 +343,17 @@ func (db *DB) getPackagesInUnit(ctx context.Context, fullPath, modulePath, resol
 			AND m.version = $2
 			AND u.name != ''
 		ORDER BY path;`
-	var packages []*internal.PackageMeta
+	var (
+		packages []*internal.PackageMeta
+		// A package has a row for each build context it has documentation
+		// for. Keep the synopsis of the build context that sorts first.
+		pathToBuildContext = map[string]internal.BuildContext{}
+		pathToPackage      = map[string]*internal.PackageMeta{}
+	)
 	collect := func(rows *sql.Rows) error {
 		var (
 			pkg          internal.PackageMeta
+			bc           internal.BuildContext
 			licenseTypes []string
 			licensePaths []string
 		)
@@ -290,12 +361,21 @@ func (db *DB) getPackagesInUnit(ctx context.Context, fullPath, modulePath, resol
 			&pkg.Path,
 			&pkg.Name,
 			&pkg.IsRedistributable,
+			database.NullIsEmpty(&bc.GOOS),
+			database.NullIsEmpty(&bc.GOARCH),
 			database.NullIsEmpty(&pkg.Synopsis),
 			pq.Array(&licenseTypes),
 			pq.Array(&licensePaths),
 		); err != nil {
 			return fmt.Errorf("row.Scan(): %v", err)
 		}
+		if p, ok := pathToPackage[pkg.Path]; ok {
+			if internal.CompareBuildContexts(bc, pathToBuildContext[pkg.Path]) < 0 {
+				p.Synopsis = pkg.Synopsis
+				pathToBuildContext[pkg.Path] = bc
+			}
+			return nil
+		}
 		if fullPath == stdlib.ModulePath || pkg.Path == fullPath || strings.HasPrefix(pkg.Path, fullPath+"/") {
 			lics, err := zipLicenseMetadata(licenseTypes, licensePaths)
 			if err != nil {
@@ -303,6 +383,8 @@ func (db *DB) getPackagesInUnit(ctx context.Context, fullPath, modulePath, resol
 			}
 			pkg.Licenses = lics
 			packages = append(packages, &pkg)
+			pathToPackage[pkg.Path] = &pkg
+			pathToBuildContext[pkg.Path] = bc
 		}
 		return nil
 	}
@@ -319,16 +401,13 @@ func (db *DB) getPackagesInUnit(ctx context.Context, fullPath, modulePath, resol
 	return packages, nil
 }
 
-func (db *DB) getUnitWithAllFields(ctx context.Context, um *internal.UnitMeta) (_ *internal.Unit, err error) {
-	defer derrors.Wrap(&err, "getUnitWithAllFields(ctx, %q, %q, %q)", um.Path, um.ModulePath, um.Version)
+func (db *DB) getUnitWithAllFields(ctx context.Context, um *internal.UnitMeta, bc internal.BuildContext, allDocs bool) (_ *internal.Unit, err error) {
+	defer derrors.Wrap(&err, "getUnitWithAllFields(ctx, %q, %q, %q, %q, %t)", um.Path, um.ModulePath, um.Version, bc, allDocs)
 	defer middleware.ElapsedStat(ctx, "getUnitWithAllFields")()
 
 	query := `
         SELECT
-			d.goos,
-			d.goarch,
-			d.synopsis,
-			d.source,
+			u.id,
 			r.file_path,
 			r.contents,
 			COALESCE((
@@ -349,8 +428,6 @@ func (db *DB) getUnitWithAllFields(ctx context.Context, um *internal.UnitMeta) (
 		ON p.id = u.path_id
 		INNER JOIN modules m
 		ON u.module_id = m.id
-		LEFT JOIN documentation d
-		ON d.unit_id = u.id
 		LEFT JOIN readmes r
 		ON r.unit_id = u.id
 		WHERE
@@ -359,15 +436,12 @@ func (db *DB) getUnitWithAllFields(ctx context.Context, um *internal.UnitMeta) (
 			AND m.version = $3;`
 
 	var (
-		d internal.Documentation
-		r internal.Readme
-		u internal.Unit
+		unitID int
+		r      internal.Readme
+		u      internal.Unit
 	)
 	err = db.db.QueryRow(ctx, query, um.Path, um.ModulePath, um.Version).Scan(
-		database.NullIsEmpty(&d.GOOS),
-		database.NullIsEmpty(&d.GOARCH),
-		database.NullIsEmpty(&d.Synopsis),
-		&d.Source,
+		&unitID,
 		database.NullIsEmpty(&r.Filepath),
 		database.NullIsEmpty(&r.Contents),
 		&u.NumImports,
@@ -377,15 +451,16 @@ func (db *DB) getUnitWithAllFields(ctx context.Context, um *internal.UnitMeta) (
 	case sql.ErrNoRows:
 		return nil, derrors.NotFound
 	case nil:
-		if d.GOOS != "" {
-			u.Documentation = &d
-		}
 		if r.Filepath != "" {
 			u.Readme = &r
 		}
 	default:
 		return nil, err
 	}
+	u.BuildContexts, u.Documentation, err = db.getDocumentation(ctx, unitID, bc, allDocs)
+	if err != nil {
+		return nil, err
+	}
 	pkgs, err := db.getPackagesInUnit(ctx, um.Path, um.ModulePath, um.Version)
 	if err != nil {
 		return nil, err
@@ -395,6 +470,87 @@ func (db *DB) getUnitWithAllFields(ctx context.Context, um *internal.UnitMeta) (
 	return &u, nil
 }
 
+// getDocumentation returns the build contexts that the unit with unitID has
+// documentation for, sorted by internal.CompareBuildContexts, along with the
+// documentation for bc. If allDocs is true, the documentation for every
+// build context matching bc is returned instead. The documentation is empty
+// if the unit has none for bc.
+func (db *DB) getDocumentation(ctx context.Context, unitID int, bc internal.BuildContext, allDocs bool) (_ []internal.BuildContext, _ []*internal.Documentation, err error) {
+	defer derrors.Wrap(&err, "getDocumentation(ctx, %d, %q, %t)", unitID, bc, allDocs)
+	defer middleware.ElapsedStat(ctx, "getDocumentation")()
+
+	// Read the synopses first, to avoid reading the source of documentation
+	// that will not be used.
+	var docs []*internal.Documentation
+	collect := func(rows *sql.Rows) error {
+		var d internal.Documentation
+		if err := rows.Scan(&d.GOOS, &d.GOARCH, &d.Synopsis); err != nil {
+			return fmt.Errorf("row.Scan(): %v", err)
+		}
+		docs = append(docs, &d)
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, `
+		SELECT goos, goarch, synopsis
+		FROM documentation
+		WHERE unit_id = $1`, collect, unitID); err != nil {
+		return nil, nil, err
+	}
+	var bcs []internal.BuildContext
+	for _, d := range docs {
+		bcs = append(bcs, d.BuildContext())
+	}
+	internal.SortBuildContexts(bcs)
+	if allDocs {
+		docs = internal.DocumentationMatching(docs, bc)
+	} else if doc := internal.DocumentationForBuildContext(docs, bc); doc != nil {
+		docs = []*internal.Documentation{doc}
+	} else {
+		docs = nil
+	}
+	if err := db.getDocumentationSources(ctx, unitID, docs); err != nil {
+		return nil, nil, err
+	}
+	return bcs, docs, nil
+}
+
+// getDocumentationSources sets the Source field of each of docs, the
+// documentation of the unit with unitID for distinct build contexts, with a
+// single query.
+func (db *DB) getDocumentationSources(ctx context.Context, unitID int, docs []*internal.Documentation) (err error) {
+	defer derrors.Wrap(&err, "getDocumentationSources(ctx, %d)", unitID)
+
+	if len(docs) == 0 {
+		return nil
+	}
+	type goosGoarch struct{ goos, goarch string }
+	args := []interface{}{unitID}
+	var pairs []string
+	byContext := map[goosGoarch]*internal.Documentation{}
+	for _, d := range docs {
+		args = append(args, d.GOOS, d.GOARCH)
+		pairs = append(pairs, fmt.Sprintf("($%d, $%d)", len(args)-1, len(args)))
+		byContext[goosGoarch{d.GOOS, d.GOARCH}] = d
+	}
+	query := fmt.Sprintf(`
+		SELECT goos, goarch, source
+		FROM documentation
+		WHERE unit_id = $1 AND (goos, goarch) IN (%s)`, strings.Join(pairs, ", "))
+	return db.db.RunQuery(ctx, query, func(rows *sql.Rows) error {
+		var (
+			k      goosGoarch
+			source []byte
+		)
+		if err := rows.Scan(&k.goos, &k.goarch, &source); err != nil {
+			return fmt.Errorf("row.Scan(): %v", err)
+		}
+		if d := byContext[k]; d != nil {
+			d.Source = source
+		}
+		return nil
+	}, args...)
+}
+
 type dbPath struct {
 	id              int64
 	path            string
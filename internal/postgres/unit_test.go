@@ -573,7 +573,7 @@ func TestGetUnit(t *testing.T) {
 func checkUnit(ctx context.Context, t *testing.T, um *internal.UnitMeta, want *internal.Unit, experiments ...string) {
 	t.Helper()
 	ctx = experiment.NewContext(ctx, experiments...)
-	got, err := testDB.GetUnit(ctx, um, internal.AllFields)
+	got, err := testDB.GetUnit(ctx, um, internal.AllFields, internal.BuildContextAll)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -626,7 +626,7 @@ func TestGetUnitFieldSet(t *testing.T) {
 	cleanFields := func(u *internal.Unit, fields internal.FieldSet) {
 		// Add/remove fields based on the FieldSet specified.
 		if fields&internal.WithMain != 0 {
-			u.Documentation = sample.Documentation
+			u.Documentation = []*internal.Documentation{sample.Documentation}
 			u.Readme = readme
 			u.NumImports = len(sample.Imports)
 			u.Subdirectories = []*internal.PackageMeta{
@@ -677,7 +677,7 @@ func TestGetUnitFieldSet(t *testing.T) {
 				test.want.Name,
 				test.want.IsRedistributable,
 			)
-			got, err := testDB.GetUnit(ctx, um, test.fields)
+			got, err := testDB.GetUnit(ctx, um, test.fields, internal.BuildContextAll)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -714,7 +714,7 @@ func unit(fullPath, modulePath, version, name string, readme *internal.Readme, s
 	if u.IsPackage() {
 		u.Imports = sample.Imports
 		u.NumImports = len(sample.Imports)
-		u.Documentation = sample.Documentation
+		u.Documentation = []*internal.Documentation{sample.Documentation}
 	}
 	return u
 }
@@ -754,7 +754,7 @@ func TestGetUnitBypass(t *testing.T) {
 			ModulePath: m.ModulePath,
 			Version:    m.Version,
 		}
-		d, err := test.db.GetUnit(ctx, pathInfo, internal.AllFields)
+		d, err := test.db.GetUnit(ctx, pathInfo, internal.AllFields, internal.BuildContextAll)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -50,12 +50,21 @@ func getPathVersions(ctx context.Context, db *DB, path string, versionTypes ...v
 		m.commit_time,
 		m.redistributable,
 		m.has_go_mod,
-		m.source_info
+		m.source_info,
+		mr.rationale IS NOT NULL,
+		COALESCE(mr.rationale, '')
 	FROM modules m
 	INNER JOIN units u
 		ON u.module_id = m.id
-	LEFT JOIN documentation d
-		ON d.unit_id = u.id
+	LEFT JOIN LATERAL (
+		SELECT r.rationale
+		FROM module_retractions r
+		WHERE
+			r.module_path = m.module_path
+			AND m.sort_version BETWEEN r.low_sort_version AND r.high_sort_version
+		ORDER BY r.low_sort_version DESC
+		LIMIT 1
+	) mr ON true
 	WHERE
 		u.v1_path = (
 			SELECT u2.v1_path
@@ -67,7 +76,11 @@ func getPathVersions(ctx context.Context, db *DB, path string, versionTypes ...v
 		)
 		AND version_type in (%s)
 		-- Packages must have documentation source
-		AND (u.name = '' OR d.source IS NOT NULL)
+		AND (u.name = '' OR EXISTS (
+			SELECT 1
+			FROM documentation d
+			WHERE d.unit_id = u.id AND d.source IS NOT NULL
+		))
 	ORDER BY
 		m.incompatible,
 		m.module_path DESC,
@@ -82,10 +95,18 @@ func getPathVersions(ctx context.Context, db *DB, path string, versionTypes ...v
 	query := fmt.Sprintf(baseQuery, versionTypeExpr(versionTypes), queryEnd)
 	var versions []*internal.ModuleInfo
 	collect := func(rows *sql.Rows) error {
-		mi, err := scanModuleInfo(rows.Scan)
+		var (
+			retracted bool
+			rationale string
+		)
+		mi, err := scanModuleInfo(func(dest ...interface{}) error {
+			return rows.Scan(append(dest, &retracted, &rationale)...)
+		})
 		if err != nil {
 			return fmt.Errorf("row.Scan(): %v", err)
 		}
+		mi.Retracted = retracted
+		mi.RetractionRationale = rationale
 		versions = append(versions, mi)
 		return nil
 	}
@@ -129,6 +150,10 @@ func (db *DB) GetLatestInfo(ctx context.Context, unitPath, modulePath string) (l
 		latest.UnitExistsAtMinor, err = db.getLatestMinorModuleVersionInfo(gctx, unitPath, modulePath)
 		return err
 	})
+	group.Go(func() (err error) {
+		latest.Deprecated, latest.DeprecationComment, err = db.getModuleDeprecation(gctx, modulePath)
+		return err
+	})
 
 	if err := group.Wait(); err != nil {
 		return internal.LatestInfo{}, err
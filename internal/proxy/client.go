@@ -32,6 +32,13 @@ type Client struct {
 	// URL of the module proxy web server
 	url string
 
+	// credentials authenticate requests to url. They may be nil.
+	credentials *Credentials
+
+	// routes send requests for some modules to other proxies. See
+	// NewWithRoutes.
+	routes []*endpoint
+
 	// client used for HTTP requests. It is mutable for testing purposes.
 	httpClient *http.Client
 }
@@ -49,7 +56,7 @@ const disableFetchHeader = "Disable-Module-Fetch"
 // New constructs a *Client using the provided url, which is expected to
 // be an absolute URI that can be directly passed to http.Get.
 func New(u string) (_ *Client, err error) {
-	defer derrors.Wrap(&err, "proxy.New(%q)", u)
+	defer derrors.Wrap(&err, "proxy.New(%q)", redactURL(u))
 	return &Client{
 		url:        strings.TrimRight(u, "/"),
 		httpClient: &http.Client{Transport: &ochttp.Transport{}},
@@ -116,9 +123,15 @@ func (c *Client) GetZipSize(ctx context.Context, modulePath, resolvedVersion str
 	if err != nil {
 		return 0, err
 	}
-	res, err := ctxhttp.Head(ctx, c.httpClient, url)
+	req, err := http.NewRequest("HEAD", url, nil)
+	if err != nil {
+		return 0, err
+	}
+	_, creds := c.endpointFor(modulePath)
+	creds.setAuth(req)
+	res, err := ctxhttp.Do(ctx, c.httpClient, req)
 	if err != nil {
-		return 0, fmt.Errorf("ctxhttp.Head(ctx, client, %q): %v", url, err)
+		return 0, fmt.Errorf("ctxhttp.Do(ctx, client, HEAD %q): %v", url, err)
 	}
 	defer res.Body.Close()
 	if err := responseError(res, false); err != nil {
@@ -142,17 +155,18 @@ func (c *Client) escapedURL(modulePath, requestedVersion, suffix string) (_ stri
 	if err != nil {
 		return "", fmt.Errorf("path: %v: %w", err, derrors.InvalidArgument)
 	}
+	proxyURL, _ := c.endpointFor(modulePath)
 	if requestedVersion == internal.LatestVersion {
 		if suffix != "info" {
 			return "", fmt.Errorf("cannot ask for latest with suffix %q", suffix)
 		}
-		return fmt.Sprintf("%s/%s/@latest", c.url, escapedPath), nil
+		return fmt.Sprintf("%s/%s/@latest", proxyURL, escapedPath), nil
 	}
 	escapedVersion, err := module.EscapeVersion(requestedVersion)
 	if err != nil {
 		return "", fmt.Errorf("version: %v: %w", err, derrors.InvalidArgument)
 	}
-	return fmt.Sprintf("%s/%s/@v/%s.%s", c.url, escapedPath, escapedVersion, suffix), nil
+	return fmt.Sprintf("%s/%s/@v/%s.%s", proxyURL, escapedPath, escapedVersion, suffix), nil
 }
 
 func (c *Client) readBody(ctx context.Context, modulePath, requestedVersion, suffix string, disableFetch bool) (_ []byte, err error) {
@@ -162,8 +176,9 @@ func (c *Client) readBody(ctx context.Context, modulePath, requestedVersion, suf
 	if err != nil {
 		return nil, err
 	}
+	_, creds := c.endpointFor(modulePath)
 	var data []byte
-	err = c.executeRequest(ctx, u, disableFetch, func(body io.Reader) error {
+	err = c.executeRequest(ctx, u, creds, disableFetch, func(body io.Reader) error {
 		var err error
 		data, err = ioutil.ReadAll(body)
 		return err
@@ -181,7 +196,8 @@ func (c *Client) ListVersions(ctx context.Context, modulePath string) ([]string,
 	if err != nil {
 		return nil, fmt.Errorf("module.EscapePath(%q): %w", modulePath, derrors.InvalidArgument)
 	}
-	u := fmt.Sprintf("%s/%s/@v/list", c.url, escapedPath)
+	proxyURL, creds := c.endpointFor(modulePath)
+	u := fmt.Sprintf("%s/%s/@v/list", proxyURL, escapedPath)
 	var versions []string
 	collect := func(body io.Reader) error {
 		scanner := bufio.NewScanner(body)
@@ -190,15 +206,15 @@ func (c *Client) ListVersions(ctx context.Context, modulePath string) ([]string,
 		}
 		return scanner.Err()
 	}
-	if err := c.executeRequest(ctx, u, false, collect); err != nil {
+	if err := c.executeRequest(ctx, u, creds, false, collect); err != nil {
 		return nil, err
 	}
 	return versions, nil
 }
 
-// executeRequest executes an HTTP GET request for u, then calls the bodyFunc
-// on the response body, if no error occurred.
-func (c *Client) executeRequest(ctx context.Context, u string, disableFetch bool, bodyFunc func(body io.Reader) error) (err error) {
+// executeRequest executes an HTTP GET request for u, authenticated with creds,
+// then calls the bodyFunc on the response body, if no error occurred.
+func (c *Client) executeRequest(ctx context.Context, u string, creds *Credentials, disableFetch bool, bodyFunc func(body io.Reader) error) (err error) {
 	defer func() {
 		if ctx.Err() != nil {
 			err = fmt.Errorf("%v: %w", err, derrors.ProxyTimedOut)
@@ -213,6 +229,7 @@ func (c *Client) executeRequest(ctx context.Context, u string, disableFetch bool
 	if disableFetch {
 		req.Header.Set(disableFetchHeader, "true")
 	}
+	creds.setAuth(req)
 	r, err := ctxhttp.Do(ctx, c.httpClient, req)
 	if err != nil {
 		return fmt.Errorf("ctxhttp.Do(ctx, client, %q): %v", u, err)
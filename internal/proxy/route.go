@@ -0,0 +1,250 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proxy
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// A Route sends requests for the modules whose paths match Patterns to a
+// proxy other than the client's default one, such as a proxy serving private
+// modules.
+type Route struct {
+	// Patterns is a comma-separated list of glob patterns, in the syntax of
+	// GOPRIVATE and GONOSUMDB, that are matched against prefixes of module
+	// paths.
+	Patterns string
+
+	// URL of the module proxy web server. It must not contain credentials.
+	URL string
+
+	// Credentials authenticate requests to URL. If nil, requests are sent
+	// without credentials.
+	Credentials *Credentials
+}
+
+// Credentials authenticate requests to a proxy, either with a bearer token
+// or with HTTP basic authentication. They are never included in URLs or
+// error messages.
+type Credentials struct {
+	// Token is sent in an "Authorization: Bearer" header. If it is set, the
+	// username and password are ignored.
+	Token string
+
+	Username, Password string
+}
+
+// String returns a description of c that does not reveal the secrets in it.
+func (c *Credentials) String() string {
+	switch {
+	case c == nil:
+		return "none"
+	case c.Token != "":
+		return "token(redacted)"
+	default:
+		return fmt.Sprintf("basic(%s:redacted)", c.Username)
+	}
+}
+
+// GoString is like String, so that credentials printed with %#v are redacted
+// too.
+func (c *Credentials) GoString() string {
+	return c.String()
+}
+
+// setAuth adds the authentication header for c, if any, to req.
+func (c *Credentials) setAuth(req *http.Request) {
+	switch {
+	case c == nil:
+	case c.Token != "":
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	case c.Username != "" || c.Password != "":
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+}
+
+// endpoint is a proxy that a Client sends requests to.
+type endpoint struct {
+	patterns    string
+	url         string
+	credentials *Credentials
+}
+
+// NewWithRoutes constructs a *Client that sends requests for modules matching
+// one of routes to the route's proxy, and all other requests to the proxy at
+// u. Routes are tried in order, and the first match wins. Credentials for u
+// may be given in defaultCredentials, which may be nil.
+func NewWithRoutes(u string, defaultCredentials *Credentials, routes []*Route) (_ *Client, err error) {
+	defer derrors.Wrap(&err, "proxy.NewWithRoutes(%q, %d routes)", redactURL(u), len(routes))
+
+	if err := checkURL(u); err != nil {
+		return nil, err
+	}
+	c, err := New(u)
+	if err != nil {
+		return nil, err
+	}
+	c.credentials = defaultCredentials
+	for _, r := range routes {
+		if r.Patterns == "" {
+			return nil, fmt.Errorf("route for %q has no patterns: %w", redactURL(r.URL), derrors.InvalidArgument)
+		}
+		if err := checkURL(r.URL); err != nil {
+			return nil, err
+		}
+		c.routes = append(c.routes, &endpoint{
+			patterns:    r.Patterns,
+			url:         strings.TrimRight(r.URL, "/"),
+			credentials: r.Credentials,
+		})
+	}
+	return c, nil
+}
+
+// endpointFor returns the URL of the proxy to use for modulePath, and the
+// credentials to send to it.
+func (c *Client) endpointFor(modulePath string) (string, *Credentials) {
+	for _, r := range c.routes {
+		if module.MatchPrefixPatterns(r.patterns, modulePath) {
+			return r.url, r.credentials
+		}
+	}
+	return c.url, c.credentials
+}
+
+// checkURL returns an error if u is not an absolute URL, or if it contains
+// credentials, which would otherwise end up in logs.
+func checkURL(u string) error {
+	pu, err := url.Parse(u)
+	if err != nil {
+		// The error from url.Parse contains the URL.
+		return fmt.Errorf("invalid proxy URL %q: %w", redactURL(u), derrors.InvalidArgument)
+	}
+	if pu.User != nil {
+		return fmt.Errorf("proxy URL %q contains credentials; use a .netrc file or a secret instead: %w",
+			redactURL(u), derrors.InvalidArgument)
+	}
+	if !pu.IsAbs() || pu.Host == "" {
+		return fmt.Errorf("proxy URL %q is not absolute: %w", u, derrors.InvalidArgument)
+	}
+	return nil
+}
+
+// redactURL returns u with any password in it replaced, so that it can be
+// logged.
+func redactURL(u string) string {
+	pu, err := url.Parse(u)
+	if err != nil {
+		return "<unparseable URL>"
+	}
+	if pu.User == nil {
+		return u
+	}
+	if _, ok := pu.User.Password(); ok {
+		pu.User = url.UserPassword(pu.User.Username(), "xxxxx")
+	}
+	return pu.String()
+}
+
+// ReadNetrc reads the .netrc file at filename and returns the credentials in
+// it, keyed by machine name. The "default" entry and macro definitions are
+// ignored.
+func ReadNetrc(filename string) (_ map[string]*Credentials, err error) {
+	defer derrors.Wrap(&err, "proxy.ReadNetrc(%q)", filename)
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return parseNetrc(string(data)), nil
+}
+
+// parseNetrc parses the contents of a .netrc file. If a machine appears more
+// than once, the first entry is used, as curl and the go command do.
+func parseNetrc(data string) map[string]*Credentials {
+	creds := map[string]*Credentials{}
+	var (
+		machine string
+		cur     *Credentials
+		inMacro bool
+	)
+	flush := func() {
+		if machine != "" && cur != nil {
+			if _, ok := creds[machine]; !ok {
+				creds[machine] = cur
+			}
+		}
+		machine, cur = "", nil
+	}
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if inMacro {
+			// A macro definition ends at a blank line.
+			if strings.TrimSpace(line) == "" {
+				inMacro = false
+			}
+			continue
+		}
+		f := strings.Fields(line)
+	tokens:
+		for i := 0; i < len(f); i++ {
+			if strings.HasPrefix(f[i], "#") {
+				break
+			}
+			switch f[i] {
+			case "default":
+				flush()
+			case "macdef":
+				// The rest of the line is the macro name, and the following
+				// lines are its body.
+				inMacro = true
+				break tokens
+			case "machine", "login", "password", "account":
+				if i+1 >= len(f) {
+					continue
+				}
+				i++
+				switch f[i-1] {
+				case "machine":
+					flush()
+					machine, cur = f[i], &Credentials{}
+				case "login":
+					if cur != nil {
+						cur.Username = f[i]
+					}
+				case "password":
+					if cur != nil {
+						cur.Password = f[i]
+					}
+				}
+			}
+		}
+	}
+	flush()
+	return creds
+}
+
+// NetrcCredentials returns the credentials in netrc for the host of the proxy
+// at u, or nil if there are none.
+func NetrcCredentials(netrc map[string]*Credentials, u string) (*Credentials, error) {
+	pu, err := url.Parse(u)
+	if err != nil {
+		return nil, errors.New("invalid proxy URL")
+	}
+	if c, ok := netrc[pu.Host]; ok {
+		return c, nil
+	}
+	return netrc[pu.Hostname()], nil
+}
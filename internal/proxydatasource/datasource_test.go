@@ -78,11 +78,11 @@ var (
 			IsRedistributable: true,
 		},
 		Imports: []string{"net/http"},
-		Documentation: &internal.Documentation{
+		Documentation: []*internal.Documentation{{
 			Synopsis: "Package baz provides a helpful constant.",
 			GOOS:     "linux",
 			GOARCH:   "amd64",
-		},
+		}},
 	}
 	wantModuleInfo = internal.ModuleInfo{
 		ModulePath:        "foo.com/bar",
@@ -173,6 +173,44 @@ func TestDataSource_GetUnitMeta(t *testing.T) {
 	}
 }
 
+func TestDataSource_GetUnitBuildContext(t *testing.T) {
+	ctx, ds, teardown := setup(t)
+	defer teardown()
+
+	um := &internal.UnitMeta{Path: "foo.com/bar/baz", ModulePath: "foo.com/bar", Version: "v1.2.0"}
+	for _, test := range []struct {
+		name   string
+		fields internal.FieldSet
+		bc     internal.BuildContext
+		want   []string // GOOS/GOARCH of the documentation returned
+	}{
+		{"default", internal.WithMain, internal.BuildContextAll, []string{"linux/amd64"}},
+		{"windows", internal.WithMain, internal.BuildContext{GOOS: "windows", GOARCH: "amd64"}, []string{"windows/amd64"}},
+		{"missing", internal.WithMain, internal.BuildContext{GOOS: "freebsd", GOARCH: "amd64"}, nil},
+		{"all", internal.WithMain | internal.WithAllDocumentation, internal.BuildContextAll,
+			[]string{"linux/amd64", "windows/amd64", "darwin/amd64", "js/wasm"}},
+		{"all amd64", internal.WithMain | internal.WithAllDocumentation, internal.BuildContext{GOARCH: "amd64"},
+			[]string{"linux/amd64", "windows/amd64", "darwin/amd64"}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			u, err := ds.GetUnit(ctx, um, test.fields, test.bc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, d := range u.Documentation {
+				got = append(got, d.GOOS+"/"+d.GOARCH)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+			if len(u.BuildContexts) != len(internal.DefaultBuildContexts) {
+				t.Errorf("got %d build contexts, want %d", len(u.BuildContexts), len(internal.DefaultBuildContexts))
+			}
+		})
+	}
+}
+
 func TestDataSource_Bypass(t *testing.T) {
 	for _, bypass := range []bool{false, true} {
 		t.Run(fmt.Sprintf("bypass=%t", bypass), func(t *testing.T) {
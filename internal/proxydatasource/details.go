@@ -8,6 +8,7 @@ package proxydatasource
 
 import (
 	"context"
+	"fmt"
 
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/derrors"
@@ -15,9 +16,13 @@ import (
 )
 
 // GetUnit returns information about a directory at a path.
-func (ds *DataSource) GetUnit(ctx context.Context, um *internal.UnitMeta, field internal.FieldSet) (_ *internal.Unit, err error) {
-	defer derrors.Wrap(&err, "GetUnit(%q, %q, %q)", um.Path, um.ModulePath, um.Version)
-	return ds.getUnit(ctx, um.Path, um.ModulePath, um.Version)
+func (ds *DataSource) GetUnit(ctx context.Context, um *internal.UnitMeta, field internal.FieldSet, bc internal.BuildContext) (_ *internal.Unit, err error) {
+	defer derrors.Wrap(&err, "GetUnit(%q, %q, %q, %q)", um.Path, um.ModulePath, um.Version, bc)
+	u, err := ds.getUnit(ctx, um.Path, um.ModulePath, um.Version)
+	if err != nil {
+		return nil, err
+	}
+	return u.ForBuildContext(bc, field), nil
 }
 
 // GetModuleInfo returns the ModuleInfo as fetched from the proxy for module
@@ -72,6 +77,34 @@ func (ds *DataSource) GetNestedModules(ctx context.Context, modulePath string) (
 	return nil, nil
 }
 
+// GetSourceFile returns the contents of a .go file of the module.
+func (ds *DataSource) GetSourceFile(ctx context.Context, modulePath, resolvedVersion, filePath string) (_ []byte, err error) {
+	defer derrors.Wrap(&err, "GetSourceFile(%q, %q, %q)", modulePath, resolvedVersion, filePath)
+	m, err := ds.getModule(ctx, modulePath, resolvedVersion)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range m.SourceFiles {
+		if f.Path == filePath {
+			return f.Contents, nil
+		}
+	}
+	return nil, fmt.Errorf("%s not found: %w", filePath, derrors.NotFound)
+}
+
+// GetGoMod returns the directives of the go.mod file of the module.
+func (ds *DataSource) GetGoMod(ctx context.Context, modulePath, resolvedVersion string) (_ *internal.GoMod, err error) {
+	defer derrors.Wrap(&err, "GetGoMod(%q, %q)", modulePath, resolvedVersion)
+	m, err := ds.getModule(ctx, modulePath, resolvedVersion)
+	if err != nil {
+		return nil, err
+	}
+	if m.GoMod == nil {
+		return nil, fmt.Errorf("%s@%s has no go.mod file: %w", modulePath, resolvedVersion, derrors.NotFound)
+	}
+	return m.GoMod, nil
+}
+
 // GetModuleReadme is unimplemented.
 func (ds *DataSource) GetModuleReadme(ctx context.Context, modulePath, resolvedVersion string) (*internal.Readme, error) {
 	return nil, nil
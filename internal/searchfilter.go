@@ -0,0 +1,51 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+// A SearchFilterKind is the kind of a search filter. It is written before
+// the colon of the filter in a search query, as in "license:MIT".
+type SearchFilterKind string
+
+const (
+	// SearchFilterLicense matches packages with a license of the given type,
+	// ignoring case.
+	SearchFilterLicense SearchFilterKind = "license"
+	// SearchFilterModule matches the packages of the module with the given
+	// path.
+	SearchFilterModule SearchFilterKind = "module"
+	// SearchFilterIs matches packages with a property: one of the
+	// SearchFilterIs* values.
+	SearchFilterIs SearchFilterKind = "is"
+	// SearchFilterGOOS matches packages that have documentation for the
+	// given GOOS.
+	SearchFilterGOOS SearchFilterKind = "goos"
+)
+
+// Values of SearchFilterIs filters.
+const (
+	// SearchFilterIsCommand matches commands: packages named main.
+	SearchFilterIsCommand = "command"
+	// SearchFilterIsInternal matches packages with an internal path
+	// element, which can only be imported from nearby packages.
+	SearchFilterIsInternal = "internal"
+)
+
+// A SearchFilter narrows search results to the packages that match it, or,
+// if Negate is set, to those that do not.
+type SearchFilter struct {
+	Kind   SearchFilterKind
+	Value  string
+	Negate bool
+}
+
+// String returns the filter as it is written in a search query, as in
+// "-is:internal".
+func (f SearchFilter) String() string {
+	s := string(f.Kind) + ":" + f.Value
+	if f.Negate {
+		s = "-" + s
+	}
+	return s
+}
@@ -63,6 +63,25 @@ func (i *Info) RepoURL() string {
 	})
 }
 
+// CloneURL returns the URL of the repository itself, as opposed to that of its
+// home page, for use by version control tools.
+func (i *Info) CloneURL() string {
+	if i == nil {
+		return ""
+	}
+	return i.repoURL
+}
+
+// ModuleDir returns the directory of the module relative to the repository
+// root. For a module at major version 2 or higher, the directory omits the
+// "/vN" suffix unless it could be shown to exist.
+func (i *Info) ModuleDir() string {
+	if i == nil {
+		return ""
+	}
+	return i.moduleDir
+}
+
 // ModuleURL returns a URL for the home page of the module.
 func (i *Info) ModuleURL() string {
 	return i.DirectoryURL("")
@@ -646,6 +665,11 @@ var (
 		Raw:       "{repo}/raw/{commit}/{file}",
 	}
 
+	viewerURLTemplates = urlTemplates{
+		File: "{repo}/{file}",
+		Line: "{repo}/{file}#line-{line}",
+	}
+
 	bitbucketURLTemplates = urlTemplates{
 		Directory: "{repo}/src/{commit}/{dir}",
 		File:      "{repo}/src/{commit}/{file}",
@@ -712,6 +736,17 @@ func NewGitHubInfo(repoURL, moduleDir, commit string) *Info {
 	}
 }
 
+// NewViewerInfo returns an Info whose file and line URLs refer to a source
+// viewer that serves the files of a single module version under urlPrefix,
+// such as "/src/example.com/m@v1.0.0". Its line URLs use anchors of the form
+// "#line-N". It has no directory URLs.
+func NewViewerInfo(urlPrefix string) *Info {
+	return &Info{
+		repoURL:   urlPrefix,
+		templates: viewerURLTemplates,
+	}
+}
+
 // NewStdlibInfo returns a source.Info for the standard library at the given
 // semantic version. It panics if the version does not correspond to a Go release
 // tag. It is for testing only.
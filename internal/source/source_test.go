@@ -907,3 +907,16 @@ func TestURLTemplates(t *testing.T) {
 		check(p.templates.Raw, "commit", "file")
 	}
 }
+
+func TestNewViewerInfo(t *testing.T) {
+	info := NewViewerInfo("/src/example.com/m@v1.0.0")
+	if got, want := info.FileURL("a/b.go"), "/src/example.com/m@v1.0.0/a/b.go"; got != want {
+		t.Errorf("FileURL = %q, want %q", got, want)
+	}
+	if got, want := info.LineURL("a/b.go", 7), "/src/example.com/m@v1.0.0/a/b.go#line-7"; got != want {
+		t.Errorf("LineURL = %q, want %q", got, want)
+	}
+	if got := info.DirectoryURL("a"); got != "" {
+		t.Errorf("DirectoryURL = %q, want empty", got)
+	}
+}
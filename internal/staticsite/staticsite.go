@@ -0,0 +1,547 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package staticsite generates a self-contained static HTML site documenting
+// a module, which can be served by any file server.
+package staticsite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/safehtml"
+	"github.com/google/safehtml/template"
+	"github.com/google/safehtml/uncheckedconversions"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/frontend"
+	"golang.org/x/pkgsite/internal/godoc"
+	"golang.org/x/pkgsite/internal/godoc/dochtml"
+	"golang.org/x/pkgsite/internal/stdlib"
+)
+
+// DefaultExternalURL is the default site that packages outside the module
+// are linked to.
+const DefaultExternalURL = "https://pkg.go.dev"
+
+// Config configures Generate and GenerateModules.
+type Config struct {
+	// OutDir is the directory the site is written to. It is created if it
+	// does not exist.
+	OutDir string
+	// StaticPath is the directory containing the templates and static
+	// assets, usually content/static.
+	StaticPath template.TrustedSource
+	// ExternalURL is the site that packages outside the site are linked to.
+	// If empty, DefaultExternalURL is used.
+	ExternalURL string
+	// BypassLicenseCheck causes the documentation of packages that are not
+	// redistributable to be displayed.
+	BypassLicenseCheck bool
+}
+
+// The layout of a site generated for a single module is:
+//
+//   index.html            the module root, listing all packages
+//   <dir>/index.html      each directory of the module
+//   licenses.html         the licenses of the module
+//   static/css, static/img
+//
+// A site generated for several modules has the same layout for each module
+// in the directory named by its module path, with an index.html listing the
+// modules and a single static directory at the root.
+//
+// All links within the site are relative, so it can be served from any URL
+// prefix or opened from the file system.
+const (
+	indexFile    = "index.html"
+	licensesFile = "licenses.html"
+	staticDir    = "static"
+)
+
+// staticAssetDirs are the subdirectories of the static path that are copied
+// to the site.
+var staticAssetDirs = []string{"css", "img"}
+
+// Generate writes a static site documenting m to cfg.OutDir. Every unit of m
+// has a page with its README and documentation, and a list of the packages
+// beneath it. Links to other packages of m stay within the site; links to
+// other packages go to cfg.ExternalURL.
+func Generate(ctx context.Context, m *internal.Module, cfg Config) (err error) {
+	defer derrors.Wrap(&err, "staticsite.Generate(%q, %q)", m.ModulePath, cfg.OutDir)
+	return generate(ctx, []*internal.Module{m}, false, cfg)
+}
+
+// GenerateModules writes a static site documenting each of ms to cfg.OutDir,
+// in the directory given by ModuleDir, along with an index page listing the
+// modules. Links to packages of any of ms stay within the site.
+func GenerateModules(ctx context.Context, ms []*internal.Module, cfg Config) (err error) {
+	defer derrors.Wrap(&err, "staticsite.GenerateModules(%q)", cfg.OutDir)
+	return generate(ctx, ms, true, cfg)
+}
+
+// ModuleDir returns the directory, relative to the root of a site written by
+// GenerateModules, that holds the pages of the module with the given path.
+func ModuleDir(modulePath string) string {
+	return modulePath
+}
+
+// UnitFile returns the path of the page of a unit, relative to the root of a
+// site written by GenerateModules.
+func UnitFile(modulePath, unitPath string) string {
+	return path.Join(ModuleDir(modulePath), unitDir(modulePath, unitPath), indexFile)
+}
+
+func generate(ctx context.Context, ms []*internal.Module, multi bool, cfg Config) error {
+	if cfg.OutDir == "" {
+		return errors.New("missing output directory")
+	}
+	if cfg.ExternalURL == "" {
+		cfg.ExternalURL = DefaultExternalURL
+	}
+	cfg.ExternalURL = strings.TrimSuffix(cfg.ExternalURL, "/")
+	dochtml.LoadTemplates(template.TrustedSourceJoin(cfg.StaticPath, template.TrustedSourceFromConstant("html/doc")))
+	templates, err := parseTemplates(cfg.StaticPath)
+	if err != nil {
+		return err
+	}
+
+	g := &generator{
+		cfg:            cfg,
+		multi:          multi,
+		templates:      templates,
+		units:          map[string]*internal.Unit{},
+		licenseAnchors: map[string]map[string]safehtml.Identifier{},
+	}
+	for _, m := range ms {
+		if cfg.BypassLicenseCheck {
+			m.IsRedistributable = true
+			for _, u := range m.Units {
+				u.IsRedistributable = true
+			}
+		} else {
+			m.RemoveNonRedistributableData()
+		}
+		for _, u := range m.Units {
+			g.units[u.Path] = u
+		}
+		var licensePaths []string
+		for _, l := range m.Licenses {
+			licensePaths = append(licensePaths, l.FilePath)
+		}
+		g.licenseAnchors[m.ModulePath] = licenseAnchors(licensePaths)
+	}
+	for _, m := range ms {
+		sort.Slice(m.Units, func(i, j int) bool { return m.Units[i].Path < m.Units[j].Path })
+		for _, u := range m.Units {
+			if err := g.writeUnit(ctx, m, u); err != nil {
+				return err
+			}
+		}
+		if err := g.writeLicenses(m); err != nil {
+			return err
+		}
+	}
+	if multi {
+		if err := g.writeModules(ms); err != nil {
+			return err
+		}
+	}
+	for _, dir := range staticAssetDirs {
+		if err := copyDir(filepath.Join(cfg.StaticPath.String(), dir), filepath.Join(cfg.OutDir, staticDir, dir)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseTemplates parses the templates of each page of the site.
+func parseTemplates(staticPath template.TrustedSource) (map[string]*template.Template, error) {
+	tsc := template.TrustedSourceFromConstant
+	join := template.TrustedSourceJoin
+	dir := join(staticPath, tsc("html/staticsite"))
+
+	templates := map[string]*template.Template{}
+	for _, page := range []template.TrustedSource{tsc("unit.tmpl"), tsc("licenses.tmpl"), tsc("modules.tmpl")} {
+		t, err := template.New("base.tmpl").ParseFilesFromTrustedSources(join(dir, tsc("base.tmpl")), join(dir, page))
+		if err != nil {
+			return nil, fmt.Errorf("ParseFilesFromTrustedSources: %v", err)
+		}
+		templates[page.String()] = t
+	}
+	return templates, nil
+}
+
+// A generator writes the pages of a site.
+type generator struct {
+	cfg Config
+	// multi reports whether the site documents several modules, each in its
+	// own directory.
+	multi     bool
+	templates map[string]*template.Template
+	units     map[string]*internal.Unit // by path, for all modules
+	// licenseAnchors maps module paths to a map from the file paths of the
+	// module's licenses to their anchors on its licenses page.
+	licenseAnchors map[string]map[string]safehtml.Identifier
+}
+
+// basePage holds the fields common to all pages.
+type basePage struct {
+	Title       string
+	ModulePath  string
+	Version     string
+	Stylesheets []safehtml.TrustedResourceURL
+	// IndexURL is the URL of the page listing the modules of the site,
+	// relative to the page. It is empty if the site has a single module.
+	IndexURL string
+	// RootURL is the URL of the module root, relative to the page.
+	RootURL string
+	// LicensesURL is the URL of the licenses page, relative to the page.
+	LicensesURL string
+}
+
+// UnitPage is the page of a unit.
+type UnitPage struct {
+	basePage
+	Path              string
+	Name              string
+	IsPackage         bool
+	IsRedistributable bool
+	// Breadcrumbs are links to the units containing this one, starting with
+	// the module root.
+	Breadcrumbs   []*Link
+	Licenses      []*Link
+	Readme        safehtml.HTML
+	ReadmeOutline []*frontend.Heading
+	DocBody       safehtml.HTML
+	DocOutline    safehtml.HTML
+	Directories   []*Directory
+}
+
+// A Link is a link to another page of the site.
+type Link struct {
+	Text string
+	URL  string
+}
+
+// A Directory is a package beneath a unit.
+type Directory struct {
+	Suffix   string
+	URL      string
+	Synopsis string
+}
+
+// LicensesPage is the page listing the licenses of a module.
+type LicensesPage struct {
+	basePage
+	Licenses []*License
+}
+
+// A License is a license of a module.
+type License struct {
+	Anchor   safehtml.Identifier
+	Types    string
+	FilePath string
+	Contents string
+}
+
+// ModulesPage is the page listing the modules of a site with several
+// modules.
+type ModulesPage struct {
+	basePage
+	Modules []*ModuleLink
+}
+
+// A ModuleLink is a link to the root of a module.
+type ModuleLink struct {
+	ModulePath string
+	Version    string
+	URL        string
+}
+
+// unitDir returns the directory holding the page of the unit with the given
+// path, relative to the directory of its module.
+func unitDir(modulePath, unitPath string) string {
+	if unitPath == modulePath {
+		return ""
+	}
+	if modulePath == stdlib.ModulePath {
+		return unitPath
+	}
+	return internal.Suffix(unitPath, modulePath)
+}
+
+// moduleDir returns the directory of the site holding the pages of the
+// module with the given path.
+func (g *generator) moduleDir(modulePath string) string {
+	if !g.multi {
+		return ""
+	}
+	return ModuleDir(modulePath)
+}
+
+// unitDir returns the directory of the site holding the page of the unit u.
+func (g *generator) unitDir(u *internal.Unit) string {
+	return path.Join(g.moduleDir(u.ModulePath), unitDir(u.ModulePath, u.Path))
+}
+
+// relativeURL returns the URL of the site file target, relative to a page in
+// the site directory fromDir. Both are slash-separated and relative to the
+// site root.
+func relativeURL(fromDir, target string) string {
+	var from []string
+	if fromDir != "" {
+		from = strings.Split(fromDir, "/")
+	}
+	to := strings.Split(target, "/")
+	// Skip the directories in common; the last element of to is a file.
+	n := 0
+	for n < len(from) && n < len(to)-1 && from[n] == to[n] {
+		n++
+	}
+	return strings.Repeat("../", len(from)-n) + strings.Join(to[n:], "/")
+}
+
+// unitURL returns the URL of the page of the unit u, relative to a page in
+// fromDir.
+func (g *generator) unitURL(fromDir string, u *internal.Unit) string {
+	return relativeURL(fromDir, path.Join(g.unitDir(u), indexFile))
+}
+
+// packageURL returns the URL of the documentation of the package with the
+// given import path, relative to a page in fromDir.
+func (g *generator) packageURL(fromDir, importPath string) string {
+	if u, ok := g.units[importPath]; ok && u.IsPackage() {
+		return g.unitURL(fromDir, u)
+	}
+	return g.cfg.ExternalURL + "/" + importPath
+}
+
+// newBasePage returns the basePage of a page in dir. If m is nil, the page
+// does not belong to a module.
+func (g *generator) newBasePage(dir, title string, m *internal.Module) basePage {
+	var stylesheets []safehtml.TrustedResourceURL
+	for _, name := range []string{"stylesheet.css", "unit_details.css", "staticsite.css"} {
+		// The URL is built from constant parts, so it is safe to load.
+		stylesheets = append(stylesheets, uncheckedconversions.TrustedResourceURLFromStringKnownToSatisfyTypeContract(
+			relativeURL(dir, path.Join(staticDir, "css", name))))
+	}
+	p := basePage{
+		Title:       title,
+		Stylesheets: stylesheets,
+	}
+	if g.multi {
+		p.IndexURL = relativeURL(dir, indexFile)
+	}
+	if m != nil {
+		moduleDir := g.moduleDir(m.ModulePath)
+		p.ModulePath = m.ModulePath
+		p.Version = m.Version
+		p.RootURL = relativeURL(dir, path.Join(moduleDir, indexFile))
+		p.LicensesURL = relativeURL(dir, path.Join(moduleDir, licensesFile))
+	}
+	return p
+}
+
+// writeUnit writes the page of u, a unit of m.
+func (g *generator) writeUnit(ctx context.Context, m *internal.Module, u *internal.Unit) (err error) {
+	defer derrors.Wrap(&err, "writeUnit(%q)", u.Path)
+
+	dir := g.unitDir(u)
+	page := &UnitPage{
+		basePage:          g.newBasePage(dir, u.Path, m),
+		Path:              u.Path,
+		Name:              u.Name,
+		IsPackage:         u.IsPackage(),
+		IsRedistributable: u.IsRedistributable,
+	}
+	if page.Name == "" {
+		page.Name = path.Base(u.Path)
+	}
+	for p := path.Dir(u.Path); isInModule(m.ModulePath, p); p = path.Dir(p) {
+		if pu, ok := g.units[p]; ok {
+			page.Breadcrumbs = append([]*Link{{Text: p, URL: g.unitURL(dir, pu)}}, page.Breadcrumbs...)
+		}
+	}
+	anchors := g.licenseAnchors[m.ModulePath]
+	for _, l := range u.Licenses {
+		page.Licenses = append(page.Licenses, &Link{
+			Text: strings.Join(l.Types, ", "),
+			URL:  page.LicensesURL + "#" + anchors[l.FilePath].String(),
+		})
+	}
+	readme, err := frontend.ProcessReadme(ctx, u)
+	if err != nil {
+		return err
+	}
+	page.Readme = readme.HTML
+	page.ReadmeOutline = readme.Outline
+	if u.IsPackage() {
+		if err := g.renderDoc(ctx, u, dir, page); err != nil {
+			return err
+		}
+	}
+	for _, d := range m.Units {
+		if d.IsPackage() && d.Path != u.Path && (u.Path == m.ModulePath || strings.HasPrefix(d.Path, u.Path+"/")) {
+			dd := &Directory{
+				Suffix: internal.Suffix(d.Path, u.Path),
+				URL:    g.unitURL(dir, d),
+			}
+			if len(d.Documentation) > 0 {
+				dd.Synopsis = d.Documentation[0].Synopsis
+			}
+			page.Directories = append(page.Directories, dd)
+		}
+	}
+	return g.writePage(path.Join(dir, indexFile), "unit.tmpl", page)
+}
+
+// isInModule reports whether the unit path p is in the module with the given
+// path.
+func isInModule(modulePath, p string) bool {
+	if modulePath == stdlib.ModulePath {
+		return p != "." && p != "/"
+	}
+	return p == modulePath || strings.HasPrefix(p, modulePath+"/")
+}
+
+// renderDoc renders the documentation of the package u, whose page is in dir,
+// into page.
+func (g *generator) renderDoc(ctx context.Context, u *internal.Unit, dir string, page *UnitPage) error {
+	doc := internal.DocumentationForBuildContext(u.Documentation, internal.BuildContextAll)
+	if doc == nil || len(doc.Source) == 0 {
+		return nil
+	}
+	docPkg, err := godoc.DecodePackage(doc.Source)
+	if err != nil {
+		return err
+	}
+	modInfo := &godoc.ModuleInfo{
+		ModulePath:      u.ModulePath,
+		ResolvedVersion: u.Version,
+		PackageURL: func(importPath string) string {
+			return g.packageURL(dir, importPath)
+		},
+	}
+	var innerPath string
+	if u.ModulePath == stdlib.ModulePath {
+		innerPath = u.Path
+	} else if u.Path != u.ModulePath {
+		innerPath = u.Path[len(u.ModulePath)+1:]
+	}
+	parts, err := docPkg.RenderParts(ctx, innerPath, u.SourceInfo, modInfo)
+	if err != nil {
+		return err
+	}
+	page.DocBody = parts.Body
+	page.DocOutline = parts.Outline
+	return nil
+}
+
+// licenseAnchors returns the anchors of the licenses with the given file
+// paths. As on the licenses tab of the frontend, anchors are assigned in the
+// order of the sorted paths.
+func licenseAnchors(paths []string) map[string]safehtml.Identifier {
+	sort.Strings(paths)
+	anchors := map[string]safehtml.Identifier{}
+	for i, p := range paths {
+		anchors[p] = safehtml.IdentifierFromConstantPrefix("lic", strconv.Itoa(i))
+	}
+	return anchors
+}
+
+// writeLicenses writes the licenses page of m.
+func (g *generator) writeLicenses(m *internal.Module) (err error) {
+	defer derrors.Wrap(&err, "writeLicenses(%q)", m.ModulePath)
+
+	dir := g.moduleDir(m.ModulePath)
+	page := &LicensesPage{basePage: g.newBasePage(dir, "Licenses", m)}
+	anchors := g.licenseAnchors[m.ModulePath]
+	for _, l := range m.Licenses {
+		page.Licenses = append(page.Licenses, &License{
+			Anchor:   anchors[l.FilePath],
+			Types:    strings.Join(l.Types, ", "),
+			FilePath: l.FilePath,
+			Contents: string(l.Contents),
+		})
+	}
+	return g.writePage(path.Join(dir, licensesFile), "licenses.tmpl", page)
+}
+
+// writeModules writes the index page of a site with several modules.
+func (g *generator) writeModules(ms []*internal.Module) (err error) {
+	defer derrors.Wrap(&err, "writeModules")
+
+	page := &ModulesPage{basePage: g.newBasePage("", "Modules", nil)}
+	for _, m := range ms {
+		page.Modules = append(page.Modules, &ModuleLink{
+			ModulePath: m.ModulePath,
+			Version:    m.Version,
+			URL:        path.Join(g.moduleDir(m.ModulePath), indexFile),
+		})
+	}
+	sort.Slice(page.Modules, func(i, j int) bool { return page.Modules[i].ModulePath < page.Modules[j].ModulePath })
+	return g.writePage(indexFile, "modules.tmpl", page)
+}
+
+// writePage executes the template with the given name on page, and writes the
+// result to the site file name.
+func (g *generator) writePage(name, tmpl string, page interface{}) error {
+	var buf bytes.Buffer
+	if err := g.templates[tmpl].Execute(&buf, page); err != nil {
+		return fmt.Errorf("executing %s: %v", tmpl, err)
+	}
+	filename := filepath.Join(g.cfg.OutDir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, buf.Bytes(), 0644)
+}
+
+// copyDir copies the files in the directory src, recursively, to dst.
+func copyDir(src, dst string) (err error) {
+	defer derrors.Wrap(&err, "copyDir(%q, %q)", src, dst)
+
+	return filepath.Walk(src, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		return copyFile(p, target)
+	})
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,225 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package staticsite
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/safehtml/template"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/fetch"
+	"golang.org/x/pkgsite/internal/godoc/dochtml"
+	"golang.org/x/pkgsite/internal/source"
+	"golang.org/x/pkgsite/internal/testing/testhelper"
+)
+
+var staticPath = template.TrustedSourceFromConstant("../../content/static")
+
+func TestGenerate(t *testing.T) {
+	ctx := context.Background()
+	dochtml.LoadTemplates(template.TrustedSourceJoin(staticPath, template.TrustedSourceFromConstant("html/doc")))
+
+	m := fetchLocalModule(t, map[string]string{
+		"go.mod":    "module example.com/m\n",
+		"LICENSE":   testhelper.BSD0License,
+		"README.md": "# The m module\n",
+		"m.go": `// Package m is the root package.
+package m
+
+import (
+	"io"
+
+	"example.com/m/a/b"
+)
+
+// F reads a b.T.
+func F(r io.Reader) b.T { return 0 }
+`,
+		"a/b/b.go": `// Package b is nested.
+package b
+
+// T is a type.
+type T int
+`,
+	})
+	outDir, err := ioutil.TempDir("", "staticsite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outDir)
+	if err := Generate(ctx, m, Config{OutDir: outDir, StaticPath: staticPath}); err != nil {
+		t.Fatal(err)
+	}
+
+	checkFiles(t, outDir, []siteFile{
+		{
+			file: "index.html",
+			want: []string{
+				`<link href="static/css/stylesheet.css" rel="stylesheet">`,
+				`id="readme-the-m-module"`,
+				`href="https://pkg.go.dev/io#Reader"`,
+				`href="a/b/index.html#T"`,
+				`<td><a href="a/b/index.html">a/b</a></td>`,
+				`<td>Package b is nested.</td>`,
+				`<a href="licenses.html#lic-0">0BSD</a>`,
+			},
+		},
+		{
+			file: "a/b/index.html",
+			want: []string{
+				`<link href="../../static/css/stylesheet.css" rel="stylesheet">`,
+				`<a href="../../index.html">example.com/m</a> / <a href="../index.html">example.com/m/a</a> /`,
+				`<h1>package b</h1>`,
+				`<a href="../../licenses.html">Licenses</a>`,
+			},
+		},
+		{
+			file: "a/index.html",
+			want: []string{`<h1>example.com/m/a</h1>`, `<td><a href="b/index.html">b</a></td>`},
+		},
+		{
+			file: "licenses.html",
+			want: []string{`<section class="License" id="lic-0">`, `Source: LICENSE`},
+		},
+		{
+			file: "static/css/stylesheet.css",
+		},
+	})
+}
+
+func TestGenerateModules(t *testing.T) {
+	ctx := context.Background()
+	dochtml.LoadTemplates(template.TrustedSourceJoin(staticPath, template.TrustedSourceFromConstant("html/doc")))
+
+	m := fetchLocalModule(t, map[string]string{
+		"go.mod":  "module example.com/m\n",
+		"LICENSE": testhelper.BSD0License,
+		"m.go": `// Package m uses another module.
+package m
+
+import "example.com/n/p"
+
+// F returns a p.T.
+func F() p.T { return 0 }
+`,
+	})
+	n := fetchLocalModule(t, map[string]string{
+		"go.mod":  "module example.com/n\n",
+		"LICENSE": testhelper.BSD0License,
+		"p/p.go": `// Package p is in another module.
+package p
+
+// T is a type.
+type T int
+`,
+	})
+	outDir, err := ioutil.TempDir("", "staticsite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outDir)
+	if err := GenerateModules(ctx, []*internal.Module{m, n}, Config{OutDir: outDir, StaticPath: staticPath}); err != nil {
+		t.Fatal(err)
+	}
+
+	checkFiles(t, outDir, []siteFile{
+		{
+			file: "index.html",
+			want: []string{
+				`<link href="static/css/stylesheet.css" rel="stylesheet">`,
+				`<td><a href="example.com/m/index.html">example.com/m</a></td>`,
+				`<td><a href="example.com/n/index.html">example.com/n</a></td>`,
+			},
+		},
+		{
+			file: "example.com/m/index.html",
+			want: []string{
+				`<link href="../../static/css/stylesheet.css" rel="stylesheet">`,
+				`href="../n/p/index.html#T"`,
+				`<a href="licenses.html#lic-0">0BSD</a>`,
+			},
+		},
+		{
+			file: "example.com/n/p/index.html",
+			want: []string{
+				`<link href="../../../static/css/stylesheet.css" rel="stylesheet">`,
+				`<a href="../index.html">example.com/n</a> /`,
+				`<a href="../licenses.html">Licenses</a>`,
+			},
+		},
+		{
+			file: "example.com/n/licenses.html",
+			want: []string{`<section class="License" id="lic-0">`},
+		},
+		{
+			file: "static/css/stylesheet.css",
+		},
+	})
+	if _, err := os.Stat(filepath.Join(outDir, "example.com", "m", "static")); !os.IsNotExist(err) {
+		t.Errorf("static files copied to module directory: %v", err)
+	}
+}
+
+// fetchLocalModule fetches the module whose files are given by their
+// contents.
+func fetchLocalModule(t *testing.T, files map[string]string) *internal.Module {
+	t.Helper()
+	dir, err := testhelper.CreateTestDirectory(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	res := fetch.FetchLocalModule(context.Background(), "", dir, source.NewClientForTesting())
+	if res.Error != nil {
+		t.Fatal(res.Error)
+	}
+	return res.Module
+}
+
+// A siteFile is a file of a generated site, with strings it must contain.
+type siteFile struct {
+	file string
+	want []string
+}
+
+// checkFiles checks that the site in outDir has the given files.
+func checkFiles(t *testing.T, outDir string, files []siteFile) {
+	t.Helper()
+	for _, test := range files {
+		t.Run(test.file, func(t *testing.T) {
+			got, err := ioutil.ReadFile(filepath.Join(outDir, filepath.FromSlash(test.file)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range test.want {
+				if !strings.Contains(string(got), w) {
+					t.Errorf("%s does not contain %q", test.file, w)
+				}
+			}
+		})
+	}
+}
+
+func TestRelativeURL(t *testing.T) {
+	for _, test := range []struct {
+		fromDir, target, want string
+	}{
+		{"", "index.html", "index.html"},
+		{"a", "index.html", "../index.html"},
+		{"a/b", "c/index.html", "../../c/index.html"},
+		{"a", "a/b/index.html", "b/index.html"},
+		{"a/b", "a/index.html", "../index.html"},
+		{"a/b", "a/bc/index.html", "../bc/index.html"},
+	} {
+		if got := relativeURL(test.fromDir, test.target); got != test.want {
+			t.Errorf("relativeURL(%q, %q) = %q, want %q", test.fromDir, test.target, got, test.want)
+		}
+	}
+}
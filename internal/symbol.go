@@ -0,0 +1,40 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import "strings"
+
+// A SymbolKind is the kind of an exported identifier. The kinds are the
+// values of the data-kind attribute of the identifier's anchor in the
+// documentation HTML.
+type SymbolKind string
+
+const (
+	SymbolKindConstant SymbolKind = "constant"
+	SymbolKindVariable SymbolKind = "variable"
+	SymbolKindFunction SymbolKind = "function"
+	SymbolKindType     SymbolKind = "type"
+	SymbolKindMethod   SymbolKind = "method"
+	SymbolKindField    SymbolKind = "field"
+)
+
+// Symbol is an exported identifier of a package.
+type Symbol struct {
+	// Name is the name of the identifier. Methods, struct fields and
+	// interface methods are qualified by their type name, as in "T.M". It is
+	// also the ID of the identifier's anchor in the documentation HTML.
+	Name string
+	Kind SymbolKind
+	// Synopsis is the first sentence of the identifier's doc comment.
+	Synopsis string
+	// Deprecated reports whether the identifier's doc comment has a
+	// paragraph that begins with "Deprecated:".
+	Deprecated bool
+}
+
+// Identifier returns the unqualified name of s, as in "M" for "T.M".
+func (s *Symbol) Identifier() string {
+	return s.Name[strings.LastIndexByte(s.Name, '.')+1:]
+}
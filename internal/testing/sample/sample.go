@@ -112,6 +112,7 @@ func Module(modulePath, version string, suffixes ...string) *internal.Module {
 		} else {
 			u := UnitForPackage(lp.Path, modulePath, version, lp.Name, lp.IsRedistributable)
 			m.Units[0].Documentation = u.Documentation
+			m.Units[0].BuildContexts = u.BuildContexts
 			m.Units[0].Name = u.Name
 		}
 	}
@@ -140,12 +141,13 @@ func UnitForModuleRoot(m *internal.ModuleInfo) *internal.Unit {
 func UnitForPackage(path, modulePath, version, name string, isRedistributable bool) *internal.Unit {
 	return &internal.Unit{
 		UnitMeta: *UnitMeta(path, modulePath, version, name, isRedistributable),
-		Documentation: &internal.Documentation{
+		Documentation: []*internal.Documentation{{
 			Synopsis: Synopsis,
 			Source:   DocumentationSource,
 			GOOS:     GOOS,
 			GOARCH:   GOARCH,
-		},
+		}},
+		BuildContexts:   []internal.BuildContext{{GOOS: GOOS, GOARCH: GOARCH}},
 		LicenseContents: Licenses,
 		Imports:         Imports,
 		NumImports:      len(Imports),
@@ -50,8 +50,16 @@ func (um *UnitMeta) IsModule() bool {
 // contains other units, licenses and/or READMEs."
 type Unit struct {
 	UnitMeta
-	Readme          *Readme
-	Documentation   *Documentation
+	Readme *Readme
+	// Documentation holds the documentation for the unit. When the unit is
+	// fetched from a module, there is one entry for each build context in
+	// BuildContexts. When the unit is read from a DataSource, it holds only
+	// the documentation for the requested build context, if any, unless
+	// WithAllDocumentation is requested.
+	Documentation []*Documentation
+	// BuildContexts are the build contexts for which the unit has
+	// documentation, in the order given by CompareBuildContexts.
+	BuildContexts   []BuildContext
 	Subdirectories  []*PackageMeta
 	Imports         []string
 	LicenseContents []*licenses.License
@@ -68,6 +76,36 @@ type Documentation struct {
 	GOARCH   string
 	Synopsis string
 	Source   []byte // encoded ast.Files; see godoc.Package.Encode
+
+	// Symbols are the exported identifiers of the package in this build
+	// context. They are stored for search, and are not read back with the
+	// rest of the documentation.
+	Symbols []*Symbol
+
+	// Coverage is the documentation coverage of the package in this build
+	// context. Like Symbols, it is not read back with the rest of the
+	// documentation.
+	Coverage *DocCoverage
+}
+
+// ForBuildContext returns a shallow copy of u whose Documentation holds only the
+// documentation for bc, if there is any; see DocumentationForBuildContext. If
+// fields contains WithAllDocumentation, the copy instead holds the
+// documentation for every build context that matches bc.
+func (u *Unit) ForBuildContext(bc BuildContext, fields FieldSet) *Unit {
+	u2 := *u
+	u2.Documentation = nil
+	if fields&WithAllDocumentation != 0 {
+		u2.Documentation = DocumentationMatching(u.Documentation, bc)
+	} else if d := DocumentationForBuildContext(u.Documentation, bc); d != nil {
+		u2.Documentation = []*Documentation{d}
+	}
+	return &u2
+}
+
+// BuildContext returns the build context of the documentation.
+func (d *Documentation) BuildContext() BuildContext {
+	return BuildContext{GOOS: d.GOOS, GOARCH: d.GOARCH}
 }
 
 // Readme is a README at the specified filepath.
@@ -112,4 +150,8 @@ const (
 	WithMain FieldSet = 1 << iota
 	WithImports
 	WithLicenses
+	// WithAllDocumentation requests the documentation for every build context
+	// matching the one passed to GetUnit, instead of only the best match. It
+	// has no effect without WithMain.
+	WithAllDocumentation
 )
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"golang.org/x/mod/semver"
 )
@@ -41,6 +42,50 @@ func IsPseudo(v string) bool {
 	return strings.Count(v, "-") >= 2 && pseudoVersionRE.MatchString(v)
 }
 
+// Pseudo returns the pseudo-version for the commit with the given time and
+// revision identifier (conventionally the first 12 characters of the commit
+// hash). major is the major version of the module, such as "v2", and
+// defaults to "v0". older is the highest semantic version tagged on an
+// ancestor of the commit, or "" if there is none.
+// Modified from src/cmd/go/internal/modfetch.
+func Pseudo(major, older string, t time.Time, rev string) string {
+	if major == "" {
+		major = "v0"
+	}
+	segment := fmt.Sprintf("%s-%s", t.UTC().Format("20060102150405"), rev)
+	build := semver.Build(older)
+	older = semver.Canonical(older)
+	if older == "" {
+		// vX.0.0-yyyymmddhhmmss-abcdefabcdef
+		return major + ".0.0-" + segment
+	}
+	if semver.Prerelease(older) != "" {
+		// vX.Y.Z-pre.0.yyyymmddhhmmss-abcdefabcdef
+		return older + ".0." + segment + build
+	}
+	// vX.Y.(Z+1)-0.yyyymmddhhmmss-abcdefabcdef
+	i := strings.LastIndex(older, ".") + 1
+	return older[:i] + incDecimal(older[i:]) + "-0." + segment + build
+}
+
+// incDecimal returns the decimal string incremented by 1.
+func incDecimal(decimal string) string {
+	// Scan right to left turning 9s to 0s until you find a digit to increment.
+	digits := []byte(decimal)
+	i := len(digits) - 1
+	for ; i >= 0 && digits[i] == '9'; i-- {
+		digits[i] = '0'
+	}
+	if i >= 0 {
+		digits[i]++
+	} else {
+		// digits is all zeros
+		digits[0] = '1'
+		digits = append(digits, '0')
+	}
+	return string(digits)
+}
+
 // ParseType returns the Type of a given a version.
 func ParseType(version string) (Type, error) {
 	if !semver.IsValid(version) {
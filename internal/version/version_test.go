@@ -6,6 +6,7 @@ package version
 
 import (
 	"testing"
+	"time"
 
 	"golang.org/x/mod/semver"
 )
@@ -151,3 +152,26 @@ func TestParseVersionType(t *testing.T) {
 		})
 	}
 }
+
+func TestPseudo(t *testing.T) {
+	tm := time.Date(2020, 9, 23, 17, 4, 5, 0, time.UTC)
+	const rev = "0123456789ab"
+	for _, test := range []struct {
+		major, older, want string
+	}{
+		{"", "", "v0.0.0-20200923170405-0123456789ab"},
+		{"v2", "", "v2.0.0-20200923170405-0123456789ab"},
+		{"v1", "v1.2.3", "v1.2.4-0.20200923170405-0123456789ab"},
+		{"v1", "v1.2.9", "v1.2.10-0.20200923170405-0123456789ab"},
+		{"v1", "v1.3.0-rc.1", "v1.3.0-rc.1.0.20200923170405-0123456789ab"},
+		{"v2", "v2.0.0+incompatible", "v2.0.1-0.20200923170405-0123456789ab+incompatible"},
+	} {
+		got := Pseudo(test.major, test.older, tm, rev)
+		if got != test.want {
+			t.Errorf("Pseudo(%q, %q) = %q, want %q", test.major, test.older, got, test.want)
+		}
+		if !IsPseudo(got) {
+			t.Errorf("IsPseudo(%q) = false, want true", got)
+		}
+	}
+}
@@ -191,6 +191,14 @@ func (f *Fetcher) fetchAndInsertModule(ctx context.Context, modulePath, requeste
 		return ft
 	}
 	log.Infof(ctx, "db.InsertModule succeeded for %s@%s", ft.ModulePath, ft.RequestedVersion)
+
+	// The symbol history only annotates the documentation, so failing to
+	// update it does not fail the fetch.
+	start = time.Now()
+	if err := updateSymbolHistory(ctx, f.DB, ft.Module); err != nil {
+		log.Error(ctx, err)
+	}
+	ft.timings["worker.updateSymbolHistory"] = time.Since(start)
 	return ft
 }
 
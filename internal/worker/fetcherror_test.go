@@ -418,7 +418,7 @@ func checkPackage(ctx context.Context, t *testing.T, pkgPath string) {
 		t.Fatalf("testDB.GetUnitMeta(%q, %q, %q): isPackage = false; want = true",
 			pkgPath, internal.UnknownModulePath, sample.VersionString)
 	}
-	dir, err := testDB.GetUnit(ctx, um, internal.WithMain)
+	dir, err := testDB.GetUnit(ctx, um, internal.WithMain, internal.BuildContextAll)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -100,11 +100,11 @@ func TestReFetch(t *testing.T) {
 			Filepath: "bar/README.md",
 			Contents: "This is a readme",
 		},
-		Documentation: &internal.Documentation{
+		Documentation: []*internal.Documentation{{
 			Synopsis: "Package bar",
 			GOOS:     "linux",
 			GOARCH:   "amd64",
-		},
+		}},
 		Subdirectories: []*internal.PackageMeta{
 			{
 				Path:              "github.com/valid/module_name/bar",
@@ -126,19 +126,19 @@ func TestReFetch(t *testing.T) {
 		t.Fatalf("testDB.GetUnitMeta(ctx, %q, %q) mismatch (-want +got):\n%s", want.ModulePath, want.Version, diff)
 	}
 
-	gotPkg, err := testDB.GetUnit(ctx, got, internal.WithMain)
+	gotPkg, err := testDB.GetUnit(ctx, got, internal.WithMain, internal.BuildContextAll)
 	if err != nil {
 		t.Fatal(err)
 	}
 	if diff := cmp.Diff(want, gotPkg,
 		cmp.AllowUnexported(source.Info{}),
-		cmpopts.IgnoreFields(internal.Unit{}, "Documentation"),
+		cmpopts.IgnoreFields(internal.Unit{}, "Documentation", "BuildContexts"),
 		cmpopts.IgnoreFields(licenses.Metadata{}, "Coverage", "OldCoverage"),
 		cmpopts.IgnoreFields(internal.UnitMeta{}, "HasGoMod")); diff != "" {
 		t.Errorf("mismatch on readme (-want +got):\n%s", diff)
 	}
 	if got, want := gotPkg.Documentation, want.Documentation; got == nil || want == nil {
-		if got != want {
+		if (got == nil) != (want == nil) {
 			t.Fatalf("mismatch on documentation: got: %v\nwant: %v", got, want)
 		}
 		return
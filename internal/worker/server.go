@@ -188,6 +188,11 @@ func (s *Server) Install(handle func(string, http.Handler)) {
 	// "before" query parameter.
 	handle("/repopulate-search-documents", rmw(s.errorHandler(s.handleRepopulateSearchDocuments)))
 
+	// manual: backfill-symbol-history rebuilds the symbol history of the
+	// package whose path follows the endpoint from all of its stored
+	// versions, for packages inserted before the history was recorded.
+	handle("/backfill-symbol-history/", http.StripPrefix("/backfill-symbol-history", rmw(s.errorHandler(s.handleBackfillSymbolHistory))))
+
 	// manual: clear-cache clears the redis cache.
 	handle("/clear-cache", rmw(s.errorHandler(s.clearCache)))
 
@@ -251,6 +256,21 @@ func (s *Server) handleRepopulateSearchDocuments(w http.ResponseWriter, r *http.
 	return nil
 }
 
+// handleBackfillSymbolHistory rebuilds the symbol history of the package
+// whose path is the request path.
+func (s *Server) handleBackfillSymbolHistory(w http.ResponseWriter, r *http.Request) error {
+	pkgPath := strings.TrimPrefix(r.URL.Path, "/")
+	if pkgPath == "" {
+		return &serverError{http.StatusBadRequest, errors.New("missing package path")}
+	}
+	n, err := backfillSymbolHistory(r.Context(), s.db, pkgPath)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "rebuilt the symbol history of %s from %d versions", pkgPath, n)
+	return nil
+}
+
 // handleFetch executes a fetch request and returns a http.StatusOK if the
 // status is not http.StatusInternalServerError, so that the task queue does
 // not retry fetching module versions that have a terminal error.
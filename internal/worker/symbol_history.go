@@ -0,0 +1,148 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"context"
+
+	"golang.org/x/mod/semver"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/godoc"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/version"
+)
+
+// updateSymbolHistory updates the symbol history of each package in m with
+// the symbols that were added in m's version. Pseudo-versions are skipped.
+func updateSymbolHistory(ctx context.Context, db *postgres.DB, m *internal.Module) (err error) {
+	defer derrors.Wrap(&err, "updateSymbolHistory(ctx, db, %q, %q)", m.ModulePath, m.Version)
+
+	if version.IsPseudo(m.Version) {
+		return nil
+	}
+	for _, u := range m.Units {
+		if !u.IsPackage() {
+			continue
+		}
+		if err := updatePackageSymbolHistory(ctx, db, u, m.ModulePath, m.Version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updatePackageSymbolHistory records vers as the version in which each
+// symbol of u that is not in the previous stored version of the package
+// appeared, unless the history already has an earlier version for it. The
+// symbols of u are those in its documentation for any build context.
+//
+// Since this is done for each version that is inserted, in any order, the
+// history ends up with the earliest stored version that contains each
+// symbol.
+func updatePackageSymbolHistory(ctx context.Context, db *postgres.DB, u *internal.Unit, modulePath, vers string) (err error) {
+	defer derrors.Wrap(&err, "updatePackageSymbolHistory(ctx, db, %q, %q, %q)", u.Path, modulePath, vers)
+
+	previous, err := db.GetPreviousSymbols(ctx, u.Path, modulePath, vers)
+	if err != nil {
+		return err
+	}
+	inPrevious := map[string]bool{}
+	for _, name := range previous {
+		inPrevious[name] = true
+	}
+	history, err := db.GetSymbolHistory(ctx, u.Path, modulePath)
+	if err != nil {
+		return err
+	}
+	changed := false
+	for _, d := range u.Documentation {
+		for _, s := range d.Symbols {
+			if inPrevious[s.Name] {
+				continue
+			}
+			if v, ok := history[s.Name]; ok && semver.Compare(v, vers) <= 0 {
+				continue
+			}
+			history[s.Name] = vers
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return db.UpsertSymbolHistory(ctx, u.Path, modulePath, history)
+}
+
+// backfillSymbolHistory replaces the symbol history of the package at pkgPath
+// with one computed from the documentation source of each of its stored
+// versions, as returned by GetVersionsForPath. It is for packages whose
+// versions were inserted before symbol history was recorded, or whose history
+// needs to be rebuilt. It returns the number of versions read.
+func backfillSymbolHistory(ctx context.Context, db *postgres.DB, pkgPath string) (_ int, err error) {
+	defer derrors.Wrap(&err, "backfillSymbolHistory(ctx, db, %q)", pkgPath)
+
+	versions, err := db.GetVersionsForPath(ctx, pkgPath)
+	if err != nil {
+		return 0, err
+	}
+	// versions are sorted in descending order, so go through them backwards
+	// to see the earliest version of each module first.
+	histories := map[string]map[string]string{}
+	n := 0
+	for i := len(versions) - 1; i >= 0; i-- {
+		mi := versions[i]
+		if version.IsPseudo(mi.Version) {
+			continue
+		}
+		names, err := symbolsFromSource(ctx, db, pkgPath, mi.ModulePath, mi.Version)
+		if err != nil {
+			return 0, err
+		}
+		history := histories[mi.ModulePath]
+		if history == nil {
+			history = map[string]string{}
+			histories[mi.ModulePath] = history
+		}
+		for _, name := range names {
+			if _, ok := history[name]; !ok {
+				history[name] = mi.Version
+			}
+		}
+		n++
+	}
+	for modulePath, history := range histories {
+		if err := db.UpsertSymbolHistory(ctx, pkgPath, modulePath, history); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
+// symbolsFromSource returns the names of the symbols of the package at
+// pkgPath in the given module version, read from its documentation source for
+// every build context.
+func symbolsFromSource(ctx context.Context, db *postgres.DB, pkgPath, modulePath, vers string) (_ []string, err error) {
+	defer derrors.Wrap(&err, "symbolsFromSource(ctx, db, %q, %q, %q)", pkgPath, modulePath, vers)
+
+	docs, err := db.GetDocumentation(ctx, pkgPath, modulePath, vers, internal.BuildContextAll, true)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, d := range docs {
+		if len(d.Source) == 0 {
+			continue
+		}
+		p, err := godoc.DecodePackage(d.Source)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range p.Symbols() {
+			names = append(names, s.Name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/godoc"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestUpdateSymbolHistory(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer postgres.ResetTestDB(testDB, t)
+
+	for _, v := range []struct {
+		version, src string
+	}{
+		// Insert out of order, to check that history follows semver order.
+		{"v1.2.0", "package foo\nfunc F() {}\ntype T struct{ A, B int }\n"},
+		{"v1.0.0", "package foo\nfunc F() {}\n"},
+		{"v1.1.0", "package foo\nfunc F() {}\ntype T struct{ A int }\nfunc G() {}\n"},
+		// Pseudo-versions are not part of the history.
+		{"v1.2.1-0.20190311183353-d8887717615a", "package foo\nfunc H() {}\n"},
+	} {
+		m := sample.Module(sample.ModulePath, v.version, sample.Suffix)
+		doc := m.Units[1].Documentation[0]
+		doc.Source, doc.Symbols = encodeSource(t, v.src)
+		if err := testDB.InsertModule(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+		if err := updateSymbolHistory(ctx, testDB, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := testDB.GetSymbolHistory(ctx, sample.PackagePath, sample.ModulePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"F":   "v1.0.0",
+		"G":   "v1.1.0",
+		"T":   "v1.1.0",
+		"T.A": "v1.1.0",
+		"T.B": "v1.2.0",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestBackfillSymbolHistory(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer postgres.ResetTestDB(testDB, t)
+
+	// Insert the versions without updating the history, as for modules
+	// processed before symbol history was recorded.
+	for _, v := range []struct {
+		version, src string
+	}{
+		{"v1.2.0", "package foo\nfunc F() {}\ntype T struct{ A, B int }\n"},
+		{"v1.0.0", "package foo\nfunc F() {}\n"},
+		{"v1.1.0", "package foo\nfunc F() {}\ntype T struct{ A int }\nfunc G() {}\n"},
+		{"v1.2.1-0.20190311183353-d8887717615a", "package foo\nfunc H() {}\n"},
+	} {
+		m := sample.Module(sample.ModulePath, v.version, sample.Suffix)
+		doc := m.Units[1].Documentation[0]
+		doc.Source, doc.Symbols = encodeSource(t, v.src)
+		if err := testDB.InsertModule(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	n, err := backfillSymbolHistory(ctx, testDB, sample.PackagePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("got %d versions read, want 3", n)
+	}
+	got, err := testDB.GetSymbolHistory(ctx, sample.PackagePath, sample.ModulePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"F":   "v1.0.0",
+		"G":   "v1.1.0",
+		"T":   "v1.1.0",
+		"T.A": "v1.1.0",
+		"T.B": "v1.2.0",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+// encodeSource returns the encoded documentation source and the symbols of a
+// package consisting of the single file src.
+func encodeSource(t *testing.T, src string) ([]byte, []*internal.Symbol) {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "foo.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := godoc.NewPackage(fset, sample.GOOS, sample.GOARCH, nil)
+	p.AddFile(f, true)
+	symbols := p.Symbols()
+	b, err := p.Encode(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b, symbols
+}
@@ -0,0 +1,21 @@
+-- Copyright 2021 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE symbol_history (
+    package_path text NOT NULL,
+    module_path text NOT NULL,
+    symbol text NOT NULL,
+    since_version text NOT NULL,
+    PRIMARY KEY (package_path, module_path, symbol)
+);
+
+COMMENT ON TABLE symbol_history IS
+'TABLE symbol_history contains the earliest version of a module in which each exported symbol of a package appeared.';
+
+COMMENT ON COLUMN symbol_history.symbol IS
+'COLUMN symbol is the name of the symbol. Methods and fields are qualified by their type name, as in "T.M".';
+
+END;
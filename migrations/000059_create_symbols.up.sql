@@ -0,0 +1,26 @@
+-- Copyright 2021 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE symbols (
+    unit_id    INTEGER NOT NULL REFERENCES units (id) ON DELETE CASCADE,
+    name       text NOT NULL,
+    identifier text NOT NULL,
+    kind       text NOT NULL,
+    synopsis   text NOT NULL,
+    PRIMARY KEY (unit_id, name)
+);
+COMMENT ON TABLE symbols IS
+'TABLE symbols contains the exported identifiers of each package, for symbol search.';
+COMMENT ON COLUMN symbols.name IS
+'COLUMN name is the name of the symbol. Methods and fields are qualified by their type name, as in "T.M".';
+COMMENT ON COLUMN symbols.identifier IS
+'COLUMN identifier is the unqualified name of the symbol, as in "M" for "T.M".';
+
+CREATE INDEX idx_symbols_lower_identifier ON symbols (lower(identifier));
+COMMENT ON INDEX idx_symbols_lower_identifier IS
+'INDEX idx_symbols_lower_identifier is used to search for symbols by name, ignoring case.';
+
+END;
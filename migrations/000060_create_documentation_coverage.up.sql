@@ -0,0 +1,22 @@
+-- Copyright 2021 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE documentation_coverage (
+    unit_id                 INTEGER NOT NULL PRIMARY KEY REFERENCES units (id) ON DELETE CASCADE,
+    has_package_doc         boolean NOT NULL,
+    num_exported            integer NOT NULL,
+    num_documented          integer NOT NULL,
+    undocumented            text[],
+    num_funcs               integer NOT NULL,
+    num_funcs_with_examples integer NOT NULL,
+    num_examples            integer NOT NULL
+);
+COMMENT ON TABLE documentation_coverage IS
+'TABLE documentation_coverage summarizes how much of the exported API of each package is documented, for its preferred build context.';
+COMMENT ON COLUMN documentation_coverage.undocumented IS
+'COLUMN undocumented holds the names of the exported identifiers that have no doc comment. Methods are qualified by their type name, as in "T.M".';
+
+END;
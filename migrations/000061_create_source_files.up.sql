@@ -0,0 +1,18 @@
+-- Copyright 2021 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE source_files (
+    module_id INTEGER NOT NULL REFERENCES modules (id) ON DELETE CASCADE,
+    path      text NOT NULL,
+    contents  bytea NOT NULL,
+    PRIMARY KEY (module_id, path)
+);
+COMMENT ON TABLE source_files IS
+'TABLE source_files contains the .go files of redistributable modules, for the source viewer.';
+COMMENT ON COLUMN source_files.path IS
+'COLUMN path is the path of the file relative to the module root.';
+
+END;
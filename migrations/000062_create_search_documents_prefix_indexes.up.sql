@@ -0,0 +1,15 @@
+-- Copyright 2021 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE INDEX idx_search_documents_lower_package_path_prefix ON search_documents (lower(package_path) text_pattern_ops);
+COMMENT ON INDEX idx_search_documents_lower_package_path_prefix IS
+'INDEX idx_search_documents_lower_package_path_prefix is used by autocomplete to find packages whose path starts with a prefix, ignoring case.';
+
+CREATE INDEX idx_search_documents_lower_name_prefix ON search_documents (lower(name) text_pattern_ops);
+COMMENT ON INDEX idx_search_documents_lower_name_prefix IS
+'INDEX idx_search_documents_lower_name_prefix is used by autocomplete to find packages whose name starts with a prefix, ignoring case.';
+
+END;
@@ -0,0 +1,11 @@
+-- Copyright 2021 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+DROP INDEX idx_paths_lower_path_trgm;
+DROP INDEX idx_search_documents_lower_name_trgm;
+DROP EXTENSION pg_trgm;
+
+END;
@@ -0,0 +1,17 @@
+-- Copyright 2021 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_paths_lower_path_trgm ON paths USING gin (lower(path) gin_trgm_ops);
+COMMENT ON INDEX idx_paths_lower_path_trgm IS
+'INDEX idx_paths_lower_path_trgm is used to suggest existing paths that are similar to a path that was not found, ignoring case.';
+
+CREATE INDEX idx_search_documents_lower_name_trgm ON search_documents USING gin (lower(name) gin_trgm_ops);
+COMMENT ON INDEX idx_search_documents_lower_name_trgm IS
+'INDEX idx_search_documents_lower_name_trgm is used to suggest corrections to the words of search queries that have no results.';
+
+END;
@@ -0,0 +1,32 @@
+-- Copyright 2021 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE go_mods (
+    module_id  INTEGER NOT NULL PRIMARY KEY REFERENCES modules (id) ON DELETE CASCADE,
+    go_version text NOT NULL,
+    replaces   jsonb NOT NULL,
+    excludes   jsonb NOT NULL
+);
+COMMENT ON TABLE go_mods IS
+'TABLE go_mods contains the directives of the go.mod file of each module version that has one. Its requirements are in the module_requires table.';
+COMMENT ON COLUMN go_mods.go_version IS
+'COLUMN go_version is the version of the go directive, or empty if there is none.';
+
+CREATE TABLE module_requires (
+    module_id            INTEGER NOT NULL REFERENCES modules (id) ON DELETE CASCADE,
+    required_module_path text NOT NULL,
+    required_version     text NOT NULL,
+    indirect             boolean NOT NULL,
+    PRIMARY KEY (module_id, required_module_path, required_version)
+);
+COMMENT ON TABLE module_requires IS
+'TABLE module_requires contains the require directives of the go.mod file of each module version.';
+COMMENT ON COLUMN module_requires.indirect IS
+'COLUMN indirect reports whether the requirement is marked with an "// indirect" comment.';
+
+CREATE INDEX idx_module_requires_required_module_path ON module_requires (required_module_path);
+
+END;
@@ -0,0 +1,28 @@
+-- Copyright 2021 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE go_mods ADD COLUMN retractions jsonb NOT NULL DEFAULT '[]';
+COMMENT ON COLUMN go_mods.retractions IS
+'COLUMN retractions holds the retract directives of the go.mod file.';
+
+CREATE TABLE module_retractions (
+    module_path       text NOT NULL,
+    low               text NOT NULL,
+    high              text NOT NULL,
+    low_sort_version  text NOT NULL,
+    high_sort_version text NOT NULL,
+    rationale         text NOT NULL,
+    module_id         INTEGER NOT NULL REFERENCES modules (id) ON DELETE CASCADE,
+    PRIMARY KEY (module_path, low, high)
+);
+COMMENT ON TABLE module_retractions IS
+'TABLE module_retractions contains the version ranges retracted by each module, taken from the go.mod file of its highest version, as the go command does.';
+COMMENT ON COLUMN module_retractions.low_sort_version IS
+'COLUMN low_sort_version holds the low version in the form of modules.sort_version, so that retracted module versions can be found by comparison.';
+COMMENT ON COLUMN module_retractions.module_id IS
+'COLUMN module_id is the ID of the module version whose go.mod file has the retract directive.';
+
+END;
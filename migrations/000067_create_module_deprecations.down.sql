@@ -0,0 +1,68 @@
+-- Copyright 2021 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+DROP FUNCTION popular_search(rawquery text, lim integer, off integer, redist_factor real, go_mod_factor real, filter text, deprecated_factor real);
+DROP TABLE module_deprecations;
+ALTER TABLE go_mods DROP COLUMN deprecation_comment;
+
+-- Restore the popular_search function of migration 000063.
+
+CREATE FUNCTION popular_search(rawquery text, lim integer, off integer, redist_factor real, go_mod_factor real, filter text) RETURNS SETOF search_result
+    LANGUAGE plpgsql
+    AS $$
+	DECLARE cur refcursor;
+	top search_result[];
+	res search_result;
+	last_idx INT;
+BEGIN
+	last_idx := lim+off;
+	top := array_fill(NULL::search_result, array[last_idx]);
+	OPEN cur FOR EXECUTE format($query$
+		SELECT
+			package_path,
+			module_path,
+			version,
+			commit_time,
+			imported_by_count,
+			(
+				-- default D, C, B, A weights are {0.1, 0.2, 0.4, 1.0}
+				CASE WHEN $4 = '' THEN 1 ELSE ts_rank('{0.1, 0.2, 1.0, 1.0}', tsv_search_tokens, $1) END *
+				ln(exp(1)+imported_by_count) *
+				CASE WHEN redistributable THEN 1 ELSE $2 END *
+				CASE WHEN COALESCE(has_go_mod, true) THEN 1 ELSE $3 END *
+				CASE WHEN $4 = '' OR tsv_search_tokens @@ $1 THEN 1 ELSE 0 END
+			) score
+			FROM search_documents
+			WHERE %s
+			ORDER BY imported_by_count DESC$query$, filter)
+		USING websearch_to_tsquery(rawquery), redist_factor, go_mod_factor, rawquery;
+	FETCH cur INTO res;
+	WHILE found LOOP
+		IF top[last_idx] IS NULL OR res.score >= top[last_idx].score THEN
+			FOR i IN 1..last_idx LOOP
+				IF top[i] IS NULL OR
+					(res.score > top[i].score) OR
+					(res.score = top[i].score AND res.commit_time > top[i].commit_time) OR
+					(res.score = top[i].score AND res.commit_time = top[i].commit_time AND
+					 res.package_path < top[i].package_path) THEN
+					top := (top[1:i-1] || res) || top[i:last_idx-1];
+					EXIT;
+				END IF;
+			END LOOP;
+		END IF;
+		IF top[last_idx].score > ln(exp(1)+res.imported_by_count) THEN
+			EXIT;
+		END IF;
+		FETCH cur INTO res;
+	END LOOP;
+	CLOSE cur;
+	RETURN QUERY SELECT * FROM UNNEST(top[off+1:last_idx])
+		WHERE package_path IS NOT NULL AND score > 0.1;
+END; $$;
+COMMENT ON FUNCTION popular_search(rawquery text, lim integer, off integer, redist_factor real, go_mod_factor real, filter text) IS
+'FUNCTION popular_search is used to generate results for search. It is implemented as a stored function, so that we can use a cursor to scan search documents procedurally, and stop scanning early, whenever our search results are provably correct. The filter is a boolean SQL expression over the columns of search_documents that is built by the search code from quoted literals; it must never contain user input verbatim.';
+
+END;
@@ -0,0 +1,85 @@
+-- Copyright 2021 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE go_mods ADD COLUMN deprecation_comment text;
+COMMENT ON COLUMN go_mods.deprecation_comment IS
+'COLUMN deprecation_comment is the text of the "Deprecated:" comment of the module directive, or NULL if the module is not deprecated.';
+
+CREATE TABLE module_deprecations (
+    module_path text NOT NULL PRIMARY KEY,
+    comment     text NOT NULL,
+    module_id   INTEGER NOT NULL REFERENCES modules (id) ON DELETE CASCADE
+);
+COMMENT ON TABLE module_deprecations IS
+'TABLE module_deprecations contains the modules that are deprecated by a "Deprecated:" comment in the go.mod file of their highest version, as the go command does.';
+COMMENT ON COLUMN module_deprecations.module_id IS
+'COLUMN module_id is the ID of the module version whose go.mod file deprecates the module.';
+
+-- Replace the popular_search function with a version that demotes deprecated
+-- modules. It is otherwise the same.
+
+CREATE FUNCTION popular_search(rawquery text, lim integer, off integer, redist_factor real, go_mod_factor real, filter text, deprecated_factor real) RETURNS SETOF search_result
+    LANGUAGE plpgsql
+    AS $$
+	DECLARE cur refcursor;
+	top search_result[];
+	res search_result;
+	last_idx INT;
+BEGIN
+	last_idx := lim+off;
+	top := array_fill(NULL::search_result, array[last_idx]);
+	OPEN cur FOR EXECUTE format($query$
+		SELECT
+			package_path,
+			module_path,
+			version,
+			commit_time,
+			imported_by_count,
+			(
+				-- default D, C, B, A weights are {0.1, 0.2, 0.4, 1.0}
+				CASE WHEN $4 = '' THEN 1 ELSE ts_rank('{0.1, 0.2, 1.0, 1.0}', tsv_search_tokens, $1) END *
+				ln(exp(1)+imported_by_count) *
+				CASE WHEN redistributable THEN 1 ELSE $2 END *
+				CASE WHEN COALESCE(has_go_mod, true) THEN 1 ELSE $3 END *
+				CASE WHEN EXISTS (
+					SELECT 1 FROM module_deprecations md
+					WHERE md.module_path = search_documents.module_path
+				) THEN $5 ELSE 1 END *
+				CASE WHEN $4 = '' OR tsv_search_tokens @@ $1 THEN 1 ELSE 0 END
+			) score
+			FROM search_documents
+			WHERE %s
+			ORDER BY imported_by_count DESC$query$, filter)
+		USING websearch_to_tsquery(rawquery), redist_factor, go_mod_factor, rawquery, deprecated_factor;
+	FETCH cur INTO res;
+	WHILE found LOOP
+		IF top[last_idx] IS NULL OR res.score >= top[last_idx].score THEN
+			FOR i IN 1..last_idx LOOP
+				IF top[i] IS NULL OR
+					(res.score > top[i].score) OR
+					(res.score = top[i].score AND res.commit_time > top[i].commit_time) OR
+					(res.score = top[i].score AND res.commit_time = top[i].commit_time AND
+					 res.package_path < top[i].package_path) THEN
+					top := (top[1:i-1] || res) || top[i:last_idx-1];
+					EXIT;
+				END IF;
+			END LOOP;
+		END IF;
+		IF top[last_idx].score > ln(exp(1)+res.imported_by_count) THEN
+			EXIT;
+		END IF;
+		FETCH cur INTO res;
+	END LOOP;
+	CLOSE cur;
+	RETURN QUERY SELECT * FROM UNNEST(top[off+1:last_idx])
+		WHERE package_path IS NOT NULL AND score > 0.1;
+END; $$;
+COMMENT ON FUNCTION popular_search(rawquery text, lim integer, off integer, redist_factor real, go_mod_factor real, filter text, deprecated_factor real) IS
+'FUNCTION popular_search is used to generate results for search. It is implemented as a stored function, so that we can use a cursor to scan search documents procedurally, and stop scanning early, whenever our search results are provably correct. The filter is a boolean SQL expression over the columns of search_documents that is built by the search code from quoted literals; it must never contain user input verbatim.';
+
+DROP FUNCTION popular_search(rawquery text, lim integer, off integer, redist_factor real, go_mod_factor real, filter text);
+
+END;
@@ -0,0 +1,11 @@
+-- Copyright 2021 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE symbols ADD COLUMN deprecated boolean NOT NULL DEFAULT false;
+COMMENT ON COLUMN symbols.deprecated IS
+'COLUMN deprecated reports whether the doc comment of the symbol has a paragraph that begins with "Deprecated:".';
+
+END;